@@ -0,0 +1,129 @@
+package business
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// maxConcurrentLogFetches is the number of container logs fetched at the same time for an aggregated log
+const maxConcurrentLogFetches = 10
+
+// AggregatedLog reports the log entries of the containers of several pods, merged in time order
+type AggregatedLog struct {
+	Entries []LogEntry `json:"entries"`
+
+	// Errors fetching the logs, by pod/container. The entries of the other containers are still reported.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// GetWorkloadLogs returns the logs of all the containers of the pods of a workload, the istio-proxy included.
+// With a container in the options, only the logs of the containers with that name are returned.
+func (in *WorkloadService) GetWorkloadLogs(namespace, workloadName, workloadType string, opts *LogOptions) (*AggregatedLog, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetWorkloadLogs")
+	defer promtimer.ObserveNow(&err)
+
+	// An invalid filter is reported before fetching the logs of each container
+	if _, err = opts.Filter.entryParser(); err != nil {
+		return nil, err
+	}
+
+	var workload *models.Workload
+	if workload, err = fetchWorkload(in.businessLayer, namespace, workloadName, workloadType); err != nil {
+		return nil, err
+	}
+	return in.getAggregatedLogs(namespace, workload.Pods, opts), nil
+}
+
+// GetAppLogs returns the logs of all the containers of the pods of the workloads of an app, the istio-proxy included.
+// With a container in the options, only the logs of the containers with that name are returned.
+func (in *AppService) GetAppLogs(namespace, appName string, opts *LogOptions) (*AggregatedLog, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "AppService", "GetAppLogs")
+	defer promtimer.ObserveNow(&err)
+
+	// An invalid filter is reported before fetching the logs of each container
+	if _, err = opts.Filter.entryParser(); err != nil {
+		return nil, err
+	}
+
+	var apps namespaceApps
+	if apps, err = fetchNamespaceApps(in.businessLayer, namespace, appName); err != nil {
+		return nil, err
+	}
+	app, ok := apps[appName]
+	if !ok {
+		err = kubernetes.NewNotFound(appName, "Kiali", "App")
+		return nil, err
+	}
+	pods := models.Pods{}
+	for _, workload := range app.Workloads {
+		pods = append(pods, workload.Pods...)
+	}
+	return in.businessLayer.Workload.getAggregatedLogs(namespace, pods, opts), nil
+}
+
+// getAggregatedLogs fetches the logs of the containers of the pods concurrently and merges them in time order.
+// The tail lines are applied to the logs of each container, then to the merged entries.
+func (in *WorkloadService) getAggregatedLogs(namespace string, pods models.Pods, opts *LogOptions) *AggregatedLog {
+	aggregated := &AggregatedLog{Entries: []LogEntry{}}
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	semaphore := make(chan struct{}, maxConcurrentLogFetches)
+
+	for _, pod := range pods {
+		containers := append(append([]*models.ContainerInfo{}, pod.Containers...), pod.IstioContainers...)
+		for _, container := range containers {
+			if opts.Container != "" && opts.Container != container.Name {
+				continue
+			}
+			wg.Add(1)
+			go func(podName, containerName string) {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				containerOpts := *opts
+				containerOpts.Container = containerName
+				podLog, err := in.getParsedLogs(namespace, podName, &containerOpts)
+
+				mutex.Lock()
+				defer mutex.Unlock()
+				if err != nil {
+					log.Debugf("Error fetching the logs of container [%s] of pod [%s/%s]: %v", containerName, namespace, podName, err)
+					if aggregated.Errors == nil {
+						aggregated.Errors = map[string]string{}
+					}
+					aggregated.Errors[podName+"/"+containerName] = err.Error()
+					return
+				}
+				for _, entry := range podLog.Entries {
+					entry.Pod = podName
+					entry.Container = containerName
+					aggregated.Entries = append(aggregated.Entries, entry)
+				}
+			}(pod.Name, container.Name)
+		}
+	}
+	wg.Wait()
+
+	entries := aggregated.Entries
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].time.Equal(entries[j].time) {
+			return entries[i].time.Before(entries[j].time)
+		}
+		if entries[i].Pod != entries[j].Pod {
+			return entries[i].Pod < entries[j].Pod
+		}
+		return entries[i].Container < entries[j].Container
+	})
+	if opts.TailLines != nil && len(entries) > int(*opts.TailLines) {
+		aggregated.Entries = entries[len(entries)-int(*opts.TailLines):]
+	}
+	return aggregated
+}
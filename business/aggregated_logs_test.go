@@ -0,0 +1,83 @@
+package business
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestGetAggregatedLogs(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	mockContainerLogs(k8s, "reviews-v1-1", "reviews",
+		"2018-01-02T03:34:28.100000000Z INFO reviews-1 #1\n2018-01-02T03:34:30.000000000Z ERROR reviews-1 #2\n")
+	mockContainerLogs(k8s, "reviews-v1-1", kubernetes.IstioProxyContainerName,
+		"2018-01-02T03:34:28.200000000Z "+fakeAccessLog+"\n")
+	mockContainerLogs(k8s, "reviews-v1-2", "reviews",
+		"2018-01-02T03:34:28.150000000Z INFO reviews-2 #1\n")
+	k8s.On("GetPodLogs", "bookinfo", "reviews-v1-2", mock.MatchedBy(func(opts *core_v1.PodLogOptions) bool {
+		return opts.Container == kubernetes.IstioProxyContainerName
+	})).Return((*kubernetes.PodLogs)(nil), errors.New("container is waiting to start"))
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	pods := models.Pods{fakeLogsPod("reviews-v1-1"), fakeLogsPod("reviews-v1-2")}
+	layer := NewWithBackends(k8s, nil, nil)
+	logs := layer.Workload.getAggregatedLogs("bookinfo", pods, &LogOptions{PodLogOptions: core_v1.PodLogOptions{Timestamps: true}})
+	assert.Len(logs.Entries, 4)
+	assert.Equal([]string{"reviews-v1-1/reviews", "reviews-v1-2/reviews", "reviews-v1-1/istio-proxy", "reviews-v1-1/reviews"}, logSources(logs.Entries))
+	assert.Equal("ERROR", logs.Entries[3].Severity)
+	assert.Equal(map[string]string{"reviews-v1-2/istio-proxy": "container is waiting to start"}, logs.Errors)
+
+	// The tail lines apply to the merged entries
+	tailLines := int64(2)
+	logs = layer.Workload.getAggregatedLogs("bookinfo", pods, &LogOptions{PodLogOptions: core_v1.PodLogOptions{Timestamps: true, TailLines: &tailLines}})
+	assert.Equal([]string{"reviews-v1-1/istio-proxy", "reviews-v1-1/reviews"}, logSources(logs.Entries))
+
+	// Only the containers with the name of the options are read
+	logs = layer.Workload.getAggregatedLogs("bookinfo", pods, &LogOptions{PodLogOptions: core_v1.PodLogOptions{Timestamps: true, Container: "reviews"}})
+	assert.Equal([]string{"reviews-v1-1/reviews", "reviews-v1-2/reviews", "reviews-v1-1/reviews"}, logSources(logs.Entries))
+	assert.Empty(logs.Errors)
+
+	filter := LogFilter{Text: "reviews-[0-9] #1", Regex: true}
+	logs = layer.Workload.getAggregatedLogs("bookinfo", pods, &LogOptions{PodLogOptions: core_v1.PodLogOptions{Timestamps: true}, Filter: filter})
+	assert.Equal([]string{"reviews-v1-1/reviews", "reviews-v1-2/reviews"}, logSources(logs.Entries))
+
+	// Access logs are filtered on their parsed fields
+	filter = LogFilter{Fields: []string{"code>=500"}}
+	logs = layer.Workload.getAggregatedLogs("bookinfo", pods, &LogOptions{PodLogOptions: core_v1.PodLogOptions{Timestamps: true}, Filter: filter})
+	assert.Equal([]string{"reviews-v1-1/istio-proxy"}, logSources(logs.Entries))
+}
+
+func mockContainerLogs(k8s *kubetest.K8SClientMock, pod, container, logs string) {
+	k8s.On("GetPodLogs", "bookinfo", pod, mock.MatchedBy(func(opts *core_v1.PodLogOptions) bool {
+		return opts.Container == container
+	})).Return(&kubernetes.PodLogs{Logs: logs}, nil)
+}
+
+func fakeLogsPod(name string) *models.Pod {
+	return &models.Pod{
+		Name:            name,
+		Containers:      []*models.ContainerInfo{{Name: "reviews"}},
+		IstioContainers: []*models.ContainerInfo{{Name: kubernetes.IstioProxyContainerName}},
+	}
+}
+
+func logSources(entries []LogEntry) []string {
+	sources := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		sources = append(sources, entry.Pod+"/"+entry.Container)
+	}
+	return sources
+}
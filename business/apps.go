@@ -167,11 +167,41 @@ func castAppDetails(services []core_v1.Service, ws models.Workloads) namespaceAp
 	return allEntities
 }
 
+// addArgoRolloutServices adds to the apps the canary and stable services of their Argo Rollouts, as the selector of these
+// services is managed by Argo Rollouts and it may not include the app label
+func addArgoRolloutServices(apps namespaceApps, services []core_v1.Service) {
+	for _, appEntities := range apps {
+		for _, w := range appEntities.Workloads {
+			if w.ArgoRollout == nil {
+				continue
+			}
+			for _, name := range w.ArgoRollout.Services() {
+				found := false
+				for _, service := range appEntities.Services {
+					if service.Name == name {
+						found = true
+						break
+					}
+				}
+				if found {
+					continue
+				}
+				for _, service := range services {
+					if service.Name == name {
+						appEntities.Services = append(appEntities.Services, service)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
 // Helper method to fetch all applications for a given namespace.
 // Optionally if appName parameter is provided, it filters apps for that name.
 // Return an error on any problem.
 func fetchNamespaceApps(layer *Layer, namespace string, appName string) (namespaceApps, error) {
-	var services []core_v1.Service
+	var services, nsServices []core_v1.Service
 	var ws models.Workloads
 	cfg := config.Get()
 
@@ -199,6 +229,7 @@ func fetchNamespaceApps(layer *Layer, namespace string, appName string) (namespa
 		} else {
 			services, err = layer.k8s.GetServices(namespace, nil)
 		}
+		nsServices = services
 		if appName != "" {
 			selector := labels.Set(map[string]string{cfg.IstioLabels.AppLabelName: appName}).AsSelector()
 			services = kubernetes.FilterServicesForSelector(selector, services)
@@ -225,5 +256,7 @@ func fetchNamespaceApps(layer *Layer, namespace string, appName string) (namespa
 		return nil, err
 	}
 
-	return castAppDetails(services, ws), nil
+	apps := castAppDetails(services, ws)
+	addArgoRolloutServices(apps, nsServices)
+	return apps, nil
 }
@@ -0,0 +1,307 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// Scopes of the sources allowed by a generated AuthorizationPolicy
+const (
+	PrincipalSourceScope = "principal"
+	NamespaceSourceScope = "namespace"
+)
+
+// policySource is the traffic observed from a source identity
+type policySource struct {
+	// methods by path, for classified HTTP requests
+	operations map[string]map[string]bool
+	// HTTP requests not classified as "METHOD /path" can't be restricted
+	anyOperation bool
+	// services called over TCP, whose ports are allowed
+	tcpServices map[string]bool
+}
+
+// GenerateAuthorizationPolicy returns an ALLOW AuthorizationPolicy for a workload that only allows the traffic observed
+// during the lookback duration: the source principals, or namespaces, and the methods and paths or TCP ports they used.
+// HTTP requests are classified by the request_operation label, so methods and paths are only restricted when it is
+// reported as "METHOD /path". The policy is not created, but compared with the existing one of the same name.
+func (in *IstioConfigService) GenerateAuthorizationPolicy(namespace, workload, lookback, sourceScope string, queryTime time.Time) (models.AuthorizationPolicyProposal, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "GenerateAuthorizationPolicy")
+	defer promtimer.ObserveNow(&err)
+
+	proposal := models.AuthorizationPolicyProposal{Warnings: []string{}}
+	if in.prom == nil {
+		err = errors.New("prometheus is required to generate an authorization policy from the observed traffic")
+		return proposal, err
+	}
+	if sourceScope != PrincipalSourceScope && sourceScope != NamespaceSourceScope {
+		err = errors2.NewBadRequest(fmt.Sprintf("source scope not supported: %s", sourceScope))
+		return proposal, err
+	}
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return proposal, err
+	}
+	var wk *models.Workload
+	if wk, err = in.businessLayer.Workload.GetWorkload(namespace, workload, "", false); err != nil {
+		return proposal, err
+	}
+	if len(wk.Labels) == 0 {
+		err = errors2.NewBadRequest(fmt.Sprintf("workload [%s] has no labels to select it", workload))
+		return proposal, err
+	}
+
+	rates, err := in.prom.GetWorkloadInboundTraffic(namespace, workload, lookback, queryTime)
+	if err != nil {
+		return proposal, err
+	}
+
+	sources := map[string]*policySource{}
+	for _, sample := range rates {
+		srcNamespace := string(sample.Metric["source_workload_namespace"])
+		srcWorkload := string(sample.Metric["source_workload"])
+		principal := strings.TrimPrefix(string(sample.Metric["source_principal"]), "spiffe://")
+		if principal == "unknown" {
+			principal = ""
+		}
+		// Sources are identified by the mTLS certificate, so traffic without it is denied
+		if principal == "" {
+			proposal.Warnings = appendUnique(proposal.Warnings, fmt.Sprintf("Traffic without mTLS identity from workload [%s] of namespace [%s] will be denied", srcWorkload, srcNamespace))
+			continue
+		}
+
+		key := principal
+		if sourceScope == NamespaceSourceScope {
+			key = srcNamespace
+		}
+		src, found := sources[key]
+		if !found {
+			src = &policySource{operations: map[string]map[string]bool{}, tcpServices: map[string]bool{}}
+			sources[key] = src
+		}
+
+		if string(sample.Metric["request_protocol"]) == "tcp" {
+			src.tcpServices[string(sample.Metric["destination_service_name"])] = true
+			continue
+		}
+		method, path, ok := parseRequestOperation(string(sample.Metric["request_operation"]))
+		if !ok {
+			src.anyOperation = true
+			continue
+		}
+		if src.operations[path] == nil {
+			src.operations[path] = map[string]bool{}
+		}
+		src.operations[path][method] = true
+	}
+
+	keys := make([]string, 0, len(sources))
+	for key := range sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rules := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		src := sources[key]
+		sourceField := "principals"
+		if sourceScope == NamespaceSourceScope {
+			sourceField = "namespaces"
+		}
+		rule := map[string]interface{}{
+			"from": []interface{}{
+				map[string]interface{}{"source": map[string]interface{}{sourceField: []interface{}{key}}},
+			},
+		}
+		// A rule without operations allows any request from the source
+		if !src.anyOperation {
+			to := httpOperations(src)
+			if ports := in.tcpPorts(namespace, wk, src, &proposal); len(ports) > 0 {
+				to = append(to, map[string]interface{}{"operation": map[string]interface{}{"ports": ports}})
+			}
+			// An empty "to" would allow any operation: the source is left out, so its traffic is denied
+			if len(to) == 0 {
+				proposal.Warnings = appendUnique(proposal.Warnings, fmt.Sprintf("No operation or port found for source [%s], its traffic will be denied", key))
+				continue
+			}
+			rule["to"] = to
+		}
+		rules = append(rules, rule)
+	}
+
+	selector := make(map[string]interface{}, len(wk.Labels))
+	for k, v := range wk.Labels {
+		selector[k] = v
+	}
+	policy := &kubernetes.GenericIstioObject{
+		TypeMeta: meta_v1.TypeMeta{
+			Kind:       kubernetes.PluralType[kubernetes.AuthorizationPolicies],
+			APIVersion: kubernetes.ApiToVersion[kubernetes.ResourceTypesToAPI[kubernetes.AuthorizationPolicies]],
+		},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      workload,
+			Namespace: namespace,
+		},
+		Spec: map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": selector},
+			"action":   "ALLOW",
+			"rules":    rules,
+		},
+	}
+	proposal.Policy.Parse(policy)
+
+	var from []byte
+	if existing, getErr := in.k8s.GetIstioObject(namespace, kubernetes.AuthorizationPolicies, workload); getErr == nil {
+		proposal.Existing = &models.AuthorizationPolicy{}
+		proposal.Existing.Parse(existing)
+		if from, err = util.MarshalYAML(reviewableIstioObject(kubernetes.AuthorizationPolicies, existing)); err != nil {
+			return proposal, err
+		}
+	} else if !errors2.IsNotFound(getErr) {
+		err = getErr
+		return proposal, err
+	}
+	var to []byte
+	if to, err = util.MarshalYAML(reviewableIstioObject(kubernetes.AuthorizationPolicies, policy)); err != nil {
+		return proposal, err
+	}
+	proposal.Diff = util.DiffLines(string(from), string(to))
+
+	return proposal, nil
+}
+
+// httpOperations returns the operations allowing the methods used for each path, sorted by path
+func httpOperations(src *policySource) []interface{} {
+	paths := make([]string, 0, len(src.operations))
+	for path := range src.operations {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	operations := make([]interface{}, 0, len(paths)+1)
+	for _, path := range paths {
+		methods := make([]string, 0, len(src.operations[path]))
+		for method := range src.operations[path] {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		operation := map[string]interface{}{"paths": []interface{}{path}, "methods": toInterfaces(methods)}
+		operations = append(operations, map[string]interface{}{"operation": operation})
+	}
+	return operations
+}
+
+// tcpPorts returns the target ports of the services called over TCP, as ports are not reported in the telemetry.
+// Named target ports are resolved from the container ports of the workload pods.
+func (in *IstioConfigService) tcpPorts(namespace string, wk *models.Workload, src *policySource, proposal *models.AuthorizationPolicyProposal) []interface{} {
+	services := make([]string, 0, len(src.tcpServices))
+	for service := range src.tcpServices {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	var namedPorts map[string]int32
+	found := map[string]bool{}
+	ports := []string{}
+	for _, service := range services {
+		svc, err := in.k8s.GetService(namespace, service)
+		if err != nil {
+			proposal.Warnings = appendUnique(proposal.Warnings, fmt.Sprintf("Ports of service [%s] not found, its TCP traffic will be denied: %v", service, err))
+			continue
+		}
+		for _, p := range svc.Spec.Ports {
+			port := strconv.Itoa(int(p.Port))
+			if p.TargetPort.Type == intstr.String && p.TargetPort.StrVal != "" {
+				if namedPorts == nil {
+					namedPorts = in.workloadNamedPorts(namespace, wk)
+				}
+				number, ok := namedPorts[p.TargetPort.StrVal]
+				if !ok {
+					proposal.Warnings = appendUnique(proposal.Warnings, fmt.Sprintf("Target port [%s] of service [%s] not found in the workload containers, its TCP traffic will be denied", p.TargetPort.StrVal, service))
+					continue
+				}
+				port = strconv.Itoa(int(number))
+			} else if p.TargetPort.IntVal > 0 {
+				port = strconv.Itoa(int(p.TargetPort.IntVal))
+			}
+			if !found[port] {
+				found[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+	sort.Strings(ports)
+	return toInterfaces(ports)
+}
+
+// workloadNamedPorts returns the container ports of the workload pods by name
+func (in *IstioConfigService) workloadNamedPorts(namespace string, wk *models.Workload) map[string]int32 {
+	namedPorts := map[string]int32{}
+	selector := labels.Set(wk.Labels).String()
+	var pods []core_v1.Pod
+	var err error
+	if IsNamespaceCached(namespace) {
+		pods, err = kialiCache.GetPods(namespace, selector)
+	} else {
+		pods, err = in.k8s.GetPods(namespace, selector)
+	}
+	if err != nil {
+		log.Errorf("Error fetching the pods of workload %s to resolve its named ports: %s", wk.Name, err)
+		return namedPorts
+	}
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			for _, p := range container.Ports {
+				if p.Name != "" {
+					namedPorts[p.Name] = p.ContainerPort
+				}
+			}
+		}
+	}
+	return namedPorts
+}
+
+// parseRequestOperation returns the method and path of a request operation classified as "METHOD /path"
+func parseRequestOperation(operation string) (string, string, bool) {
+	fields := strings.Fields(operation)
+	if len(fields) != 2 || !strings.HasPrefix(fields[1], "/") || strings.ToUpper(fields[0]) != fields[0] {
+		return "", "", false
+	}
+	return fields[0], fields[1], true
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		result = append(result, v)
+	}
+	return result
+}
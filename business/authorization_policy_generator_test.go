@@ -0,0 +1,182 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	osapps_v1 "github.com/openshift/api/apps/v1"
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestGenerateAuthorizationPolicy(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	queryTime := time.Now()
+	k8s, prom := mockAuthorizationPolicyGenerator(queryTime)
+	k8s.On("GetIstioObject", "Namespace", "authorizationpolicies", "details-v1").Return(&kubernetes.GenericIstioObject{},
+		errors.NewNotFound(schema.GroupResource{Resource: "authorizationpolicies"}, "details-v1"))
+
+	proposal, err := NewWithBackends(k8s, prom, nil).IstioConfig.GenerateAuthorizationPolicy("Namespace", "details-v1", "1h", PrincipalSourceScope, queryTime)
+	assert.NoError(err)
+	assert.Nil(proposal.Existing)
+	assert.Equal("details-v1", proposal.Policy.Metadata.Name)
+	assert.Equal("ALLOW", proposal.Policy.Spec.Action)
+	assert.Equal(map[string]interface{}{"matchLabels": map[string]interface{}{"app": "details", "version": "v1"}}, proposal.Policy.Spec.Selector)
+	assert.Equal([]interface{}{
+		map[string]interface{}{
+			"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"principals": []interface{}{"cluster.local/ns/Namespace/sa/productpage"}}}},
+			"to": []interface{}{
+				map[string]interface{}{"operation": map[string]interface{}{"paths": []interface{}{"/details"}, "methods": []interface{}{"GET", "POST"}}},
+				map[string]interface{}{"operation": map[string]interface{}{"paths": []interface{}{"/health"}, "methods": []interface{}{"GET"}}},
+			},
+		},
+		map[string]interface{}{
+			"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"principals": []interface{}{"cluster.local/ns/Namespace/sa/reviews"}}}},
+			"to": []interface{}{
+				map[string]interface{}{"operation": map[string]interface{}{"ports": []interface{}{"9081"}}},
+			},
+		},
+	}, proposal.Policy.Spec.Rules)
+	assert.Equal([]string{"Traffic without mTLS identity from workload [unknown] of namespace [unknown] will be denied"}, proposal.Warnings)
+	assert.Contains(proposal.Diff, "+kind: AuthorizationPolicy\n")
+	assert.NotContains(proposal.Diff, "\n-")
+}
+
+func TestGenerateAuthorizationPolicyByNamespace(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	queryTime := time.Now()
+	k8s, prom := mockAuthorizationPolicyGenerator(queryTime)
+	existing := &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "details-v1", Namespace: "Namespace", ResourceVersion: "10"},
+		Spec: map[string]interface{}{
+			"action": "ALLOW",
+			"rules":  []interface{}{map[string]interface{}{"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"namespaces": []interface{}{"Namespace"}}}}}},
+		},
+	}
+	k8s.On("GetIstioObject", "Namespace", "authorizationpolicies", "details-v1").Return(existing, nil)
+
+	proposal, err := NewWithBackends(k8s, prom, nil).IstioConfig.GenerateAuthorizationPolicy("Namespace", "details-v1", "1h", NamespaceSourceScope, queryTime)
+	assert.NoError(err)
+	assert.NotNil(proposal.Existing)
+	rules := proposal.Policy.Spec.Rules.([]interface{})
+	assert.Len(rules, 1)
+	assert.Equal([]interface{}{map[string]interface{}{"source": map[string]interface{}{"namespaces": []interface{}{"Namespace"}}}}, rules[0].(map[string]interface{})["from"])
+	assert.Len(rules[0].(map[string]interface{})["to"], 3)
+	// Unchanged fields are common to both versions
+	assert.Contains(proposal.Diff, " kind: AuthorizationPolicy\n")
+	assert.Contains(proposal.Diff, " - from:\n")
+	assert.Contains(proposal.Diff, "+  selector:\n")
+	assert.NotContains(proposal.Diff, "resourceVersion")
+
+	_, err = NewWithBackends(k8s, prom, nil).IstioConfig.GenerateAuthorizationPolicy("Namespace", "details-v1", "1h", "workload", queryTime)
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestGenerateAuthorizationPolicyNamedTargetPorts(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	queryTime := time.Now()
+	k8s, prom := mockAuthorizationPolicyGenerator(queryTime)
+	k8s.On("GetIstioObject", "Namespace", "authorizationpolicies", "details-v1").Return(&kubernetes.GenericIstioObject{},
+		errors.NewNotFound(schema.GroupResource{Resource: "authorizationpolicies"}, "details-v1"))
+	sample := func(principal, service string) *model.Sample {
+		return &model.Sample{Metric: model.Metric{
+			"source_principal":          model.LabelValue(principal),
+			"source_workload_namespace": "Namespace",
+			"destination_service_name":  model.LabelValue(service),
+			"request_protocol":          "tcp",
+		}}
+	}
+	prom.On("GetWorkloadInboundTraffic", "Namespace", "details-v1", "2h", queryTime).Return(model.Vector{
+		sample("spiffe://cluster.local/ns/Namespace/sa/reviews", "details-named"),
+		sample("spiffe://cluster.local/ns/Namespace/sa/ratings", "details-missing"),
+	}, nil)
+
+	proposal, err := NewWithBackends(k8s, prom, nil).IstioConfig.GenerateAuthorizationPolicy("Namespace", "details-v1", "2h", PrincipalSourceScope, queryTime)
+	assert.NoError(err)
+	// The source without any resolved port is left out, as a rule without operations would allow all its traffic
+	assert.Equal([]interface{}{
+		map[string]interface{}{
+			"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"principals": []interface{}{"cluster.local/ns/Namespace/sa/reviews"}}}},
+			"to": []interface{}{
+				map[string]interface{}{"operation": map[string]interface{}{"ports": []interface{}{"9082"}}},
+			},
+		},
+	}, proposal.Policy.Spec.Rules)
+	assert.Equal([]string{
+		"Target port [tcp-missing] of service [details-missing] not found in the workload containers, its TCP traffic will be denied",
+		"No operation or port found for source [cluster.local/ns/Namespace/sa/ratings], its traffic will be denied",
+	}, proposal.Warnings)
+}
+
+func mockAuthorizationPolicyGenerator(queryTime time.Time) (*kubetest.K8SClientMock, *prometheustest.PromClientMock) {
+	notfound := errors.NewNotFound(schema.GroupResource{Group: "test-group", Resource: "test-resource"}, "not found")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&FakeDepSyncedWithRS()[0], nil)
+	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetDaemonSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.DaemonSet{}, notfound)
+	k8s.On("IsArgoRolloutsApi").Return(false)
+	pods := FakePodsSyncedWithDeployments()
+	pods[0].Spec.Containers[0].Ports = []core_v1.ContainerPort{{Name: "tcp-details", ContainerPort: 9082}}
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(pods, nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetService", "Namespace", "details-tcp").Return(&core_v1.Service{
+		Spec: core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Port: 9080, TargetPort: intstr.FromInt(9081)}}},
+	}, nil)
+	k8s.On("GetService", "Namespace", "details-named").Return(&core_v1.Service{
+		Spec: core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Port: 9080, TargetPort: intstr.FromString("tcp-details")}}},
+	}, nil)
+	k8s.On("GetService", "Namespace", "details-missing").Return(&core_v1.Service{
+		Spec: core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Port: 9080, TargetPort: intstr.FromString("tcp-missing")}}},
+	}, nil)
+
+	sample := func(principal, srcNamespace, protocol, service, operation string) *model.Sample {
+		return &model.Sample{Metric: model.Metric{
+			"source_principal":          model.LabelValue(principal),
+			"source_workload_namespace": model.LabelValue(srcNamespace),
+			"source_workload":           "any",
+			"destination_service_name":  model.LabelValue(service),
+			"request_protocol":          model.LabelValue(protocol),
+			"request_operation":         model.LabelValue(operation),
+		}}
+	}
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetWorkloadInboundTraffic", "Namespace", "details-v1", "1h", queryTime).Return(model.Vector{
+		sample("spiffe://cluster.local/ns/Namespace/sa/productpage", "Namespace", "http", "details", "GET /details"),
+		sample("spiffe://cluster.local/ns/Namespace/sa/productpage", "Namespace", "http", "details", "POST /details"),
+		sample("spiffe://cluster.local/ns/Namespace/sa/productpage", "Namespace", "http", "details", "GET /health"),
+		sample("spiffe://cluster.local/ns/Namespace/sa/reviews", "Namespace", "tcp", "details-tcp", ""),
+		{Metric: model.Metric{"source_principal": "unknown", "source_workload_namespace": "unknown", "source_workload": "unknown", "request_protocol": "http"}},
+	}, nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
+	return k8s, prom
+}
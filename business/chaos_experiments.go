@@ -0,0 +1,397 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	// KialiChaosLabel is set on the VirtualServices with the faults of a running experiment
+	KialiChaosLabel = "kiali_chaos"
+
+	// chaosExpiryAnnotation holds the time the faults of an experiment are removed, in RFC 3339
+	chaosExpiryAnnotation = "kiali.io/chaos-expiry"
+	// chaosStartAnnotation holds the time an experiment started, in RFC 3339
+	chaosStartAnnotation = "kiali.io/chaos-start"
+	// chaosPreviousSpecAnnotation holds the spec restored when an experiment ends. VirtualServices created for an
+	// experiment don't have it and are deleted.
+	chaosPreviousSpecAnnotation = "kiali.io/chaos-previous-spec"
+
+	maxChaosDuration = 24 * time.Hour
+	// chaosRetryInterval is the time before ending again an experiment whose VirtualService couldn't be restored
+	chaosRetryInterval = time.Minute
+)
+
+// chaosExperiments holds the timers ending the experiments, by namespace and VirtualService
+var chaosExperiments = struct {
+	sync.Mutex
+	timers map[string]*time.Timer
+}{timers: map[string]*time.Timer{}}
+
+// getChaosClient returns the client ending the experiments, when the user starting them may no longer be logged in
+var getChaosClient = getKialiSAClient
+
+// StartChaosExperiment injects faults in the HTTP routes of the VirtualService of a service for a duration. Without
+// VirtualService, one routing to the service is created. When the duration expires, the previous spec is restored.
+// The faults are injected with the user client, but the experiment is ended with the Kiali service account: a
+// Forbidden error is returned unless it is allowed to patch the VirtualService, or to delete the one created.
+func (in *IstioConfigService) StartChaosExperiment(namespace, service string, request models.ChaosExperimentRequest) (models.ChaosExperiment, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "StartChaosExperiment")
+	defer promtimer.ObserveNow(&err)
+
+	experiment := models.ChaosExperiment{Namespace: namespace, Service: service}
+	var duration time.Duration
+	if duration, err = time.ParseDuration(request.Duration); err != nil || duration <= 0 || duration > maxChaosDuration {
+		err = errors2.NewBadRequest(fmt.Sprintf("invalid duration [%s], it must be positive and at most %v", request.Duration, maxChaosDuration))
+		return experiment, err
+	}
+	var fault map[string]interface{}
+	if fault, err = faultInjectionSpec(request.Fault); err != nil {
+		return experiment, err
+	}
+	var svc *wizardService
+	if svc, err = in.getWizardService(namespace, service); err != nil {
+		return experiment, err
+	}
+	var current kubernetes.IstioObject
+	if current, err = in.getServiceVirtualService(namespace, service); err != nil {
+		return experiment, err
+	}
+	if current != nil {
+		if _, running := current.GetObjectMeta().Labels[KialiChaosLabel]; running {
+			err = errors2.NewConflict(schema.GroupResource{Group: "networking.istio.io", Resource: kubernetes.VirtualServices}, current.GetObjectMeta().Name,
+				fmt.Errorf("an experiment is already running on service [%s]", service))
+			return experiment, err
+		}
+	}
+
+	// Kiali must be allowed to end the experiment before the faults are injected
+	if err = checkChaosRevert(namespace, service, current == nil); err != nil {
+		return experiment, err
+	}
+
+	start := util.Clock.Now()
+	expiry := start.Add(duration)
+	annotations := map[string]interface{}{
+		chaosStartAnnotation:  start.Format(time.RFC3339),
+		chaosExpiryAnnotation: expiry.Format(time.RFC3339),
+	}
+	api := GetIstioAPI(kubernetes.VirtualServices)
+	var details models.IstioConfigDetails
+	if current == nil {
+		labels := wizardLabels(models.FaultInjectionWizard)
+		labels[KialiChaosLabel] = "true"
+		var body []byte
+		body, err = json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":        service,
+				"namespace":   namespace,
+				"labels":      labels,
+				"annotations": annotations,
+			},
+			"spec": svc.virtualServiceSpec(map[string]interface{}{
+				"fault": fault,
+				"route": []interface{}{map[string]interface{}{"destination": svc.destination("")}},
+			}),
+		})
+		if err != nil {
+			return experiment, err
+		}
+		details, err = in.CreateIstioConfigDetail(api, namespace, kubernetes.VirtualServices, body)
+	} else {
+		http, _ := current.GetSpec()["http"].([]interface{})
+		if len(http) == 0 {
+			err = errors2.NewBadRequest(fmt.Sprintf("VirtualService [%s] has no HTTP routes", current.GetObjectMeta().Name))
+			return experiment, err
+		}
+		var previous []byte
+		if previous, err = json.Marshal(current.GetSpec()); err != nil {
+			return experiment, err
+		}
+		annotations[chaosPreviousSpecAnnotation] = string(previous)
+		faulty := make([]interface{}, 0, len(http))
+		for _, route := range http {
+			r, _ := route.(map[string]interface{})
+			faultyRoute := make(map[string]interface{}, len(r)+1)
+			for k, v := range r {
+				faultyRoute[k] = v
+			}
+			faultyRoute["fault"] = fault
+			faulty = append(faulty, faultyRoute)
+		}
+		var patch []byte
+		patch, err = json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels":      map[string]interface{}{KialiChaosLabel: "true"},
+				"annotations": annotations,
+			},
+			"spec": map[string]interface{}{"http": faulty},
+		})
+		if err != nil {
+			return experiment, err
+		}
+		details, err = in.UpdateIstioConfigDetail(api, namespace, kubernetes.VirtualServices, current.GetObjectMeta().Name, string(patch))
+	}
+	if err != nil {
+		return experiment, err
+	}
+
+	name := details.VirtualService.Metadata.Name
+	scheduleChaosRevert(namespace, name, expiry)
+	experiment.VirtualService = name
+	experiment.Fault = fault
+	experiment.StartTime = start
+	experiment.Expiry = expiry
+	return experiment, nil
+}
+
+// GetChaosExperiment returns the experiment running on a service, with the health of its requests
+func (in *IstioConfigService) GetChaosExperiment(namespace, service, rateInterval string, queryTime time.Time) (models.ChaosExperiment, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "GetChaosExperiment")
+	defer promtimer.ObserveNow(&err)
+
+	experiment := models.ChaosExperiment{Namespace: namespace, Service: service}
+	var vs kubernetes.IstioObject
+	if vs, err = in.getChaosVirtualService(namespace, service); err != nil {
+		return experiment, err
+	}
+	meta := vs.GetObjectMeta()
+	experiment.VirtualService = meta.Name
+	experiment.StartTime, _ = time.Parse(time.RFC3339, meta.Annotations[chaosStartAnnotation])
+	experiment.Expiry, _ = time.Parse(time.RFC3339, meta.Annotations[chaosExpiryAnnotation])
+	if http, ok := vs.GetSpec()["http"].([]interface{}); ok && len(http) > 0 {
+		if route, ok := http[0].(map[string]interface{}); ok {
+			experiment.Fault = route["fault"]
+		}
+	}
+
+	var health models.ServiceHealth
+	if health, err = in.businessLayer.Health.GetServiceHealth(namespace, service, rateInterval, queryTime); err != nil {
+		return experiment, err
+	}
+	experiment.Health = &health
+	experiment.ErrorRatio = health.Requests.InboundErrorRatio()
+	return experiment, nil
+}
+
+// StopChaosExperiment ends the experiment running on a service before its expiry, restoring the previous spec
+func (in *IstioConfigService) StopChaosExperiment(namespace, service string) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "StopChaosExperiment")
+	defer promtimer.ObserveNow(&err)
+
+	var vs kubernetes.IstioObject
+	if vs, err = in.getChaosVirtualService(namespace, service); err != nil {
+		return err
+	}
+	if err = in.revertChaosExperiment(vs); err != nil {
+		return err
+	}
+	cancelChaosRevert(namespace, vs.GetObjectMeta().Name)
+	return nil
+}
+
+// revertChaosExperiment restores the spec of a VirtualService before an experiment, or deletes it when it was
+// created for the experiment
+func (in *IstioConfigService) revertChaosExperiment(vs kubernetes.IstioObject) error {
+	meta := vs.GetObjectMeta()
+	api := GetIstioAPI(kubernetes.VirtualServices)
+	previous, found := meta.Annotations[chaosPreviousSpecAnnotation]
+	if !found {
+		return in.DeleteIstioConfigDetail(api, meta.Namespace, kubernetes.VirtualServices, meta.Name)
+	}
+	var previousSpec map[string]interface{}
+	if err := json.Unmarshal([]byte(previous), &previousSpec); err != nil {
+		return fmt.Errorf("invalid %s annotation in VirtualService [%s/%s]: %v", chaosPreviousSpecAnnotation, meta.Namespace, meta.Name, err)
+	}
+	currentSpec, err := toGenericJSON(vs.GetSpec())
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{KialiChaosLabel: nil},
+			"annotations": map[string]interface{}{
+				chaosStartAnnotation:        nil,
+				chaosExpiryAnnotation:       nil,
+				chaosPreviousSpecAnnotation: nil,
+			},
+		},
+		"spec": util.CreateMergePatch(currentSpec, previousSpec),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = in.UpdateIstioConfigDetail(api, meta.Namespace, kubernetes.VirtualServices, meta.Name, string(patch))
+	return err
+}
+
+// checkChaosRevert returns a Forbidden error unless the Kiali service account is allowed to end the experiment running
+// on a service: to delete the VirtualService created for the experiment, or to patch the existing one
+func checkChaosRevert(namespace, service string, created bool) error {
+	k8s, err := getChaosClient()
+	if err != nil {
+		return err
+	}
+	verb := "patch"
+	if created {
+		verb = "delete"
+	}
+	ssars, err := k8s.GetSelfSubjectAccessReview(namespace, kubernetes.ResourceTypesToAPI[kubernetes.VirtualServices], kubernetes.VirtualServices, []string{verb})
+	if err != nil {
+		return err
+	}
+	if len(ssars) == 0 || !ssars[0].Status.Allowed {
+		return errors2.NewForbidden(schema.GroupResource{Group: "networking.istio.io", Resource: kubernetes.VirtualServices}, service,
+			fmt.Errorf("Kiali service account can't %s virtualservices in namespace [%s] to end the experiment", verb, namespace))
+	}
+	return nil
+}
+
+// getServiceVirtualService returns the VirtualService whose hosts include the service, nil when not found
+func (in *IstioConfigService) getServiceVirtualService(namespace, service string) (kubernetes.IstioObject, error) {
+	vs, err := in.k8s.GetIstioObjects(namespace, kubernetes.VirtualServices, "")
+	if err != nil {
+		return nil, err
+	}
+	found := filterServiceHosts(vs, namespace, service)
+	if len(found) > 1 {
+		return nil, errors2.NewBadRequest(fmt.Sprintf("several VirtualServices define the hosts of service [%s]", service))
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return found[0], nil
+}
+
+// getChaosVirtualService returns the VirtualService with the faults of the experiment running on a service
+func (in *IstioConfigService) getChaosVirtualService(namespace, service string) (kubernetes.IstioObject, error) {
+	vs, err := in.k8s.GetIstioObjects(namespace, kubernetes.VirtualServices, KialiChaosLabel)
+	if err != nil {
+		return nil, err
+	}
+	found := filterServiceHosts(vs, namespace, service)
+	if len(found) == 0 {
+		return nil, errors2.NewNotFound(schema.GroupResource{Group: "networking.istio.io", Resource: "chaosexperiments"}, service)
+	}
+	return found[0], nil
+}
+
+// filterServiceHosts returns the VirtualServices whose hosts include the service
+func filterServiceHosts(vs []kubernetes.IstioObject, namespace, service string) []kubernetes.IstioObject {
+	found := []kubernetes.IstioObject{}
+	for _, v := range vs {
+		hosts, _ := v.GetSpec()["hosts"].([]interface{})
+		for _, h := range hosts {
+			if host, ok := h.(string); ok && kubernetes.FilterByHost(host, service, namespace) {
+				found = append(found, v)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// StartChaosExperiments schedules the end of the experiments running in the accessible namespaces, so the faults
+// injected before a restart are removed. Expired experiments are ended right away.
+func StartChaosExperiments() {
+	k8s, err := getChaosClient()
+	if err != nil {
+		log.Errorf("Error getting the client to end the chaos experiments: %v", err)
+		return
+	}
+	namespaces, err := NewWithBackends(k8s, nil, nil).Namespace.GetNamespaces()
+	if err != nil {
+		log.Errorf("Error getting the namespaces of the chaos experiments: %v", err)
+		return
+	}
+	for _, ns := range namespaces {
+		vs, err := k8s.GetIstioObjects(ns.Name, kubernetes.VirtualServices, KialiChaosLabel)
+		if err != nil {
+			log.Errorf("Error getting the chaos experiments of namespace [%s]: %v", ns.Name, err)
+			continue
+		}
+		for _, v := range vs {
+			meta := v.GetObjectMeta()
+			expiry, err := time.Parse(time.RFC3339, meta.Annotations[chaosExpiryAnnotation])
+			if err != nil {
+				log.Errorf("Invalid %s annotation in VirtualService [%s/%s], ending the experiment", chaosExpiryAnnotation, meta.Namespace, meta.Name)
+			}
+			scheduleChaosRevert(meta.Namespace, meta.Name, expiry)
+		}
+	}
+}
+
+// stopChaosExperiments stops the timers of the experiments, which are ended on the next start
+func stopChaosExperiments() {
+	chaosExperiments.Lock()
+	defer chaosExperiments.Unlock()
+	for key, timer := range chaosExperiments.timers {
+		timer.Stop()
+		delete(chaosExperiments.timers, key)
+	}
+}
+
+// scheduleChaosRevert ends an experiment at its expiry, replacing the timer scheduled before for the VirtualService
+func scheduleChaosRevert(namespace, name string, expiry time.Time) {
+	key := namespace + "/" + name
+	chaosExperiments.Lock()
+	defer chaosExperiments.Unlock()
+	if timer, found := chaosExperiments.timers[key]; found {
+		timer.Stop()
+	}
+	chaosExperiments.timers[key] = time.AfterFunc(expiry.Sub(util.Clock.Now()), func() {
+		cancelChaosRevert(namespace, name)
+		endChaosExperiment(namespace, name)
+	})
+}
+
+func cancelChaosRevert(namespace, name string) {
+	key := namespace + "/" + name
+	chaosExperiments.Lock()
+	defer chaosExperiments.Unlock()
+	if timer, found := chaosExperiments.timers[key]; found {
+		timer.Stop()
+		delete(chaosExperiments.timers, key)
+	}
+}
+
+// endChaosExperiment restores the VirtualService of an expired experiment, unless it was already ended
+func endChaosExperiment(namespace, name string) {
+	k8s, err := getChaosClient()
+	if err != nil {
+		log.Errorf("Error getting the client to end the chaos experiment of VirtualService [%s/%s]: %v", namespace, name, err)
+		return
+	}
+	vs, err := k8s.GetIstioObject(namespace, kubernetes.VirtualServices, name)
+	if err != nil {
+		if !errors2.IsNotFound(err) {
+			log.Errorf("Error getting the VirtualService [%s/%s] of a chaos experiment: %v", namespace, name, err)
+		}
+		return
+	}
+	if _, running := vs.GetObjectMeta().Labels[KialiChaosLabel]; !running {
+		return
+	}
+	layer := NewWithBackends(k8s, nil, nil)
+	layer.SetUser("kiali")
+	if err = layer.IstioConfig.revertChaosExperiment(vs); err != nil {
+		log.Errorf("Error ending the chaos experiment of VirtualService [%s/%s], retrying in %v: %v", namespace, name, chaosRetryInterval, err)
+		scheduleChaosRevert(namespace, name, util.Clock.Now().Add(chaosRetryInterval))
+		return
+	}
+	log.Infof("Chaos experiment of VirtualService [%s/%s] ended", namespace, name)
+}
@@ -0,0 +1,169 @@
+package business
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_v1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func TestStartChaosExperiment(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockWizardService()
+	existing := fakeChaosVirtualService(nil, nil)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.VirtualServices, "").Return([]kubernetes.IstioObject{existing}, nil)
+	var patch map[string]interface{}
+	k8s.On("UpdateIstioObject", mock.Anything, "bookinfo", kubernetes.VirtualServices, "reviews-vs", mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		assert.NoError(json.Unmarshal([]byte(args.String(4)), &patch))
+	}).Return(existing, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	now := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: now}
+	defer cancelChaosRevert("bookinfo", "reviews-vs")
+	getChaosClient = func() (kubernetes.ClientInterface, error) { return mockChaosServiceAccount("patch", true), nil }
+	defer func() { getChaosClient = getKialiSAClient }()
+
+	layer := NewWithBackends(k8s, nil, nil)
+	experiment, err := layer.IstioConfig.StartChaosExperiment("bookinfo", "reviews", models.ChaosExperimentRequest{
+		Fault:    models.FaultInjection{Abort: &models.FaultAbort{Percentage: 50, HttpStatus: 503}},
+		Duration: "10m",
+	})
+	assert.NoError(err)
+	assert.Equal("reviews-vs", experiment.VirtualService)
+	assert.Equal(now.Add(10*time.Minute), experiment.Expiry)
+
+	metadata := patch["metadata"].(map[string]interface{})
+	assert.Equal(map[string]interface{}{KialiChaosLabel: "true"}, metadata["labels"])
+	annotations := metadata["annotations"].(map[string]interface{})
+	assert.Equal("2020-06-01T10:10:00Z", annotations[chaosExpiryAnnotation])
+	assert.JSONEq(`{"hosts": ["reviews"], "http": [{"route": [{"destination": {"host": "reviews", "subset": "v1"}}]}]}`, annotations[chaosPreviousSpecAnnotation].(string))
+	route := patch["spec"].(map[string]interface{})["http"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(map[string]interface{}{"abort": map[string]interface{}{"percentage": map[string]interface{}{"value": float64(50)}, "httpStatus": float64(503)}}, route["fault"])
+	assert.NotNil(route["route"])
+
+	chaosExperiments.Lock()
+	assert.Contains(chaosExperiments.timers, "bookinfo/reviews-vs")
+	chaosExperiments.Unlock()
+
+	_, err = layer.IstioConfig.StartChaosExperiment("bookinfo", "reviews", models.ChaosExperimentRequest{
+		Fault:    models.FaultInjection{Abort: &models.FaultAbort{Percentage: 50, HttpStatus: 503}},
+		Duration: "48h",
+	})
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestStartChaosExperimentForbidden(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockWizardService()
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.VirtualServices, "").Return([]kubernetes.IstioObject{}, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	getChaosClient = func() (kubernetes.ClientInterface, error) { return mockChaosServiceAccount("delete", false), nil }
+	defer func() { getChaosClient = getKialiSAClient }()
+
+	// Kiali must be allowed to delete the VirtualService created before the faults are injected
+	layer := NewWithBackends(k8s, nil, nil)
+	_, err := layer.IstioConfig.StartChaosExperiment("bookinfo", "reviews", models.ChaosExperimentRequest{
+		Fault:    models.FaultInjection{Abort: &models.FaultAbort{Percentage: 50, HttpStatus: 503}},
+		Duration: "10m",
+	})
+	assert.True(errors.IsForbidden(err))
+	k8s.AssertNotCalled(t, "CreateIstioObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStopChaosExperiment(t *testing.T) {
+	assert := assert.New(t)
+
+	previous := fakeChaosVirtualService(nil, nil).GetSpec()
+	previousJSON, _ := json.Marshal(previous)
+	faulty := fakeChaosVirtualService(map[string]string{KialiChaosLabel: "true"}, map[string]string{
+		chaosExpiryAnnotation:       "2020-06-01T10:10:00Z",
+		chaosPreviousSpecAnnotation: string(previousJSON),
+	})
+	faulty.GetSpec()["http"].([]interface{})[0].(map[string]interface{})["fault"] = map[string]interface{}{"abort": map[string]interface{}{"httpStatus": 503}}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.VirtualServices, KialiChaosLabel).Return([]kubernetes.IstioObject{faulty}, nil)
+	k8s.On("UpdateIstioObject", mock.Anything, "bookinfo", kubernetes.VirtualServices, "reviews-vs",
+		`{"metadata":{"annotations":{"kiali.io/chaos-expiry":null,"kiali.io/chaos-previous-spec":null,"kiali.io/chaos-start":null},"labels":{"kiali_chaos":null}},"spec":{"http":[{"route":[{"destination":{"host":"reviews","subset":"v1"}}]}]}}`).Return(faulty, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	assert.NoError(layer.IstioConfig.StopChaosExperiment("bookinfo", "reviews"))
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+
+	err := layer.IstioConfig.StopChaosExperiment("bookinfo", "ratings")
+	assert.True(errors.IsNotFound(err))
+}
+
+func TestEndChaosExperiment(t *testing.T) {
+	assert := assert.New(t)
+
+	// VirtualServices created for the experiment are deleted when it expires
+	created := fakeChaosVirtualService(map[string]string{KialiChaosLabel: "true"}, map[string]string{chaosExpiryAnnotation: "2020-06-01T10:10:00Z"})
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews-vs").Return(created, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	getChaosClient = func() (kubernetes.ClientInterface, error) { return k8s, nil }
+	defer func() { getChaosClient = getKialiSAClient }()
+	util.Clock = util.ClockMock{Time: time.Date(2020, 6, 1, 10, 10, 0, 0, time.UTC)}
+
+	done := make(chan bool)
+	k8s.On("DeleteIstioObject", mock.Anything, "bookinfo", kubernetes.VirtualServices, "reviews-vs").Run(func(args mock.Arguments) {
+		done <- true
+	}).Return(nil)
+	// Expired experiments end right away
+	scheduleChaosRevert("bookinfo", "reviews-vs", time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		assert.Fail("experiment not ended")
+	}
+}
+
+// mockChaosServiceAccount returns a mock of the Kiali service account client, allowed or not to end the experiments
+func mockChaosServiceAccount(verb string, allowed bool) *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "networking.istio.io", kubernetes.VirtualServices, []string{verb}).Return([]*auth_v1.SelfSubjectAccessReview{
+		{Status: auth_v1.SubjectAccessReviewStatus{Allowed: allowed}},
+	}, nil)
+	return k8s
+}
+
+func fakeChaosVirtualService(labels, annotations map[string]string) *kubernetes.GenericIstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-vs", Namespace: "bookinfo", Labels: labels, Annotations: annotations},
+		Spec: map[string]interface{}{
+			"hosts": []interface{}{"reviews"},
+			"http": []interface{}{
+				map[string]interface{}{
+					"route": []interface{}{
+						map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "v1"}},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,298 @@
+package custom
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	opExists   = "exists"
+	opAbsent   = "absent"
+	opEqual    = "=="
+	opNotEqual = "!="
+	opMatch    = "=~"
+	opNotMatch = "!~"
+	opLT       = "<"
+	opLTE      = "<="
+	opGT       = ">"
+	opGTE      = ">="
+)
+
+var pathSegmentRegexp = regexp.MustCompile(`^([^\[\]]+)(?:\[(\*|\d+)\])?$`)
+
+// Rule is a parsed config.CustomValidationRule, ready to be checked against objects
+type Rule struct {
+	config.CustomValidationRule
+	operator string
+	value    string
+	number   float64
+	regex    *regexp.Regexp
+	segments []pathSegment
+}
+
+type pathSegment struct {
+	field string
+	// index is -1 for plain fields and -2 for wildcards
+	index int
+}
+
+func (s pathSegment) String() string {
+	if s.index >= 0 {
+		return fmt.Sprintf("%s[%d]", s.field, s.index)
+	}
+	return s.field
+}
+
+// loadedRules are the custom rules of the configuration, parsed once when it's loaded
+var loadedRules struct {
+	sync.RWMutex
+	rules []Rule
+}
+
+// LoadRules parses the custom rules of the configuration and keeps them for the validations, replacing the ones
+// loaded before. Nothing is loaded when a rule is invalid.
+func LoadRules(rules []config.CustomValidationRule) error {
+	parsed := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		rule, err := ParseRule(r)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, rule)
+	}
+	loadedRules.Lock()
+	defer loadedRules.Unlock()
+	loadedRules.rules = parsed
+	return nil
+}
+
+// LoadedRules returns the custom rules loaded by LoadRules
+func LoadedRules() []Rule {
+	loadedRules.RLock()
+	defer loadedRules.RUnlock()
+	return loadedRules.rules
+}
+
+// ParseRule validates a custom rule from the configuration and parses its path and condition
+func ParseRule(r config.CustomValidationRule) (Rule, error) {
+	rule := Rule{CustomValidationRule: r}
+
+	if _, found := models.ObjectTypeSingular[r.ObjectType]; !found {
+		return rule, fmt.Errorf("custom validation rule [%s]: unknown object type [%s]", r.Code, r.ObjectType)
+	}
+	switch models.SeverityLevel(r.Severity) {
+	case "":
+		rule.Severity = string(models.WarningSeverity)
+	case models.ErrorSeverity, models.WarningSeverity:
+	default:
+		return rule, fmt.Errorf("custom validation rule [%s]: severity must be error or warning, found [%s]", r.Code, r.Severity)
+	}
+	if r.Message == "" {
+		return rule, fmt.Errorf("custom validation rule [%s]: message is required", r.Code)
+	}
+
+	for _, s := range strings.Split(strings.Trim(r.Path, "/"), "/") {
+		matches := pathSegmentRegexp.FindStringSubmatch(s)
+		if matches == nil {
+			return rule, fmt.Errorf("custom validation rule [%s]: invalid path [%s]", r.Code, r.Path)
+		}
+		segment := pathSegment{field: matches[1], index: -1}
+		if matches[2] == "*" {
+			segment.index = -2
+		} else if matches[2] != "" {
+			segment.index, _ = strconv.Atoi(matches[2])
+		}
+		rule.segments = append(rule.segments, segment)
+	}
+
+	condition := strings.TrimSpace(r.Condition)
+	switch condition {
+	case opExists, opAbsent:
+		rule.operator = condition
+		return rule, nil
+	}
+	// Two-chars operators go first so "<=" is not taken as "<"
+	for _, op := range []string{opEqual, opNotEqual, opMatch, opNotMatch, opLTE, opGTE, opLT, opGT} {
+		if strings.HasPrefix(condition, op) {
+			rule.operator = op
+			rule.value = strings.TrimSpace(strings.TrimPrefix(condition, op))
+			break
+		}
+	}
+	var err error
+	switch rule.operator {
+	case "":
+		err = fmt.Errorf("custom validation rule [%s]: invalid condition [%s]", r.Code, r.Condition)
+	case opMatch, opNotMatch:
+		if rule.regex, err = regexp.Compile(rule.value); err != nil {
+			err = fmt.Errorf("custom validation rule [%s]: invalid regular expression [%s]: %v", r.Code, rule.value, err)
+		}
+	case opLT, opLTE, opGT, opGTE:
+		if rule.number, err = strconv.ParseFloat(rule.value, 64); err != nil {
+			err = fmt.Errorf("custom validation rule [%s]: [%s] requires a number, found [%s]", r.Code, rule.operator, rule.value)
+		}
+	}
+	return rule, err
+}
+
+// RuleChecker checks a custom Rule against an Istio object
+type RuleChecker struct {
+	Rule   Rule
+	Object kubernetes.IstioObject
+}
+
+func (c RuleChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	// Objects are walked as plain JSON so paths are the same than in the YAML
+	var object map[string]interface{}
+	if b, err := json.Marshal(c.Object); err != nil {
+		return checks, valid
+	} else if err := json.Unmarshal(b, &object); err != nil {
+		return checks, valid
+	}
+
+	for _, result := range resolvePath(object, c.Rule.segments, "") {
+		if !c.Rule.satisfied(result.value, result.found) {
+			check := c.Rule.build(result.path)
+			checks = append(checks, &check)
+			valid = valid && check.Severity != models.ErrorSeverity
+		}
+	}
+
+	return checks, valid
+}
+
+func (r Rule) build(path string) models.IstioCheck {
+	message := r.Message
+	if r.Code != "" {
+		message = r.Code + " " + message
+	}
+	return models.IstioCheck{
+		Code:     r.Code,
+		Message:  message,
+		Severity: models.SeverityLevel(r.Severity),
+		Path:     path,
+	}
+}
+
+func (r Rule) satisfied(value interface{}, found bool) bool {
+	found = found && value != nil
+	switch r.operator {
+	case opExists:
+		return found
+	case opAbsent:
+		return !found
+	}
+	// Comparisons only apply to the values present in the object
+	if !found {
+		return true
+	}
+	str := stringValue(value)
+	switch r.operator {
+	case opEqual:
+		return str == r.value
+	case opNotEqual:
+		return str != r.value
+	case opMatch:
+		return r.regex.MatchString(str)
+	case opNotMatch:
+		return !r.regex.MatchString(str)
+	}
+	number, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return false
+	}
+	switch r.operator {
+	case opLT:
+		return number < r.number
+	case opLTE:
+		return number <= r.number
+	case opGT:
+		return number > r.number
+	default:
+		return number >= r.number
+	}
+}
+
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+type pathResult struct {
+	path  string
+	value interface{}
+	found bool
+}
+
+// resolvePath returns the values found in the path, expanding the wildcards over the items of the lists.
+// A missing field is returned as not found, unless a wildcard follows it, as there are no items to check then.
+func resolvePath(node interface{}, segments []pathSegment, prefix string) []pathResult {
+	if len(segments) == 0 {
+		return []pathResult{{path: prefix, value: node, found: true}}
+	}
+
+	segment := segments[0]
+	path := joinPath(prefix, segment.field)
+	var child interface{}
+	found := false
+	if m, ok := node.(map[string]interface{}); ok {
+		child, found = m[segment.field]
+	}
+
+	if found && segment.index != -1 {
+		items, ok := child.([]interface{})
+		if !ok {
+			found = false
+		} else if segment.index == -2 {
+			results := make([]pathResult, 0)
+			for i, item := range items {
+				results = append(results, resolvePath(item, segments[1:], fmt.Sprintf("%s[%d]", path, i))...)
+			}
+			return results
+		} else if segment.index < len(items) {
+			child = items[segment.index]
+			path = fmt.Sprintf("%s[%d]", path, segment.index)
+		} else {
+			found = false
+		}
+	}
+
+	if !found {
+		path = prefix
+		for _, s := range segments {
+			if s.index == -2 {
+				return []pathResult{}
+			}
+			path = joinPath(path, s.String())
+		}
+		return []pathResult{{path: path, found: false}}
+	}
+
+	return resolvePath(child, segments[1:], path)
+}
+
+func joinPath(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	return prefix + "/" + field
+}
@@ -0,0 +1,132 @@
+package custom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestParseRuleErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	valid := config.CustomValidationRule{Code: "ORG0001", ObjectType: "virtualservices", Path: "spec/http[*]/timeout", Condition: "exists", Message: "Timeout is required"}
+	rule, err := ParseRule(valid)
+	assert.NoError(err)
+	assert.Equal(string(models.WarningSeverity), rule.Severity)
+
+	invalid := valid
+	invalid.ObjectType = "virtualservice"
+	_, err = ParseRule(invalid)
+	assert.Error(err)
+
+	invalid = valid
+	invalid.Condition = "contains foo"
+	_, err = ParseRule(invalid)
+	assert.Error(err)
+
+	invalid = valid
+	invalid.Condition = ">= ten"
+	_, err = ParseRule(invalid)
+	assert.Error(err)
+
+	invalid = valid
+	invalid.Path = "spec/http[a]/timeout"
+	_, err = ParseRule(invalid)
+	assert.Error(err)
+
+	invalid = valid
+	invalid.Severity = "info"
+	_, err = ParseRule(invalid)
+	assert.Error(err)
+}
+
+func TestLoadRules(t *testing.T) {
+	assert := assert.New(t)
+	defer func() { _ = LoadRules(nil) }()
+
+	err := LoadRules([]config.CustomValidationRule{
+		{Code: "ORG0001", ObjectType: "virtualservices", Path: "spec/http[*]/timeout", Condition: "exists", Message: "Timeout is required"},
+	})
+	assert.NoError(err)
+	rules := LoadedRules()
+	assert.Len(rules, 1)
+	assert.Equal("ORG0001", rules[0].Code)
+
+	// Nothing is loaded when a rule is invalid
+	err = LoadRules([]config.CustomValidationRule{
+		{Code: "ORG0002", ObjectType: "gateways", Path: "spec/servers", Condition: "exists", Message: "Servers are required"},
+		{Code: "ORG0003", ObjectType: "virtualservice", Path: "spec/hosts", Condition: "exists", Message: "Unknown object type"},
+	})
+	assert.Error(err)
+	assert.Equal(rules, LoadedRules())
+}
+
+func TestRequiredFieldPerRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", -1),
+		data.CreateEmptyVirtualService("reviews", "test", []string{"reviews"}))
+	vs.GetSpec()["http"] = append(vs.GetSpec()["http"].([]interface{}), map[string]interface{}{"timeout": "5s"})
+
+	rule, err := ParseRule(config.CustomValidationRule{Code: "ORG0001", ObjectType: "virtualservices", Path: "spec/http[*]/timeout", Condition: "exists", Severity: "error", Message: "Timeout is required"})
+	assert.NoError(err)
+
+	checks, valid := RuleChecker{Rule: rule, Object: vs}.Check()
+	assert.False(valid)
+	assert.Len(checks, 1)
+	assert.Equal("ORG0001", checks[0].Code)
+	assert.Equal("ORG0001 Timeout is required", checks[0].Message)
+	assert.Equal(models.ErrorSeverity, checks[0].Severity)
+	assert.Equal("spec/http[0]/timeout", checks[0].Path)
+}
+
+func TestRequiredNestedField(t *testing.T) {
+	assert := assert.New(t)
+
+	dr := data.CreateEmptyDestinationRule("test", "reviews", "reviews")
+	rule, err := ParseRule(config.CustomValidationRule{ObjectType: "destinationrules", Path: "spec/trafficPolicy/outlierDetection", Condition: "exists", Message: "Outlier detection is required"})
+	assert.NoError(err)
+
+	checks, valid := RuleChecker{Rule: rule, Object: dr}.Check()
+	assert.True(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+	assert.Equal("spec/trafficPolicy/outlierDetection", checks[0].Path)
+
+	dr.GetSpec()["trafficPolicy"] = map[string]interface{}{"outlierDetection": map[string]interface{}{"consecutiveErrors": 5}}
+	checks, valid = RuleChecker{Rule: rule, Object: dr}.Check()
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestForbiddenValue(t *testing.T) {
+	assert := assert.New(t)
+
+	gw := data.AddServerToGateway(data.CreateServer([]string{"*"}, uint32(443), "https", "https"),
+		data.AddServerToGateway(data.CreateServer([]string{"*"}, uint32(80), "http", "http"),
+			data.CreateEmptyGateway("ingress", "test", map[string]string{"istio": "ingressgateway"})))
+
+	rule, err := ParseRule(config.CustomValidationRule{Code: "ORG0003", ObjectType: "gateways", Path: "spec/servers[*]/port/number", Condition: "!= 80", Severity: "error", Message: "Port 80 is not allowed"})
+	assert.NoError(err)
+
+	checks, valid := RuleChecker{Rule: rule, Object: gw}.Check()
+	assert.False(valid)
+	assert.Len(checks, 1)
+	assert.Equal("spec/servers[0]/port/number", checks[0].Path)
+}
+
+func TestWildcardWithoutItems(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := data.CreateEmptyVirtualService("reviews", "test", []string{"reviews"})
+	rule, err := ParseRule(config.CustomValidationRule{ObjectType: "virtualservices", Path: "spec/http[*]/retries/attempts", Condition: "<= 3", Message: "Too many retries"})
+	assert.NoError(err)
+
+	checks, valid := RuleChecker{Rule: rule, Object: vs}.Check()
+	assert.True(valid)
+	assert.Empty(checks)
+}
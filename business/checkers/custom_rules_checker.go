@@ -0,0 +1,31 @@
+package checkers
+
+import (
+	"github.com/kiali/kiali/business/checkers/custom"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// CustomRulesChecker runs the custom validation rules defined in the configuration, loaded by custom.LoadRules.
+// IstioObjects are indexed by Istio type (plural), i.e. virtualservices
+type CustomRulesChecker struct {
+	Rules        []custom.Rule
+	IstioObjects map[string][]kubernetes.IstioObject
+}
+
+func (c CustomRulesChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, rule := range c.Rules {
+		objectType := models.ObjectTypeSingular[rule.ObjectType]
+		for _, object := range c.IstioObjects[rule.ObjectType] {
+			key, validation := EmptyValidValidation(object.GetObjectMeta().Name, object.GetObjectMeta().Namespace, objectType)
+			checks, valid := custom.RuleChecker{Rule: rule, Object: object}.Check()
+			validation.Checks = append(validation.Checks, checks...)
+			validation.Valid = valid
+			validations.MergeValidations(models.IstioValidations{key: validation})
+		}
+	}
+
+	return validations
+}
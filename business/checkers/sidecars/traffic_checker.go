@@ -0,0 +1,153 @@
+package sidecars
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// OutboundTraffic is a destination service called by a workload, as observed in the telemetry
+type OutboundTraffic struct {
+	SourceWorkload string
+	// Service is the host called, i.e. reviews.bookinfo.svc.cluster.local
+	Service          string
+	ServiceName      string
+	ServiceNamespace string
+}
+
+// TrafficChecker compares the egress hosts of a Sidecar with the outbound traffic of the workloads it selects.
+// Destinations called but not included in the egress hosts will be blocked, while hosts never called
+// widen the scope of the Sidecar more than needed.
+type TrafficChecker struct {
+	Sidecar kubernetes.IstioObject
+	// Sidecars of the namespace, as a Sidecar without selector only applies to the workloads not selected by others
+	Sidecars     []kubernetes.IstioObject
+	WorkloadList models.WorkloadList
+	Traffic      []OutboundTraffic
+}
+
+func (tc TrafficChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+	hosts, ok := EgressHostChecker{Sidecar: tc.Sidecar}.getHosts()
+	if !ok || len(hosts) == 0 {
+		return checks, valid
+	}
+
+	sns := tc.Sidecar.GetObjectMeta().Namespace
+	workloads := tc.selectedWorkloads()
+	called := map[string]bool{}
+	excluded := map[string]bool{}
+	observed := false
+
+	for _, t := range tc.Traffic {
+		if !workloads[t.SourceWorkload] {
+			continue
+		}
+		observed = true
+		included := false
+		for i, hwi := range hosts {
+			for j, h := range hwi.Hosts {
+				if host, ok := h.(string); ok && HostMatchesTraffic(host, sns, t) {
+					included = true
+					called[fmt.Sprintf("%d/%d", i, j)] = true
+				}
+			}
+		}
+		if !included && !excluded[t.Service] {
+			excluded[t.Service] = true
+			check := models.Build("sidecar.egress.trafficexcluded", "spec/egress")
+			check.Message = fmt.Sprintf("%s: %s", check.Message, t.Service)
+			checks = append(checks, &check)
+			valid = false
+		}
+	}
+
+	// Idle workloads would report every host, so they are only reported when some traffic is observed
+	if !observed {
+		return checks, valid
+	}
+	for i, hwi := range hosts {
+		for j, h := range hwi.Hosts {
+			host, ok := h.(string)
+			if !ok || !reportsUnusedHost(host) || called[fmt.Sprintf("%d/%d", i, j)] {
+				continue
+			}
+			checks = append(checks, buildCheck("sidecar.egress.hostwithouttraffic", i, j))
+		}
+	}
+
+	return checks, valid
+}
+
+// selectedWorkloads returns the names of the workloads whose proxies are configured by the Sidecar
+func (tc TrafficChecker) selectedWorkloads() map[string]bool {
+	selected := map[string]bool{}
+	sidecarLabels := common.GetWorkloadSelectorLabels(tc.Sidecar)
+	for _, wl := range tc.WorkloadList.Workloads {
+		wlLabels := labels.Set(wl.Labels)
+		if len(sidecarLabels) > 0 {
+			if labels.SelectorFromSet(sidecarLabels).Matches(wlLabels) {
+				selected[wl.Name] = true
+			}
+			continue
+		}
+		// Sidecars with selector take precedence over the namespace-wide one
+		selectedByOther := false
+		for _, s := range tc.Sidecars {
+			if otherLabels := common.GetWorkloadSelectorLabels(s); len(otherLabels) > 0 && labels.SelectorFromSet(otherLabels).Matches(wlLabels) {
+				selectedByOther = true
+				break
+			}
+		}
+		if !selectedByOther {
+			selected[wl.Name] = true
+		}
+	}
+	return selected
+}
+
+// HostMatchesTraffic returns true when an egress host, in namespace/dnsName format, includes the destination of the traffic
+func HostMatchesTraffic(host, sidecarNamespace string, traffic OutboundTraffic) bool {
+	hostNs, dnsName, valid := getHostComponents(host)
+	if !valid {
+		return false
+	}
+
+	switch hostNs {
+	case "*":
+	case ".":
+		if traffic.ServiceNamespace != sidecarNamespace {
+			return false
+		}
+	case "~":
+		return false
+	default:
+		if traffic.ServiceNamespace != hostNs {
+			return false
+		}
+	}
+
+	if dnsName == "*" {
+		return true
+	}
+	if strings.HasPrefix(dnsName, "*.") {
+		return strings.HasSuffix(traffic.Service, dnsName[1:])
+	}
+	return dnsName == traffic.Service
+}
+
+// reportsUnusedHost returns false for the hosts that are expected without traffic in the telemetry:
+// the whole mesh, none, and the control plane namespace
+func reportsUnusedHost(host string) bool {
+	hostNs, dnsName, valid := getHostComponents(host)
+	if !valid || hostNs == config.Get().IstioNamespace || hostNs == "~" {
+		return false
+	}
+	return hostNs != "*" || dnsName != "*"
+}
@@ -0,0 +1,145 @@
+package sidecars
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestTrafficIncludedInEgressHosts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := TrafficChecker{
+		Sidecar:      sidecarWithHosts([]interface{}{"./*", "istio-system/*"}),
+		WorkloadList: fakeWorkloads("productpage", "reviews"),
+		Traffic: []OutboundTraffic{
+			fakeTraffic("productpage", "reviews", "bookinfo"),
+			fakeTraffic("reviews", "ratings", "bookinfo"),
+		},
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestTrafficExcludedByEgressHosts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := TrafficChecker{
+		Sidecar:      sidecarWithHosts([]interface{}{"bookinfo/reviews.bookinfo.svc.cluster.local"}),
+		WorkloadList: fakeWorkloads("productpage"),
+		Traffic: []OutboundTraffic{
+			fakeTraffic("productpage", "reviews", "bookinfo"),
+			fakeTraffic("productpage", "details", "bookinfo"),
+			fakeTraffic("productpage", "details", "bookinfo"),
+			// Not selected by the Sidecar
+			fakeTraffic("other", "ratings", "bookinfo"),
+		},
+	}.Check()
+
+	assert.Len(validations, 1)
+	assert.False(valid)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal("spec/egress", validations[0].Path)
+	assert.True(strings.HasPrefix(validations[0].Message, models.CheckMessage("sidecar.egress.trafficexcluded")))
+	assert.True(strings.HasSuffix(validations[0].Message, "details.bookinfo.svc.cluster.local"))
+}
+
+func TestEgressHostWithoutTraffic(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := TrafficChecker{
+		Sidecar: sidecarWithHosts([]interface{}{
+			"./reviews.bookinfo.svc.cluster.local",
+			"./details.bookinfo.svc.cluster.local",
+			"istio-system/*",
+			"*/*",
+		}),
+		WorkloadList: fakeWorkloads("productpage"),
+		Traffic:      []OutboundTraffic{fakeTraffic("productpage", "reviews", "bookinfo")},
+	}.Check()
+
+	assert.Len(validations, 1)
+	assert.True(valid)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/egress[0]/hosts[1]", validations[0].Path)
+	assert.Equal(models.CheckMessage("sidecar.egress.hostwithouttraffic"), validations[0].Message)
+}
+
+func TestNoTrafficObserved(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := TrafficChecker{
+		Sidecar:      sidecarWithHosts([]interface{}{"./details.bookinfo.svc.cluster.local"}),
+		WorkloadList: fakeWorkloads("productpage"),
+		Traffic:      []OutboundTraffic{},
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestTrafficOfWorkloadsSelectedByOtherSidecar(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	selected := data.AddSelectorToSidecar(map[string]interface{}{
+		"labels": map[string]interface{}{"app": "productpage"},
+	}, sidecarWithHosts([]interface{}{"*/*"}))
+	namespaceWide := sidecarWithHosts([]interface{}{"./reviews.bookinfo.svc.cluster.local"})
+
+	validations, valid := TrafficChecker{
+		Sidecar:      namespaceWide,
+		Sidecars:     []kubernetes.IstioObject{selected, namespaceWide},
+		WorkloadList: fakeWorkloads("productpage", "reviews"),
+		Traffic: []OutboundTraffic{
+			fakeTraffic("productpage", "details", "bookinfo"),
+			fakeTraffic("reviews", "reviews", "bookinfo"),
+		},
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestHostMatchesTraffic(t *testing.T) {
+	assert := assert.New(t)
+
+	reviews := fakeTraffic("productpage", "reviews", "bookinfo")
+	assert.True(HostMatchesTraffic("*/*", "bookinfo", reviews))
+	assert.True(HostMatchesTraffic("./*", "bookinfo", reviews))
+	assert.False(HostMatchesTraffic("./*", "default", reviews))
+	assert.True(HostMatchesTraffic("bookinfo/*.bookinfo.svc.cluster.local", "default", reviews))
+	assert.False(HostMatchesTraffic("bookinfo/*.example.com", "default", reviews))
+	assert.True(HostMatchesTraffic("*/reviews.bookinfo.svc.cluster.local", "default", reviews))
+	assert.False(HostMatchesTraffic("default/reviews.bookinfo.svc.cluster.local", "default", reviews))
+	assert.False(HostMatchesTraffic("~/*", "bookinfo", reviews))
+	assert.False(HostMatchesTraffic("reviews", "bookinfo", reviews))
+}
+
+func fakeWorkloads(names ...string) models.WorkloadList {
+	workloads := make([]models.WorkloadListItem, 0, len(names))
+	for _, name := range names {
+		workloads = append(workloads, models.WorkloadListItem{Name: name, Labels: map[string]string{"app": name}})
+	}
+	return models.WorkloadList{Namespace: models.Namespace{Name: "bookinfo"}, Workloads: workloads}
+}
+
+func fakeTraffic(workload, service, namespace string) OutboundTraffic {
+	return OutboundTraffic{
+		SourceWorkload:   workload,
+		Service:          service + "." + namespace + ".svc.cluster.local",
+		ServiceName:      service,
+		ServiceNamespace: namespace,
+	}
+}
@@ -17,6 +17,8 @@ type SidecarChecker struct {
 	Services       []core_v1.Service
 	Namespaces     models.Namespaces
 	WorkloadList   models.WorkloadList
+	// OutboundTraffic of the namespace workloads. Traffic checks are skipped when it is nil
+	OutboundTraffic []sidecars.OutboundTraffic
 }
 
 func (s SidecarChecker) Check() models.IstioValidations {
@@ -63,6 +65,10 @@ func (s SidecarChecker) runChecks(sidecar kubernetes.IstioObject) models.IstioVa
 		sidecars.GlobalChecker{Sidecar: sidecar},
 	}
 
+	if s.OutboundTraffic != nil {
+		enabledCheckers = append(enabledCheckers, sidecars.TrafficChecker{Sidecar: sidecar, Sidecars: s.Sidecars, WorkloadList: s.WorkloadList, Traffic: s.OutboundTraffic})
+	}
+
 	for _, checker := range enabledCheckers {
 		checks, validChecker := checker.Check()
 		rrValidation.Checks = append(rrValidation.Checks, checks...)
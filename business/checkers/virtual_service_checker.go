@@ -45,6 +45,7 @@ func (in VirtualServiceChecker) runGroupChecks() models.IstioValidations {
 
 	enabledCheckers := []GroupChecker{
 		virtual_services.SingleHostChecker{Namespace: in.Namespace, Namespaces: in.Namespaces, VirtualServices: in.VirtualServices},
+		virtual_services.DelegateChecker{Namespace: in.Namespace, VirtualServices: in.VirtualServices},
 	}
 
 	for _, checker := range enabledCheckers {
@@ -60,7 +61,7 @@ func (in VirtualServiceChecker) runChecks(virtualService kubernetes.IstioObject)
 	key, rrValidation := EmptyValidValidation(virtualServiceName, virtualService.GetObjectMeta().Namespace, VirtualCheckerType)
 
 	enabledCheckers := []Checker{
-		virtual_services.RouteChecker{Route: virtualService},
+		virtual_services.RouteChecker{Route: virtualService, VirtualServices: in.VirtualServices},
 		virtual_services.SubsetPresenceChecker{Namespace: in.Namespace, Namespaces: in.Namespaces.GetNames(), DestinationRules: in.DestinationRules, VirtualService: virtualService},
 	}
 
@@ -0,0 +1,260 @@
+package virtual_services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// DelegateChecker validates the delegation between VirtualServices:
+// 1. The delegate exists.
+// 2. The delegate is not bound to hosts or gateways.
+// 3. There is no delegation cycle.
+// 4. The match conditions of the delegate don't conflict with the ones of the delegating route.
+type DelegateChecker struct {
+	Namespace       string
+	VirtualServices []kubernetes.IstioObject
+}
+
+// delegateRef is a reference to a delegate VirtualService from an HTTP route
+type delegateRef struct {
+	routeIdx  int
+	name      string
+	namespace string
+}
+
+func (d DelegateChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, vs := range d.VirtualServices {
+		http, _ := vs.GetSpec()["http"].([]interface{})
+		for _, ref := range getDelegates(vs) {
+			path := fmt.Sprintf("spec/http[%d]/delegate", ref.routeIdx)
+			delegate := findVirtualService(d.VirtualServices, ref.name, ref.namespace)
+			if delegate == nil {
+				if ref.namespace != vs.GetObjectMeta().Namespace {
+					addDelegateCheck(validations, vs, "validation.unable.cross-namespace", path, nil)
+				} else {
+					addDelegateCheck(validations, vs, "virtualservices.delegate.notfound", path, nil)
+				}
+				continue
+			}
+
+			if d.reaches(delegate, vs, map[models.IstioValidationKey]bool{}) {
+				addDelegateCheck(validations, vs, "virtualservices.delegate.cycle", path, delegate)
+			}
+
+			for _, field := range []string{"hosts", "gateways"} {
+				if values, ok := delegate.GetSpec()[field].([]interface{}); ok && len(values) > 0 {
+					addDelegateCheck(validations, delegate, "virtualservices.delegate.hostsorgateways", "spec/"+field, vs)
+				} else if values, ok := delegate.GetSpec()[field].([]string); ok && len(values) > 0 {
+					addDelegateCheck(validations, delegate, "virtualservices.delegate.hostsorgateways", "spec/"+field, vs)
+				}
+			}
+
+			parentRoute, _ := http[ref.routeIdx].(map[string]interface{})
+			parentMatches, _ := parentRoute["match"].([]interface{})
+			if len(parentMatches) == 0 {
+				continue
+			}
+			delegateHttp, _ := delegate.GetSpec()["http"].([]interface{})
+			for i, r := range delegateHttp {
+				route, _ := r.(map[string]interface{})
+				matches, _ := route["match"].([]interface{})
+				for j, m := range matches {
+					match, _ := m.(map[string]interface{})
+					if conflictsWithAll(parentMatches, match) {
+						addDelegateCheck(validations, delegate, "virtualservices.delegate.matchconflict", fmt.Sprintf("spec/http[%d]/match[%d]", i, j), vs)
+					}
+				}
+			}
+		}
+	}
+
+	return validations
+}
+
+// reaches returns true when target is found following the delegates of vs
+func (d DelegateChecker) reaches(vs, target kubernetes.IstioObject, visited map[models.IstioValidationKey]bool) bool {
+	key := virtualServiceKey(vs)
+	if visited[key] {
+		return false
+	}
+	visited[key] = true
+
+	for _, ref := range getDelegates(vs) {
+		delegate := findVirtualService(d.VirtualServices, ref.name, ref.namespace)
+		if delegate == nil {
+			continue
+		}
+		if virtualServiceKey(delegate) == virtualServiceKey(target) || d.reaches(delegate, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func addDelegateCheck(validations models.IstioValidations, vs kubernetes.IstioObject, checkId, path string, reference kubernetes.IstioObject) {
+	check := models.Build(checkId, path)
+	key := virtualServiceKey(vs)
+	validation := &models.IstioValidation{
+		Name:       key.Name,
+		ObjectType: key.ObjectType,
+		Valid:      check.Severity != models.ErrorSeverity,
+		Checks:     []*models.IstioCheck{&check},
+		References: make([]models.IstioValidationKey, 0, 1),
+	}
+	if reference != nil {
+		validation.References = append(validation.References, virtualServiceKey(reference))
+	}
+	validations.MergeValidations(models.IstioValidations{key: validation})
+}
+
+func virtualServiceKey(vs kubernetes.IstioObject) models.IstioValidationKey {
+	return models.BuildKey("virtualservice", vs.GetObjectMeta().Name, vs.GetObjectMeta().Namespace)
+}
+
+// getDelegates returns the delegates referenced by the HTTP routes of the VirtualService.
+// The namespace of the delegate defaults to the one of the VirtualService.
+func getDelegates(vs kubernetes.IstioObject) []delegateRef {
+	refs := make([]delegateRef, 0)
+	http, ok := vs.GetSpec()["http"].([]interface{})
+	if !ok {
+		return refs
+	}
+	for i, r := range http {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		delegate, ok := route["delegate"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref := delegateRef{routeIdx: i, namespace: vs.GetObjectMeta().Namespace}
+		ref.name, _ = delegate["name"].(string)
+		if namespace, ok := delegate["namespace"].(string); ok && namespace != "" {
+			ref.namespace = namespace
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func findVirtualService(virtualServices []kubernetes.IstioObject, name, namespace string) kubernetes.IstioObject {
+	for _, vs := range virtualServices {
+		if vs.GetObjectMeta().Name == name && vs.GetObjectMeta().Namespace == namespace {
+			return vs
+		}
+	}
+	return nil
+}
+
+// conflictsWithAll returns true when the delegate match conflicts with every parent match,
+// as the delegate route is merged with each one of the parent matches.
+func conflictsWithAll(parentMatches []interface{}, match map[string]interface{}) bool {
+	for _, pm := range parentMatches {
+		parent, _ := pm.(map[string]interface{})
+		if !matchConflict(parent, match) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchConflict follows the rules used by Istio to merge the match of a delegate route with the match of its parent
+func matchConflict(parent, delegate map[string]interface{}) bool {
+	for _, field := range []string{"uri", "scheme", "method", "authority"} {
+		if stringMatchConflict(parent[field], delegate[field]) {
+			return true
+		}
+	}
+	for _, field := range []string{"headers", "withoutHeaders", "queryParams"} {
+		parentValues, _ := parent[field].(map[string]interface{})
+		delegateValues, _ := delegate[field].(map[string]interface{})
+		for key, value := range delegateValues {
+			if stringMatchConflict(parentValues[key], value) {
+				return true
+			}
+		}
+	}
+
+	parentIgnoreCase, _ := parent["ignoreUriCase"].(bool)
+	delegateIgnoreCase, _ := delegate["ignoreUriCase"].(bool)
+	if parentIgnoreCase != delegateIgnoreCase {
+		return true
+	}
+	if parent["port"] != nil && delegate["port"] != nil && fmt.Sprint(parent["port"]) != fmt.Sprint(delegate["port"]) {
+		return true
+	}
+	if ns, ok := parent["sourceNamespace"].(string); ok && ns != "" && delegate["sourceNamespace"] != ns {
+		return true
+	}
+
+	// Parent must have a superset of the source labels and gateways
+	parentLabels, _ := parent["sourceLabels"].(map[string]interface{})
+	delegateLabels, _ := delegate["sourceLabels"].(map[string]interface{})
+	for key, value := range delegateLabels {
+		if v, ok := parentLabels[key]; ok && v != value {
+			return true
+		}
+	}
+	parentGateways, _ := parent["gateways"].([]interface{})
+	delegateGateways, _ := delegate["gateways"].([]interface{})
+	if len(parentGateways) > 0 {
+	Gateways:
+		for _, dg := range delegateGateways {
+			for _, pg := range parentGateways {
+				if dg == pg {
+					continue Gateways
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func stringMatchConflict(parent, delegate interface{}) bool {
+	p, ok := parent.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	d, ok := delegate.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	pExact, _ := p["exact"].(string)
+	pPrefix, _ := p["prefix"].(string)
+	pRegex, _ := p["regex"].(string)
+	dExact, _ := d["exact"].(string)
+	dPrefix, _ := d["prefix"].(string)
+	dRegex, _ := d["regex"].(string)
+
+	// Regular expressions can't be combined with other matches
+	if pRegex != "" && (dRegex != "" || dPrefix != "" || dExact != "") {
+		return true
+	}
+	if dRegex != "" && (pPrefix != "" || pExact != "") {
+		return true
+	}
+	if pExact != "" {
+		if dPrefix != "" && !strings.HasPrefix(pExact, dPrefix) {
+			return true
+		}
+		if dExact != "" && dExact != pExact {
+			return true
+		}
+	}
+	if pPrefix != "" {
+		if dPrefix != "" && !strings.HasPrefix(dPrefix, pPrefix) {
+			return true
+		}
+		if dExact != "" && !strings.HasPrefix(dExact, pPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package virtual_services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data/validations"
+)
+
+func TestValidDelegates(t *testing.T) {
+	vals := delegateCheckerPrep("delegate-valid.yaml", t)
+	validations.ValidationsTestAsserter{T: t, Validations: vals}.AssertNoValidations()
+}
+
+func TestDelegateNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	vals := delegateCheckerPrep("delegate-not-found.yaml", t)
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertValidationsPresent(1)
+	tb.AssertValidationAt(vsKey("bookinfo-root"), models.ErrorSeverity, "spec/http[0]/delegate", "virtualservices.delegate.notfound")
+
+	// Delegates in other namespaces can't be verified
+	checks := vals[vsKey("bookinfo-root")].Checks
+	assert.Len(checks, 2)
+	assert.Equal("spec/http[1]/delegate", checks[1].Path)
+	assert.Equal(models.CheckMessage("validation.unable.cross-namespace"), checks[1].Message)
+}
+
+func TestDelegateWithHostsAndGateways(t *testing.T) {
+	assert := assert.New(t)
+
+	vals := delegateCheckerPrep("delegate-with-hosts.yaml", t)
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertValidationsPresent(1)
+	tb.AssertValidationAt(vsKey("reviews-delegate"), models.ErrorSeverity, "spec/hosts", "virtualservices.delegate.hostsorgateways")
+
+	validation := vals[vsKey("reviews-delegate")]
+	assert.Len(validation.Checks, 2)
+	assert.Equal("spec/gateways", validation.Checks[1].Path)
+	assert.Equal([]models.IstioValidationKey{vsKey("bookinfo-root")}, validation.References)
+}
+
+func TestDelegateCycle(t *testing.T) {
+	vals := delegateCheckerPrep("delegate-cycle.yaml", t)
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertValidationsPresent(2)
+	tb.AssertValidationAt(vsKey("reviews-delegate"), models.ErrorSeverity, "spec/http[0]/delegate", "virtualservices.delegate.cycle")
+	tb.AssertValidationAt(vsKey("ratings-delegate"), models.ErrorSeverity, "spec/http[0]/delegate", "virtualservices.delegate.cycle")
+}
+
+func TestDelegateMatchConflict(t *testing.T) {
+	assert := assert.New(t)
+
+	vals := delegateCheckerPrep("delegate-match-conflict.yaml", t)
+	tb := validations.ValidationsTestAsserter{T: t, Validations: vals}
+	tb.AssertValidationsPresent(1)
+	tb.AssertValidationAt(vsKey("reviews-delegate"), models.ErrorSeverity, "spec/http[0]/match[1]", "virtualservices.delegate.matchconflict")
+
+	checks := vals[vsKey("reviews-delegate")].Checks
+	assert.Len(checks, 2)
+	assert.Equal("spec/http[1]/match[0]", checks[1].Path)
+}
+
+func TestStringMatchConflict(t *testing.T) {
+	assert := assert.New(t)
+
+	exact := func(v string) map[string]interface{} { return map[string]interface{}{"exact": v} }
+	prefix := func(v string) map[string]interface{} { return map[string]interface{}{"prefix": v} }
+	regex := func(v string) map[string]interface{} { return map[string]interface{}{"regex": v} }
+
+	assert.False(stringMatchConflict(nil, exact("/a")))
+	assert.False(stringMatchConflict(exact("/a"), exact("/a")))
+	assert.True(stringMatchConflict(exact("/a"), exact("/b")))
+	assert.False(stringMatchConflict(exact("/a/b"), prefix("/a")))
+	assert.True(stringMatchConflict(exact("/a/b"), prefix("/c")))
+	assert.False(stringMatchConflict(prefix("/a"), prefix("/a/b")))
+	assert.True(stringMatchConflict(prefix("/a/b"), prefix("/a")))
+	assert.False(stringMatchConflict(prefix("/a"), exact("/a/b")))
+	assert.True(stringMatchConflict(regex("/a.*"), prefix("/a")))
+	assert.True(stringMatchConflict(prefix("/a"), regex("/a.*")))
+}
+
+func delegateCheckerPrep(scenario string, t *testing.T) models.IstioValidations {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	loader := yamlFixtureLoaderFor(scenario)
+	err := loader.Load()
+	if err != nil {
+		t.Error("Error loading test data.")
+	}
+
+	return DelegateChecker{
+		Namespace:       "bookinfo",
+		VirtualServices: loader.GetResources("VirtualService"),
+	}.Check()
+}
+
+func vsKey(name string) models.IstioValidationKey {
+	return models.IstioValidationKey{ObjectType: "virtualservice", Name: name, Namespace: "bookinfo"}
+}
@@ -11,6 +11,8 @@ import (
 
 type RouteChecker struct {
 	Route kubernetes.IstioObject
+	// VirtualServices are used to resolve the delegates of the routes
+	VirtualServices []kubernetes.IstioObject
 }
 
 // Check returns both an array of IstioCheck and a boolean indicating if the current route rule is valid.
@@ -19,6 +21,7 @@ type RouteChecker struct {
 // 2. All weights have value between 0 and 100.
 // 3. Sum of all weights are 100 (if only one weight, then it assumes that is 100).
 // 4. All the route has to have weight label.
+// 5. Routes with delegate don't define their own destinations, and inherit some routes from the delegate.
 func (route RouteChecker) Check() ([]*models.IstioCheck, bool) {
 	checks, valid := make([]*models.IstioCheck, 0), true
 	protocols := []string{"http", "tcp", "tls"}
@@ -35,6 +38,7 @@ func (route RouteChecker) Check() ([]*models.IstioCheck, bool) {
 func (route RouteChecker) checkRoutesFor(kind string) ([]*models.IstioCheck, bool) {
 	validations := make([]*models.IstioCheck, 0)
 	valid := true
+	namespace, virtualServices := route.Route.GetObjectMeta().Namespace, route.VirtualServices
 
 	http := route.Route.GetSpec()[kind]
 	if http == nil {
@@ -49,6 +53,13 @@ func (route RouteChecker) checkRoutesFor(kind string) ([]*models.IstioCheck, boo
 
 	for routeIdx := 0; routeIdx < slice.Len(); routeIdx++ {
 		route, ok := slice.Index(routeIdx).Interface().(map[string]interface{})
+		if ok && kind == "http" && route["delegate"] != nil {
+			// Destinations of the route are inherited from the delegate. Istio only supports delegation on HTTP routes.
+			cs, v := checkDelegatedRoute(routeIdx, route, namespace, virtualServices)
+			validations = append(validations, cs...)
+			valid = valid && v
+			continue
+		}
 		if !ok || route["route"] == nil {
 			continue
 		}
@@ -79,6 +90,39 @@ func (route RouteChecker) checkRoutesFor(kind string) ([]*models.IstioCheck, boo
 	return validations, valid
 }
 
+// checkDelegatedRoute checks a route with delegate: destinations can't be set in the route itself,
+// and the delegate has to define some HTTP route to be inherited.
+func checkDelegatedRoute(routeIdx int, route map[string]interface{}, namespace string, virtualServices []kubernetes.IstioObject) ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+	valid := true
+
+	for _, field := range []string{"route", "redirect"} {
+		if route[field] != nil {
+			validation := models.Build("virtualservices.delegate.routeset", fmt.Sprintf("spec/http[%d]/%s", routeIdx, field))
+			validations = append(validations, &validation)
+			valid = false
+		}
+	}
+
+	delegate, ok := route["delegate"].(map[string]interface{})
+	if !ok {
+		return validations, valid
+	}
+	name, _ := delegate["name"].(string)
+	if ns, ok := delegate["namespace"].(string); ok && ns != "" {
+		namespace = ns
+	}
+	// Missing delegates are reported by the DelegateChecker
+	if vs := findVirtualService(virtualServices, name, namespace); vs != nil {
+		if http, ok := vs.GetSpec()["http"].([]interface{}); !ok || len(http) == 0 {
+			validation := models.Build("virtualservices.delegate.noroutes", fmt.Sprintf("spec/http[%d]/delegate", routeIdx))
+			validations = append(validations, &validation)
+		}
+	}
+
+	return validations, valid
+}
+
 func trackSubset(routeIdx int, kind string, destinationWeights reflect.Value, checks *[]*models.IstioCheck) {
 	subsetCollitions := map[string][]int{}
 
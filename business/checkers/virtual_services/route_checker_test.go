@@ -15,7 +15,7 @@ func TestServiceWellVirtualServiceValidation(t *testing.T) {
 	assert := assert.New(t)
 
 	// Setup mocks
-	validations, valid := RouteChecker{fakeValidVirtualService()}.Check()
+	validations, valid := RouteChecker{Route: fakeValidVirtualService()}.Check()
 
 	// Well configured object
 	assert.True(valid)
@@ -26,7 +26,7 @@ func TestServiceWellVirtualServiceValidation(t *testing.T) {
 func TestServiceMultipleChecks(t *testing.T) {
 	assert := assert.New(t)
 
-	validations, valid := RouteChecker{fakeOneRouteUnder100()}.Check()
+	validations, valid := RouteChecker{Route: fakeOneRouteUnder100()}.Check()
 
 	// wrong weight'ed route rule
 	assert.True(valid)
@@ -40,7 +40,7 @@ func TestServiceMultipleChecks(t *testing.T) {
 func TestVSWithRepeatingSubsets(t *testing.T) {
 	assert := assert.New(t)
 
-	validations, valid := RouteChecker{fakeRepeatedSubset()}.Check()
+	validations, valid := RouteChecker{Route: fakeRepeatedSubset()}.Check()
 	assert.True(valid)
 	assert.NotEmpty(validations)
 	assert.Len(validations, 4)
@@ -83,3 +83,62 @@ func fakeRepeatedSubset() kubernetes.IstioObject {
 
 	return validVirtualService
 }
+
+func TestDelegatedRouteWithDestinations(t *testing.T) {
+	assert := assert.New(t)
+
+	root := fakeDelegatingVirtualService()
+	http := root.GetSpec()["http"].([]interface{})
+	http[0].(map[string]interface{})["route"] = []interface{}{data.CreateRoute("reviews", "v1", -1)}
+
+	validations, valid := RouteChecker{Route: root, VirtualServices: []kubernetes.IstioObject{root, fakeValidVirtualService()}}.Check()
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("virtualservices.delegate.routeset"), validations[0].Message)
+	assert.Equal("spec/http[0]/route", validations[0].Path)
+}
+
+func TestDelegatedRouteWithoutInheritedRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	root := fakeDelegatingVirtualService()
+	validations, valid := RouteChecker{Route: root, VirtualServices: []kubernetes.IstioObject{root, fakeValidVirtualService()}}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+
+	delegate := data.CreateEmptyVirtualService("reviews-well", "test", nil)
+	validations, valid = RouteChecker{Route: root, VirtualServices: []kubernetes.IstioObject{root, delegate}}.Check()
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("virtualservices.delegate.noroutes"), validations[0].Message)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/http[0]/delegate", validations[0].Path)
+}
+
+func TestDelegateOnlyOnHTTPRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	// Istio ignores delegate on TCP routes, so the route destinations are checked as usual
+	vs := data.CreateEmptyVirtualService("reviews-tcp", "test", []string{"reviews"})
+	vs.GetSpec()["tcp"] = []interface{}{
+		map[string]interface{}{
+			"delegate": map[string]interface{}{"name": "reviews-well"},
+			"route":    []interface{}{data.CreateRoute("reviews", "v1", 50)},
+		},
+	}
+	validations, valid := RouteChecker{Route: vs, VirtualServices: []kubernetes.IstioObject{vs}}.Check()
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("virtualservices.route.singleweight"), validations[0].Message)
+	assert.Equal("spec/tcp[0]/route[0]/weight", validations[0].Path)
+}
+
+func fakeDelegatingVirtualService() kubernetes.IstioObject {
+	root := data.CreateEmptyVirtualService("reviews-root", "test", []string{"reviews"})
+	root.GetSpec()["http"] = []interface{}{
+		map[string]interface{}{
+			"delegate": map[string]interface{}{"name": "reviews-well"},
+		},
+	}
+	return root
+}
@@ -0,0 +1,51 @@
+package business
+
+import (
+	"time"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// containerResourcesRateInterval is the interval of the CPU rates of the container resources of the workload details
+const containerResourcesRateInterval = "1m"
+
+// fetchContainerResources returns the current CPU and memory of the containers of the pods. They come from Prometheus and
+// are an additional information of the workload details: no resource is returned without Prometheus client, and on any
+// error, which is logged.
+func fetchContainerResources(prom prometheus.ClientInterface, namespace string, pods models.Pods) *models.WorkloadResources {
+	if prom == nil || len(pods) == 0 {
+		return nil
+	}
+	metrics, err := prom.GetContainerResources(namespace, podNames(pods), containerResourcesRateInterval, time.Now())
+	if err != nil {
+		log.Errorf("Error fetching container resources per namespace %s: %s", namespace, err)
+		return nil
+	}
+	resources := &models.WorkloadResources{}
+	resources.Parse(metrics, sidecarContainers(pods))
+	if len(resources.Containers) == 0 && resources.Sidecar == nil {
+		return nil
+	}
+	return resources
+}
+
+func podNames(pods models.Pods) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+// sidecarContainers returns the names of the containers injected by Istio in the pods
+func sidecarContainers(pods models.Pods) map[string]bool {
+	sidecars := map[string]bool{}
+	for _, pod := range pods {
+		for _, container := range pod.IstioContainers {
+			sidecars[container.Name] = true
+		}
+	}
+	return sidecars
+}
@@ -0,0 +1,129 @@
+package business
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// EnvoyConfigCriteria selects the sections of the Envoy config of a pod, and filters their items
+type EnvoyConfigCriteria struct {
+	// Sections returned: bootstrap, clusters, listeners or routes. All of them when empty.
+	Sections []string
+	// Port of the listeners, when not zero
+	ListenerPort int
+	// Service (FQDN) of the clusters, when not empty
+	ClusterService string
+	// Name of the route configs, when not empty
+	RouteConfig string
+}
+
+func (criteria EnvoyConfigCriteria) include(section string) bool {
+	if len(criteria.Sections) == 0 {
+		return true
+	}
+	for _, s := range criteria.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// GetConfigDump returns the configuration received by the Envoy proxy of a pod, parsed and filtered by the criteria.
+// The Envoy admin interface only listens on localhost, so the config_dump is requested with the user client through the
+// pods exec subresource: a Forbidden error is returned unless the user can create pods/exec in the namespace.
+func (in *WorkloadService) GetConfigDump(namespace, podName string, criteria EnvoyConfigCriteria) (*models.EnvoyConfigDump, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetConfigDump")
+	defer promtimer.ObserveNow(&err)
+
+	for _, section := range criteria.Sections {
+		switch section {
+		case models.EnvoyBootstrapSection, models.EnvoyClustersSection, models.EnvoyListenersSection, models.EnvoyRoutesSection:
+		default:
+			err = errors.NewBadRequest(fmt.Sprintf("unknown Envoy config section [%s]", section))
+			return nil, err
+		}
+	}
+
+	if err = in.checkPodExec(namespace, podName); err != nil {
+		return nil, err
+	}
+	pod, err := in.k8s.GetPod(namespace, podName)
+	if err != nil {
+		return nil, err
+	}
+	hasProxy := false
+	for _, c := range pod.Spec.Containers {
+		hasProxy = hasProxy || c.Name == kubernetes.IstioProxyContainerName
+	}
+	if !hasProxy {
+		err = errors.NewBadRequest(fmt.Sprintf("pod [%s] has no Istio proxy", podName))
+		return nil, err
+	}
+
+	var configDump *kubernetes.ConfigDump
+	if configDump, err = in.k8s.GetConfigDump(namespace, podName); err != nil {
+		return nil, err
+	}
+	dump := &models.EnvoyConfigDump{}
+	dump.Parse(configDump)
+
+	if !criteria.include(models.EnvoyBootstrapSection) {
+		dump.Bootstrap = nil
+	}
+	if !criteria.include(models.EnvoyClustersSection) {
+		dump.Clusters = nil
+	} else if criteria.ClusterService != "" {
+		clusters := []models.EnvoyCluster{}
+		for _, c := range dump.Clusters {
+			if c.Service == criteria.ClusterService {
+				clusters = append(clusters, c)
+			}
+		}
+		dump.Clusters = clusters
+	}
+	if !criteria.include(models.EnvoyListenersSection) {
+		dump.Listeners = nil
+	} else if criteria.ListenerPort != 0 {
+		listeners := []models.EnvoyListener{}
+		for _, l := range dump.Listeners {
+			if l.Port == criteria.ListenerPort {
+				listeners = append(listeners, l)
+			}
+		}
+		dump.Listeners = listeners
+	}
+	if !criteria.include(models.EnvoyRoutesSection) {
+		dump.Routes = nil
+	} else if criteria.RouteConfig != "" {
+		routes := []models.EnvoyRoute{}
+		for _, r := range dump.Routes {
+			if r.RouteConfig == criteria.RouteConfig {
+				routes = append(routes, r)
+			}
+		}
+		dump.Routes = routes
+	}
+	return dump, nil
+}
+
+// checkPodExec returns a Forbidden error unless a SelfSubjectAccessReview allows the user to exec in the pods of the
+// namespace, as the Envoy admin interface of the proxies is requested through the pods exec subresource
+func (in *WorkloadService) checkPodExec(namespace, podName string) error {
+	ssars, err := in.k8s.GetSelfSubjectAccessReview(namespace, "", "pods/exec", []string{"create"})
+	if err != nil {
+		return err
+	}
+	if len(ssars) == 0 || !ssars[0].Status.Allowed {
+		return errors.NewForbidden(schema.GroupResource{Resource: "pods/exec"}, podName,
+			fmt.Errorf("user can't create pods/exec in namespace [%s], which is required to reach the Envoy admin interface of the proxy", namespace))
+	}
+	return nil
+}
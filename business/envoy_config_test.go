@@ -0,0 +1,85 @@
+package business
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	auth_v1 "k8s.io/api/authorization/v1"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestGetConfigDump(t *testing.T) {
+	assert := assert.New(t)
+
+	configDump := &kubernetes.ConfigDump{}
+	assert.NoError(json.Unmarshal([]byte(`{"configs": [
+		{"bootstrap": {"node": {"id": "sidecar~10.1.1.5~reviews-v1.bookinfo~bookinfo.svc.cluster.local"}}},
+		{"dynamic_active_clusters": [
+			{"cluster": {"name": "outbound|9080||reviews.bookinfo.svc.cluster.local"}},
+			{"cluster": {"name": "outbound|9080||ratings.bookinfo.svc.cluster.local"}}
+		]},
+		{"dynamic_listeners": [
+			{"active_state": {"listener": {"address": {"socket_address": {"address": "0.0.0.0", "port_value": 9080}}}}},
+			{"active_state": {"listener": {"address": {"socket_address": {"address": "0.0.0.0", "port_value": 15006}}}}}
+		]}
+	]}`), configDump))
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetPod", "bookinfo", "reviews-v1").Return(&core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1", Namespace: "bookinfo"},
+		Spec:       core_v1.PodSpec{Containers: []core_v1.Container{{Name: "reviews"}, {Name: kubernetes.IstioProxyContainerName}}},
+	}, nil)
+	k8s.On("GetPod", "bookinfo", "mysql").Return(&core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "mysql", Namespace: "bookinfo"},
+		Spec:       core_v1.PodSpec{Containers: []core_v1.Container{{Name: "mysql"}}},
+	}, nil)
+	k8s.On("GetConfigDump", "bookinfo", "reviews-v1").Return(configDump, nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "", "pods/exec", []string{"create"}).Return([]*auth_v1.SelfSubjectAccessReview{
+		{Status: auth_v1.SubjectAccessReviewStatus{Allowed: true}},
+	}, nil)
+	k8s.On("GetSelfSubjectAccessReview", "travel", "", "pods/exec", []string{"create"}).Return([]*auth_v1.SelfSubjectAccessReview{
+		{Status: auth_v1.SubjectAccessReviewStatus{Allowed: false}},
+	}, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	dump, err := layer.Workload.GetConfigDump("bookinfo", "reviews-v1", EnvoyConfigCriteria{})
+	assert.NoError(err)
+	assert.NotNil(dump.Bootstrap)
+	assert.Len(dump.Clusters, 2)
+	assert.Len(dump.Listeners, 2)
+
+	dump, err = layer.Workload.GetConfigDump("bookinfo", "reviews-v1", EnvoyConfigCriteria{
+		Sections:       []string{models.EnvoyClustersSection, models.EnvoyListenersSection},
+		ClusterService: "ratings.bookinfo.svc.cluster.local",
+		ListenerPort:   15006,
+	})
+	assert.NoError(err)
+	assert.Nil(dump.Bootstrap)
+	assert.Len(dump.Clusters, 1)
+	assert.Equal("ratings.bookinfo.svc.cluster.local", dump.Clusters[0].Service)
+	assert.Len(dump.Listeners, 1)
+	assert.Equal(15006, dump.Listeners[0].Port)
+
+	_, err = layer.Workload.GetConfigDump("bookinfo", "reviews-v1", EnvoyConfigCriteria{Sections: []string{"secrets"}})
+	assert.True(errors.IsBadRequest(err))
+
+	_, err = layer.Workload.GetConfigDump("bookinfo", "mysql", EnvoyConfigCriteria{})
+	assert.True(errors.IsBadRequest(err))
+
+	// Users who can't exec in the pods can't reach the Envoy admin interface
+	_, err = layer.Workload.GetConfigDump("travel", "cars-v1", EnvoyConfigCriteria{})
+	assert.True(errors.IsForbidden(err))
+	k8s.AssertNotCalled(t, "GetConfigDump", "travel", "cars-v1")
+}
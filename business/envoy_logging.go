@@ -0,0 +1,341 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// maxProxyLogLevelReset is the longest time a proxy log level can be changed for before being reset
+const maxProxyLogLevelReset = 24 * time.Hour
+
+const (
+	// proxyLogLevelResetAnnotation holds the time the log levels of the proxy of a pod are reset, in RFC 3339
+	proxyLogLevelResetAnnotation = "kiali.io/proxy-log-level-reset"
+	// proxyLogLevelPreviousAnnotation holds the log levels restored by the reset, in JSON
+	proxyLogLevelPreviousAnnotation = "kiali.io/proxy-log-level-previous"
+)
+
+var (
+	validProxyLogLevels = map[string]bool{"trace": true, "debug": true, "info": true, "warning": true, "error": true, "critical": true, "off": true}
+	proxyLoggerRe       = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+// proxyLogLevelReset is a pending reset of the log levels of a proxy, with the levels restored
+type proxyLogLevelReset struct {
+	timer    *time.Timer
+	time     time.Time
+	previous map[string]string
+}
+
+// proxyLogLevelResets holds the pending resets of the proxy log levels, by namespace and pod
+var proxyLogLevelResets = struct {
+	sync.Mutex
+	resets map[string]*proxyLogLevelReset
+}{resets: map[string]*proxyLogLevelReset{}}
+
+// getProxyLoggingClient returns the client resetting the log levels, when the user changing them may no longer be logged in
+var getProxyLoggingClient = getKialiSAClient
+
+// GetProxyLogLevels returns the log level of each logger of the Envoy proxy of a pod, with the time of the pending reset.
+// As for the config_dump, the user must be allowed to create pods/exec in the namespace.
+func (in *WorkloadService) GetProxyLogLevels(namespace, podName string) (*models.EnvoyLogLevels, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetProxyLogLevels")
+	defer promtimer.ObserveNow(&err)
+
+	if err = in.checkPodExec(namespace, podName); err != nil {
+		return nil, err
+	}
+	var loggers map[string]string
+	if loggers, err = in.k8s.SetProxyLogLevel(namespace, podName, "", ""); err != nil {
+		return nil, err
+	}
+	return proxyLogLevels(namespace, podName, loggers), nil
+}
+
+// SetProxyLogLevel changes the log level of the Envoy proxy of a pod, for all the loggers or for one of them.
+// The user must be allowed to create pods/exec in the namespace, as the Envoy admin interface is reached through the pods
+// exec subresource. With a reset duration, the levels before the first change are restored when it expires; a change
+// without reset duration cancels the pending reset.
+// The reset is kept in the annotations of the pod, so it survives a restart: the user must also be allowed to patch the
+// pod. The reset is done with the Kiali service account, which must be allowed to create pods/exec and to patch pods in
+// the namespace, i.e. cluster-wide when all the namespaces are accessible.
+func (in *WorkloadService) SetProxyLogLevel(namespace, podName string, request models.EnvoyLogLevelRequest) (*models.EnvoyLogLevels, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "SetProxyLogLevel")
+	defer promtimer.ObserveNow(&err)
+
+	if !validProxyLogLevels[request.Level] {
+		err = errors2.NewBadRequest(fmt.Sprintf("invalid log level [%s]", request.Level))
+		return nil, err
+	}
+	if request.Logger != "" && !proxyLoggerRe.MatchString(request.Logger) {
+		err = errors2.NewBadRequest(fmt.Sprintf("invalid logger [%s]", request.Logger))
+		return nil, err
+	}
+	var resetAfter time.Duration
+	if request.ResetAfter != "" {
+		if resetAfter, err = time.ParseDuration(request.ResetAfter); err != nil || resetAfter <= 0 || resetAfter > maxProxyLogLevelReset {
+			err = errors2.NewBadRequest(fmt.Sprintf("invalid reset duration [%s], it must be positive and at most %v", request.ResetAfter, maxProxyLogLevelReset))
+			return nil, err
+		}
+	}
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if err = in.checkPodExec(namespace, podName); err != nil {
+		return nil, err
+	}
+
+	var previous map[string]string
+	var resetTime time.Time
+	if resetAfter > 0 {
+		if err = in.checkPodPatch(namespace, podName); err != nil {
+			return nil, err
+		}
+		if err = checkProxyLogLevelReset(namespace, podName); err != nil {
+			return nil, err
+		}
+		// The levels restored are still the ones before the first change
+		if previous = pendingProxyLogLevels(namespace, podName); previous == nil {
+			if previous, err = in.k8s.SetProxyLogLevel(namespace, podName, "", ""); err != nil {
+				return nil, err
+			}
+		}
+		resetTime = util.Clock.Now().Add(resetAfter).Truncate(time.Second)
+		if err = patchProxyLogLevelReset(in.k8s, namespace, podName, previous, &resetTime); err != nil {
+			return nil, err
+		}
+	}
+	var loggers map[string]string
+	if loggers, err = in.k8s.SetProxyLogLevel(namespace, podName, request.Logger, request.Level); err != nil {
+		return nil, err
+	}
+
+	if resetAfter > 0 {
+		scheduleProxyLogLevelReset(namespace, podName, previous, resetTime)
+	} else if cancelProxyLogLevelReset(namespace, podName) {
+		if patchErr := patchProxyLogLevelReset(in.k8s, namespace, podName, nil, nil); patchErr != nil {
+			log.Errorf("Error removing the log level reset of the proxy of pod [%s/%s]: %v", namespace, podName, patchErr)
+		}
+	}
+	log.Infof("Log level of the proxy of pod [%s/%s] set to [%s] for logger [%s]", namespace, podName, request.Level, request.Logger)
+	return proxyLogLevels(namespace, podName, loggers), nil
+}
+
+// checkPodPatch returns a Forbidden error unless a SelfSubjectAccessReview allows the user to patch the pods, to set
+// the annotations of the reset
+func (in *WorkloadService) checkPodPatch(namespace, podName string) error {
+	ssars, err := in.k8s.GetSelfSubjectAccessReview(namespace, "", "pods", []string{"patch"})
+	if err != nil {
+		return err
+	}
+	if len(ssars) == 0 || !ssars[0].Status.Allowed {
+		return errors2.NewForbidden(schema.GroupResource{Resource: "pods"}, podName, fmt.Errorf("user can't patch the pods of namespace [%s] to reset the log levels", namespace))
+	}
+	return nil
+}
+
+// checkProxyLogLevelReset returns a Forbidden error unless the Kiali service account is allowed to reset the log levels:
+// to exec in the pod, to request the Envoy admin interface, and to patch it, to remove the reset annotations
+func checkProxyLogLevelReset(namespace, podName string) error {
+	k8s, err := getProxyLoggingClient()
+	if err != nil {
+		return err
+	}
+	for _, check := range []struct{ resource, verb string }{{"pods/exec", "create"}, {"pods", "patch"}} {
+		ssars, err := k8s.GetSelfSubjectAccessReview(namespace, "", check.resource, []string{check.verb})
+		if err != nil {
+			return err
+		}
+		if len(ssars) == 0 || !ssars[0].Status.Allowed {
+			return errors2.NewForbidden(schema.GroupResource{Resource: check.resource}, podName,
+				fmt.Errorf("Kiali service account can't %s %s in namespace [%s] to reset the log levels", check.verb, check.resource, namespace))
+		}
+	}
+	return nil
+}
+
+// patchProxyLogLevelReset sets the annotations of the pending reset in the pod, or removes them without reset time
+func patchProxyLogLevelReset(k8s kubernetes.ClientInterface, namespace, podName string, previous map[string]string, resetTime *time.Time) error {
+	annotations := map[string]interface{}{
+		proxyLogLevelResetAnnotation:    nil,
+		proxyLogLevelPreviousAnnotation: nil,
+	}
+	if resetTime != nil {
+		levels, err := json.Marshal(previous)
+		if err != nil {
+			return err
+		}
+		annotations[proxyLogLevelResetAnnotation] = resetTime.Format(time.RFC3339)
+		annotations[proxyLogLevelPreviousAnnotation] = string(levels)
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+	if err != nil {
+		return err
+	}
+	return k8s.UpdateWorkload(namespace, podName, kubernetes.PodType, string(patch))
+}
+
+// StartProxyLogLevelResets schedules the resets of the proxy log levels pending before a restart, as found in the
+// annotations of the pods of the accessible namespaces. Expired resets happen right away. The Kiali service account must
+// be allowed to list, patch and create pods/exec in these namespaces for the resets to be done.
+func StartProxyLogLevelResets() {
+	k8s, err := getProxyLoggingClient()
+	if err != nil {
+		log.Errorf("Error getting the client to reset the log levels of the proxies: %v", err)
+		return
+	}
+	namespaces, err := NewWithBackends(k8s, nil, nil).Namespace.GetNamespaces()
+	if err != nil {
+		log.Errorf("Error getting the namespaces of the proxy log level resets: %v", err)
+		return
+	}
+	for _, ns := range namespaces {
+		pods, err := k8s.GetPods(ns.Name, "")
+		if err != nil {
+			log.Errorf("Error getting the pods of namespace [%s] to reset the log levels of their proxies: %v", ns.Name, err)
+			continue
+		}
+		for _, pod := range pods {
+			value, found := pod.Annotations[proxyLogLevelResetAnnotation]
+			if !found {
+				continue
+			}
+			resetTime, err := time.Parse(time.RFC3339, value)
+			previous := map[string]string{}
+			if err == nil {
+				err = json.Unmarshal([]byte(pod.Annotations[proxyLogLevelPreviousAnnotation]), &previous)
+			}
+			if err != nil {
+				log.Errorf("Invalid log level reset of the proxy of pod [%s/%s], the reset is dropped: %v", ns.Name, pod.Name, err)
+				if patchErr := patchProxyLogLevelReset(k8s, ns.Name, pod.Name, nil, nil); patchErr != nil {
+					log.Errorf("Error removing the log level reset of the proxy of pod [%s/%s]: %v", ns.Name, pod.Name, patchErr)
+				}
+				continue
+			}
+			scheduleProxyLogLevelReset(ns.Name, pod.Name, previous, resetTime)
+		}
+	}
+}
+
+func proxyLogLevels(namespace, podName string, loggers map[string]string) *models.EnvoyLogLevels {
+	levels := &models.EnvoyLogLevels{Loggers: loggers}
+	proxyLogLevelResets.Lock()
+	defer proxyLogLevelResets.Unlock()
+	if reset, found := proxyLogLevelResets.resets[namespace+"/"+podName]; found {
+		resetTime := reset.time
+		levels.ResetTime = &resetTime
+	}
+	return levels
+}
+
+func stopProxyLogLevelResets() {
+	proxyLogLevelResets.Lock()
+	defer proxyLogLevelResets.Unlock()
+	for key, reset := range proxyLogLevelResets.resets {
+		reset.timer.Stop()
+		delete(proxyLogLevelResets.resets, key)
+	}
+}
+
+// scheduleProxyLogLevelReset restores the log levels of a proxy at the reset time. When a reset is already pending,
+// only its time changes, so the levels restored are still the ones before the first change.
+func scheduleProxyLogLevelReset(namespace, podName string, previous map[string]string, resetTime time.Time) {
+	key := namespace + "/" + podName
+	proxyLogLevelResets.Lock()
+	defer proxyLogLevelResets.Unlock()
+	if reset, found := proxyLogLevelResets.resets[key]; found {
+		reset.timer.Stop()
+		previous = reset.previous
+	}
+	proxyLogLevelResets.resets[key] = &proxyLogLevelReset{
+		time:     resetTime,
+		previous: previous,
+		timer: time.AfterFunc(resetTime.Sub(util.Clock.Now()), func() {
+			cancelProxyLogLevelReset(namespace, podName)
+			resetProxyLogLevels(namespace, podName, previous)
+		}),
+	}
+}
+
+// cancelProxyLogLevelReset stops the pending reset of a proxy and returns true, false when there is none
+func cancelProxyLogLevelReset(namespace, podName string) bool {
+	key := namespace + "/" + podName
+	proxyLogLevelResets.Lock()
+	defer proxyLogLevelResets.Unlock()
+	reset, found := proxyLogLevelResets.resets[key]
+	if found {
+		reset.timer.Stop()
+		delete(proxyLogLevelResets.resets, key)
+	}
+	return found
+}
+
+// pendingProxyLogLevels returns the levels restored by the pending reset of a proxy, nil when there is none
+func pendingProxyLogLevels(namespace, podName string) map[string]string {
+	proxyLogLevelResets.Lock()
+	defer proxyLogLevelResets.Unlock()
+	if reset, found := proxyLogLevelResets.resets[namespace+"/"+podName]; found {
+		return reset.previous
+	}
+	return nil
+}
+
+// resetProxyLogLevels restores the levels of the loggers changed since the previous levels were listed, and removes the
+// annotations of the reset. When all the previous levels are the same, they are restored at once.
+func resetProxyLogLevels(namespace, podName string, previous map[string]string) {
+	k8s, err := getProxyLoggingClient()
+	if err != nil {
+		log.Errorf("Error getting the client to reset the log levels of the proxy of pod [%s/%s]: %v", namespace, podName, err)
+		return
+	}
+
+	uniform := ""
+	for _, level := range previous {
+		if uniform == "" {
+			uniform = level
+		} else if uniform != level {
+			uniform = ""
+			break
+		}
+	}
+	if uniform != "" {
+		_, err = k8s.SetProxyLogLevel(namespace, podName, "", uniform)
+	} else {
+		var current map[string]string
+		if current, err = k8s.SetProxyLogLevel(namespace, podName, "", ""); err == nil {
+			for logger, level := range previous {
+				if current[logger] != level {
+					if _, err = k8s.SetProxyLogLevel(namespace, podName, logger, level); err != nil {
+						break
+					}
+				}
+			}
+		}
+	}
+	if err != nil {
+		// The pod may be gone, so the reset is not retried
+		log.Errorf("Error resetting the log levels of the proxy of pod [%s/%s]: %v", namespace, podName, err)
+	} else {
+		log.Infof("Log levels of the proxy of pod [%s/%s] reset", namespace, podName)
+	}
+	if err = patchProxyLogLevelReset(k8s, namespace, podName, nil, nil); err != nil && !errors2.IsNotFound(err) {
+		log.Errorf("Error removing the log level reset of the proxy of pod [%s/%s]: %v", namespace, podName, err)
+	}
+}
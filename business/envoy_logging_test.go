@@ -0,0 +1,194 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	auth_v1 "k8s.io/api/authorization/v1"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func TestSetProxyLogLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockProxyLogging(true, true)
+	k8s.On("SetProxyLogLevel", "bookinfo", "reviews-v1", "", "").Return(map[string]string{"http": "warning", "rbac": "warning"}, nil)
+	k8s.On("SetProxyLogLevel", "bookinfo", "reviews-v1", "rbac", "debug").Return(map[string]string{"http": "warning", "rbac": "debug"}, nil)
+	// The reset is kept in the pod annotations, and removed with the reset
+	k8s.On("UpdateWorkload", "bookinfo", "reviews-v1", kubernetes.PodType,
+		`{"metadata":{"annotations":{"kiali.io/proxy-log-level-previous":"{\"http\":\"warning\",\"rbac\":\"warning\"}","kiali.io/proxy-log-level-reset":"2020-06-01T10:10:00Z"}}}`).Return(nil, nil).Once()
+	k8s.On("UpdateWorkload", "bookinfo", "reviews-v1", kubernetes.PodType,
+		`{"metadata":{"annotations":{"kiali.io/proxy-log-level-previous":null,"kiali.io/proxy-log-level-reset":null}}}`).Return(nil, nil).Once()
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	now := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: now}
+	defer cancelProxyLogLevelReset("bookinfo", "reviews-v1")
+	getProxyLoggingClient = func() (kubernetes.ClientInterface, error) { return mockProxyLoggingServiceAccount(true), nil }
+	defer func() { getProxyLoggingClient = getKialiSAClient }()
+
+	layer := NewWithBackends(k8s, nil, nil)
+	levels, err := layer.Workload.SetProxyLogLevel("bookinfo", "reviews-v1", models.EnvoyLogLevelRequest{Logger: "rbac", Level: "debug", ResetAfter: "10m"})
+	assert.NoError(err)
+	assert.Equal("debug", levels.Loggers["rbac"])
+	assert.Equal(now.Add(10*time.Minute), *levels.ResetTime)
+
+	proxyLogLevelResets.Lock()
+	reset := proxyLogLevelResets.resets["bookinfo/reviews-v1"]
+	proxyLogLevelResets.Unlock()
+	assert.Equal(map[string]string{"http": "warning", "rbac": "warning"}, reset.previous)
+
+	// A change without reset duration is kept
+	levels, err = layer.Workload.SetProxyLogLevel("bookinfo", "reviews-v1", models.EnvoyLogLevelRequest{Logger: "rbac", Level: "debug"})
+	assert.NoError(err)
+	assert.Nil(levels.ResetTime)
+	k8s.AssertNumberOfCalls(t, "UpdateWorkload", 2)
+
+	for _, request := range []models.EnvoyLogLevelRequest{
+		{Level: "verbose"},
+		{Logger: "rbac;http", Level: "debug"},
+		{Level: "debug", ResetAfter: "48h"},
+	} {
+		_, err = layer.Workload.SetProxyLogLevel("bookinfo", "reviews-v1", request)
+		assert.True(errors.IsBadRequest(err))
+	}
+}
+
+func TestSetProxyLogLevelForbidden(t *testing.T) {
+	assert := assert.New(t)
+
+	// The right to update the pods is not enough, the Envoy admin interface is reached through the pods exec subresource
+	k8s := mockProxyLogging(false, true)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	_, err := layer.Workload.SetProxyLogLevel("bookinfo", "reviews-v1", models.EnvoyLogLevelRequest{Level: "debug"})
+	assert.True(errors.IsForbidden(err))
+	_, err = layer.Workload.GetProxyLogLevels("bookinfo", "reviews-v1")
+	assert.True(errors.IsForbidden(err))
+	k8s.AssertNotCalled(t, "SetProxyLogLevel", "bookinfo", "reviews-v1", "", "debug")
+	k8s.AssertNotCalled(t, "SetProxyLogLevel", "bookinfo", "reviews-v1", "", "")
+
+	// The reset is kept in the annotations of the pod, patched by the user
+	k8s = mockProxyLogging(true, false)
+	layer = NewWithBackends(k8s, nil, nil)
+	_, err = layer.Workload.SetProxyLogLevel("bookinfo", "reviews-v1", models.EnvoyLogLevelRequest{Level: "debug", ResetAfter: "10m"})
+	assert.True(errors.IsForbidden(err))
+	k8s.AssertNotCalled(t, "SetProxyLogLevel", "bookinfo", "reviews-v1", "", "debug")
+}
+
+func TestSetProxyLogLevelResetForbidden(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockProxyLogging(true, true)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	getProxyLoggingClient = func() (kubernetes.ClientInterface, error) { return mockProxyLoggingServiceAccount(false), nil }
+	defer func() { getProxyLoggingClient = getKialiSAClient }()
+
+	// Kiali must be allowed to reset the levels before they are changed
+	layer := NewWithBackends(k8s, nil, nil)
+	_, err := layer.Workload.SetProxyLogLevel("bookinfo", "reviews-v1", models.EnvoyLogLevelRequest{Level: "debug", ResetAfter: "10m"})
+	assert.True(errors.IsForbidden(err))
+	k8s.AssertNotCalled(t, "SetProxyLogLevel", "bookinfo", "reviews-v1", "", "debug")
+}
+
+func TestStartProxyLogLevelResets(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespaces", "").Return([]core_v1.Namespace{{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}}}, nil)
+	k8s.On("GetPods", "bookinfo", "").Return([]core_v1.Pod{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1", Annotations: map[string]string{
+			proxyLogLevelResetAnnotation:    "2020-06-01T11:00:00Z",
+			proxyLogLevelPreviousAnnotation: `{"http":"info"}`,
+		}}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v2", Annotations: map[string]string{proxyLogLevelResetAnnotation: "tomorrow"}}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "ratings-v1"}},
+	}, nil)
+	k8s.On("UpdateWorkload", "bookinfo", "reviews-v2", kubernetes.PodType, mock.AnythingOfType("string")).Return(nil, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	util.Clock = util.ClockMock{Time: time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)}
+	getProxyLoggingClient = func() (kubernetes.ClientInterface, error) { return k8s, nil }
+	defer func() { getProxyLoggingClient = getKialiSAClient }()
+	defer stopProxyLogLevelResets()
+
+	// Invalid resets are dropped
+	StartProxyLogLevelResets()
+	levels := proxyLogLevels("bookinfo", "reviews-v1", map[string]string{"http": "debug"})
+	assert.Equal(time.Date(2020, 6, 1, 11, 0, 0, 0, time.UTC), levels.ResetTime.UTC())
+	assert.Equal(map[string]string{"http": "info"}, pendingProxyLogLevels("bookinfo", "reviews-v1"))
+	assert.Nil(pendingProxyLogLevels("bookinfo", "reviews-v2"))
+	k8s.AssertCalled(t, "UpdateWorkload", "bookinfo", "reviews-v2", kubernetes.PodType, `{"metadata":{"annotations":{"kiali.io/proxy-log-level-previous":null,"kiali.io/proxy-log-level-reset":null}}}`)
+}
+
+func TestResetProxyLogLevels(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	getProxyLoggingClient = func() (kubernetes.ClientInterface, error) { return k8s, nil }
+	defer func() { getProxyLoggingClient = getKialiSAClient }()
+	util.Clock = util.ClockMock{Time: time.Date(2020, 6, 1, 10, 10, 0, 0, time.UTC)}
+
+	done := make(chan bool)
+	k8s.On("SetProxyLogLevel", "bookinfo", "reviews-v1", "", "").Return(map[string]string{"http": "debug", "rbac": "debug"}, nil)
+	k8s.On("SetProxyLogLevel", "bookinfo", "reviews-v1", "http", "info").Return(map[string]string{"http": "info", "rbac": "debug"}, nil)
+	k8s.On("UpdateWorkload", "bookinfo", "reviews-v1", kubernetes.PodType, mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		done <- true
+	}).Return(nil, nil)
+	// Expired resets happen right away, only for the loggers changed
+	scheduleProxyLogLevelReset("bookinfo", "reviews-v1", map[string]string{"http": "info", "rbac": "debug"}, time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		assert.Fail("log levels not reset")
+	}
+	k8s.AssertNotCalled(t, "SetProxyLogLevel", "bookinfo", "reviews-v1", "rbac", "debug")
+}
+
+// mockProxyLoggingServiceAccount returns a mock of the Kiali service account client, allowed or not to reset the log levels
+func mockProxyLoggingServiceAccount(allowed bool) *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "", "pods/exec", []string{"create"}).Return([]*auth_v1.SelfSubjectAccessReview{
+		{Status: auth_v1.SubjectAccessReviewStatus{Allowed: allowed}},
+	}, nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "", "pods", []string{"patch"}).Return([]*auth_v1.SelfSubjectAccessReview{
+		{Status: auth_v1.SubjectAccessReviewStatus{Allowed: true}},
+	}, nil)
+	return k8s
+}
+
+// mockProxyLogging returns a mock of the user client, allowed or not to exec in the pods and to patch them
+func mockProxyLogging(exec, patch bool) *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "", "pods/exec", []string{"create"}).Return([]*auth_v1.SelfSubjectAccessReview{
+		{Status: auth_v1.SubjectAccessReviewStatus{Allowed: exec}},
+	}, nil)
+	k8s.On("GetSelfSubjectAccessReview", "bookinfo", "", "pods", []string{"patch"}).Return([]*auth_v1.SelfSubjectAccessReview{
+		{Status: auth_v1.SubjectAccessReviewStatus{Allowed: patch}},
+	}, nil)
+	return k8s
+}
@@ -0,0 +1,199 @@
+package business
+
+import (
+	"sync"
+
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// eventObject is an object whose events are fetched
+type eventObject struct {
+	kind string
+	name string
+}
+
+// eventObjects is a set of the objects whose events are fetched
+type eventObjects map[eventObject]bool
+
+func (objects eventObjects) add(kind, name string) {
+	objects[eventObject{kind: kind, name: name}] = true
+}
+
+func (objects eventObjects) has(kind, name string) bool {
+	return objects[eventObject{kind: kind, name: name}]
+}
+
+// GetWorkloadEvents returns the recent events of a workload, its ReplicaSets and its pods
+func (in *WorkloadService) GetWorkloadEvents(namespace, workloadName, workloadType string) (models.Events, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetWorkloadEvents")
+	defer promtimer.ObserveNow(&err)
+
+	workload, err := fetchWorkload(in.businessLayer, namespace, workloadName, workloadType)
+	if err != nil {
+		return nil, err
+	}
+	objects := workloadEventObjects(workload, fetchReplicaSetsForEvents(in.businessLayer, namespace))
+	var events []core_v1.Event
+	if events, err = getObjectEvents(in.k8s, namespace, objects); err != nil {
+		return nil, err
+	}
+	return parseEvents(events, objects), nil
+}
+
+// GetPodEvents returns the recent events of a pod
+func (in *WorkloadService) GetPodEvents(namespace, podName string) (models.Events, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetPodEvents")
+	defer promtimer.ObserveNow(&err)
+
+	// Events outlive their pods, so only the access to the namespace is checked
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	objects := podEventObjects(podName)
+	var events []core_v1.Event
+	if events, err = getObjectEvents(in.k8s, namespace, objects); err != nil {
+		return nil, err
+	}
+	return parseEvents(events, objects), nil
+}
+
+// GetServiceEvents returns the recent events of a service and its endpoints
+func (in *SvcService) GetServiceEvents(namespace, service string) (models.Events, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SvcService", "GetServiceEvents")
+	defer promtimer.ObserveNow(&err)
+
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if _, _, err = in.getServiceDefinition(namespace, service); err != nil {
+		return nil, err
+	}
+	objects := serviceEventObjects(service)
+	var events []core_v1.Event
+	if events, err = getObjectEvents(in.k8s, namespace, objects); err != nil {
+		return nil, err
+	}
+	return parseEvents(events, objects), nil
+}
+
+// getObjectEvents returns the events of the objects of a namespace. Events are not cached: they are listed concurrently
+// with a field selector per object, instead of listing all the events of the namespace.
+func getObjectEvents(k8s kubernetes.ClientInterface, namespace string, objects eventObjects) ([]core_v1.Event, error) {
+	events := []core_v1.Event{}
+	errChan := make(chan error, len(objects))
+	var mutex sync.Mutex
+	wg := sync.WaitGroup{}
+	wg.Add(len(objects))
+	for object := range objects {
+		go func(object eventObject) {
+			defer wg.Done()
+			objectEvents, err := k8s.GetObjectEvents(namespace, object.kind, object.name)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			mutex.Lock()
+			events = append(events, objectEvents...)
+			mutex.Unlock()
+		}(object)
+	}
+	wg.Wait()
+	if len(errChan) != 0 {
+		return nil, <-errChan
+	}
+	return events, nil
+}
+
+// fetchObjectEvents returns the events of the objects of a namespace. They are an additional information of the details
+// and the health of the objects: an error is logged and no event is returned.
+func fetchObjectEvents(layer *Layer, namespace string, objects eventObjects) []core_v1.Event {
+	events, err := getObjectEvents(layer.k8s, namespace, objects)
+	if err != nil {
+		log.Errorf("Error fetching Events per namespace %s: %s", namespace, err)
+		return []core_v1.Event{}
+	}
+	return events
+}
+
+// fetchEvents returns all the events of a namespace, for the health of all its workloads. Events are not cached, and
+// they are an additional information of the health: an error is logged and no event is returned.
+func fetchEvents(layer *Layer, namespace string) []core_v1.Event {
+	events, err := layer.k8s.GetEvents(namespace)
+	if err != nil {
+		log.Errorf("Error fetching Events per namespace %s: %s", namespace, err)
+		return []core_v1.Event{}
+	}
+	return events
+}
+
+// fetchReplicaSetsForEvents returns the ReplicaSets of a namespace, to include the events of the ReplicaSets without pods,
+// i.e. when the pods cannot be created
+func fetchReplicaSetsForEvents(layer *Layer, namespace string) []apps_v1.ReplicaSet {
+	var replicaSets []apps_v1.ReplicaSet
+	var err error
+	if IsNamespaceCached(namespace) {
+		replicaSets, err = kialiCache.GetReplicaSets(namespace)
+	} else {
+		replicaSets, err = layer.k8s.GetReplicaSets(namespace)
+	}
+	if err != nil {
+		log.Errorf("Error fetching ReplicaSets per namespace %s: %s", namespace, err)
+	}
+	return replicaSets
+}
+
+// workloadEventObjects returns the workload, the ReplicaSets it owns, its pods and their owners
+func workloadEventObjects(workload *models.Workload, replicaSets []apps_v1.ReplicaSet) eventObjects {
+	objects := eventObjects{}
+	objects.add(workload.Type, workload.Name)
+	for _, rs := range replicaSets {
+		for _, ref := range rs.OwnerReferences {
+			if ref.Kind == workload.Type && ref.Name == workload.Name {
+				objects.add(kubernetes.ReplicaSetType, rs.Name)
+			}
+		}
+	}
+	for _, pod := range workload.Pods {
+		objects.add(kubernetes.PodType, pod.Name)
+		for _, ref := range pod.CreatedBy {
+			objects.add(ref.Kind, ref.Name)
+		}
+	}
+	return objects
+}
+
+func podEventObjects(podName string) eventObjects {
+	objects := eventObjects{}
+	objects.add(kubernetes.PodType, podName)
+	return objects
+}
+
+// serviceEventObjects returns the service and its endpoints, which share the name of the service
+func serviceEventObjects(service string) eventObjects {
+	objects := eventObjects{}
+	objects.add(kubernetes.ServiceType, service)
+	objects.add("Endpoints", service)
+	return objects
+}
+
+// parseEvents returns the events of the given objects, warnings first and deduplicated by reason
+func parseEvents(events []core_v1.Event, objects eventObjects) models.Events {
+	filtered := []core_v1.Event{}
+	for _, e := range events {
+		if objects.has(e.InvolvedObject.Kind, e.InvolvedObject.Name) {
+			filtered = append(filtered, e)
+		}
+	}
+	parsed := models.Events{}
+	parsed.Parse(filtered)
+	return parsed
+}
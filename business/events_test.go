@@ -0,0 +1,77 @@
+package business
+
+import (
+	"testing"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func fakeEvent(eventType, reason, kind, name string) core_v1.Event {
+	return core_v1.Event{
+		Type:           eventType,
+		Reason:         reason,
+		Message:        reason + " " + name,
+		InvolvedObject: core_v1.ObjectReference{Kind: kind, Name: name},
+	}
+}
+
+func TestGetPodEvents(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "Namespace").Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetObjectEvents", "Namespace", "Pod", "details-v1-3618568057-dnkjp").Return([]core_v1.Event{
+		fakeEvent("Normal", "Pulling", "Pod", "details-v1-3618568057-dnkjp"),
+		fakeEvent("Warning", "Failed", "Pod", "details-v1-3618568057-dnkjp"),
+		fakeEvent("Warning", "Failed", "Pod", "details-v1-3618568057-dnkjp"),
+	}, nil)
+
+	svc := setupWorkloadService(k8s)
+	events, err := svc.GetPodEvents("Namespace", "details-v1-3618568057-dnkjp")
+
+	assert.NoError(err)
+	assert.Len(events, 2)
+	assert.Equal("Failed", events[0].Reason)
+	assert.Equal(int32(2), events[0].Count)
+	assert.Equal("Pulling", events[1].Reason)
+}
+
+func TestGetWorkloadDetailsEvents(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDaemonSet", "Namespace", "daemon-controller").Return(&FakeDaemonSets()[0], nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsFromDaemonSet(), nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetObjectEvents", "Namespace", "DaemonSet", "daemon-controller").Return([]core_v1.Event{
+		fakeEvent("Normal", "SuccessfulCreate", "DaemonSet", "daemon-controller"),
+	}, nil)
+	k8s.On("GetObjectEvents", "Namespace", "Pod", "daemon-pod").Return([]core_v1.Event{
+		fakeEvent("Warning", "BackOff", "Pod", "daemon-pod"),
+		fakeEvent("Warning", "BackOff", "Pod", "daemon-pod"),
+	}, nil)
+	k8s.On("GetObjectEvents", "Namespace", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
+
+	svc := setupWorkloadService(k8s)
+	workload, err := svc.GetWorkload("Namespace", "daemon-controller", "DaemonSet", false)
+
+	assert.NoError(err)
+	assert.Len(workload.Events, 2)
+	assert.Equal("BackOff", workload.Events[0].Reason)
+	assert.Equal("Pod", workload.Events[0].ObjectKind)
+	assert.Equal(int32(2), workload.Events[0].Count)
+	assert.Equal("SuccessfulCreate", workload.Events[1].Reason)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/prometheus/common/model"
+	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
@@ -91,6 +92,9 @@ func (in *HealthService) GetWorkloadHealth(namespace, workload, workloadType, ra
 	}
 
 	status := w.CastWorkloadStatus()
+	// Warning events show the problems of the workload not reflected by the replicas, i.e. an image that cannot be pulled
+	objects := workloadEventObjects(w, fetchReplicaSetsForEvents(in.businessLayer, namespace))
+	status.WarningReasons = parseEvents(fetchObjectEvents(in.businessLayer, namespace, objects), objects).WarningReasons()
 
 	// Perf: do not bother fetching request rate if workload has no sidecar
 	if !w.IstioSidecar {
@@ -235,9 +239,20 @@ func (in *HealthService) getNamespaceWorkloadHealth(namespace string, ws models.
 	hasSidecar := false
 
 	allHealth := make(models.NamespaceWorkloadHealth)
+	// Listing all the events of the namespace at each refresh of the health is expensive, it is opt-in
+	namespaceEvents := config.Get().HealthConfig.NamespaceEvents
+	var events []core_v1.Event
+	var replicaSets []apps_v1.ReplicaSet
+	if namespaceEvents {
+		events = fetchEvents(in.businessLayer, namespace)
+		replicaSets = fetchReplicaSetsForEvents(in.businessLayer, namespace)
+	}
 	for _, w := range ws {
 		allHealth[w.Name] = models.EmptyWorkloadHealth()
 		allHealth[w.Name].WorkloadStatus = w.CastWorkloadStatus()
+		if namespaceEvents {
+			allHealth[w.Name].WorkloadStatus.WarningReasons = parseEvents(events, workloadEventObjects(w, replicaSets)).WarningReasons()
+		}
 		if w.IstioSidecar {
 			hasSidecar = true
 		}
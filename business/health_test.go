@@ -15,6 +15,7 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus/prometheustest"
 )
 
@@ -63,6 +64,7 @@ func TestGetAppHealth(t *testing.T) {
 	config.Set(conf)
 
 	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsArgoRolloutsApi").Return(true)
 	k8s.MockEmptyWorkloads("ns")
 	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
 	k8s.On("GetDeployments", "ns").Return(fakeDeploymentsHealthReview(), nil)
@@ -106,11 +108,13 @@ func TestGetWorkloadHealth(t *testing.T) {
 	config.Set(conf)
 
 	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsArgoRolloutsApi").Return(true)
 	k8s.MockEmptyWorkload("ns", "reviews-v1")
 	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
 	k8s.On("GetDeployment", "ns", "reviews-v1").Return(&fakeDeploymentsHealthReview()[0], nil)
 	k8s.On("GetPods", "ns", "").Return(fakePodsHealthReview(), nil)
 	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{}, nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
 
 	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
 	prom.MockWorkloadRequestRates("ns", "reviews-v1", otherRatesIn, otherRatesOut)
@@ -150,6 +154,7 @@ func TestGetAppHealthWithoutIstio(t *testing.T) {
 	config.Set(conf)
 
 	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsArgoRolloutsApi").Return(true)
 	k8s.MockEmptyWorkloads("ns")
 	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
 	k8s.On("GetDeployments", "ns").Return(fakeDeploymentsHealthReview(), nil)
@@ -177,10 +182,12 @@ func TestGetWorkloadHealthWithoutIstio(t *testing.T) {
 	config.Set(conf)
 
 	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsArgoRolloutsApi").Return(true)
 	k8s.MockEmptyWorkload("ns", "reviews-v1")
 	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
 	k8s.On("GetDeployment", "ns", "reviews-v1").Return(&fakeDeploymentsHealthReview()[0], nil)
 	k8s.On("GetPods", "ns", "").Return(fakePodsHealthReviewWithoutIstio(), nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
 
 	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
 	prom.MockWorkloadRequestRates("ns", "reviews-v1", otherRatesIn, otherRatesOut)
@@ -194,6 +201,97 @@ func TestGetWorkloadHealthWithoutIstio(t *testing.T) {
 	assert.Equal(emptyResult, health.Requests.Outbound)
 }
 
+func TestGetWorkloadHealthWithWarningEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// ReplicaSets and Deployment are mocked before the empty workload, as the first matching call is used
+	controller := true
+	dep := fakeDeploymentsHealthReview()[0]
+	dep.Spec.Template.Labels = map[string]string{"app": "reviews", "version": "v1"}
+	k8s.On("GetReplicaSets", "ns").Return([]apps_v1.ReplicaSet{
+		{ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "reviews-v1-1",
+			OwnerReferences: []meta_v1.OwnerReference{{Controller: &controller, Kind: "Deployment", Name: "reviews-v1"}},
+		}},
+		{ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "reviews-v1-2",
+			OwnerReferences: []meta_v1.OwnerReference{{Controller: &controller, Kind: "Deployment", Name: "reviews-v1"}},
+		}},
+	}, nil)
+	k8s.On("GetDeployment", "ns", "reviews-v1").Return(&dep, nil)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsArgoRolloutsApi").Return(true)
+	k8s.MockEmptyWorkload("ns", "reviews-v1")
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetPods", "ns", "").Return([]core_v1.Pod{
+		{ObjectMeta: meta_v1.ObjectMeta{
+			Name:            "reviews-v1-1-x2x8q",
+			Labels:          map[string]string{"app": "reviews", "version": "v1"},
+			OwnerReferences: []meta_v1.OwnerReference{{Controller: &controller, Kind: "ReplicaSet", Name: "reviews-v1-1"}},
+		}},
+	}, nil)
+	// A crash-looping pod, and the new ReplicaSet not allowed to create its pods
+	k8s.On("GetObjectEvents", "ns", "Pod", "reviews-v1-1-x2x8q").Return([]core_v1.Event{
+		{Type: "Warning", Reason: "BackOff", InvolvedObject: core_v1.ObjectReference{Kind: "Pod", Name: "reviews-v1-1-x2x8q"}},
+		{Type: "Normal", Reason: "Pulling", InvolvedObject: core_v1.ObjectReference{Kind: "Pod", Name: "reviews-v1-1-x2x8q"}},
+	}, nil)
+	k8s.On("GetObjectEvents", "ns", "ReplicaSet", "reviews-v1-2").Return([]core_v1.Event{
+		{Type: "Warning", Reason: "FailedCreate", InvolvedObject: core_v1.ObjectReference{Kind: "ReplicaSet", Name: "reviews-v1-2"}},
+	}, nil)
+	k8s.On("GetObjectEvents", "ns", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetWorkloadHealth("ns", "reviews-v1", "", "1m", queryTime)
+
+	assert.NoError(err)
+	assert.ElementsMatch([]string{"BackOff", "FailedCreate"}, health.WorkloadStatus.WarningReasons)
+	// The events of the namespace are not listed
+	k8s.AssertNotCalled(t, "GetEvents", "ns")
+}
+
+func TestGetNamespaceWorkloadHealthWithWarningEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetReplicaSets", "ns").Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetEvents", "ns").Return([]core_v1.Event{
+		{Type: "Warning", Reason: "BackOff", InvolvedObject: core_v1.ObjectReference{Kind: "Pod", Name: "reviews-v1-1-x2x8q"}},
+		// Events of other workloads are not included
+		{Type: "Warning", Reason: "FailedMount", InvolvedObject: core_v1.ObjectReference{Kind: "Pod", Name: "reviews-v2"}},
+	}, nil)
+
+	w := &models.Workload{Pods: models.Pods{{Name: "reviews-v1-1-x2x8q"}}}
+	w.Name = "reviews-v1"
+	w.Type = "Deployment"
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+
+	// Namespace events are opt-in
+	health, err := hs.getNamespaceWorkloadHealth("ns", models.Workloads{w}, "1m", queryTime)
+	assert.NoError(err)
+	assert.Empty(health["reviews-v1"].WorkloadStatus.WarningReasons)
+	k8s.AssertNotCalled(t, "GetEvents", "ns")
+
+	conf.HealthConfig.NamespaceEvents = true
+	config.Set(conf)
+	health, err = hs.getNamespaceWorkloadHealth("ns", models.Workloads{w}, "1m", queryTime)
+	assert.NoError(err)
+	assert.Equal([]string{"BackOff"}, health["reviews-v1"].WorkloadStatus.WarningReasons)
+}
+
 func TestGetNamespaceAppHealthWithoutIstio(t *testing.T) {
 	// Setup mocks
 	k8s := new(kubetest.K8SClientMock)
@@ -202,6 +300,7 @@ func TestGetNamespaceAppHealthWithoutIstio(t *testing.T) {
 	config.Set(conf)
 
 	k8s.On("IsOpenShift").Return(true)
+	k8s.On("IsArgoRolloutsApi").Return(true)
 	k8s.MockEmptyWorkloads("ns")
 	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
 	k8s.On("GetServices", "ns", mock.AnythingOfType("map[string]string")).Return([]core_v1.Service{}, nil)
@@ -12,13 +12,17 @@ import (
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 	"github.com/kiali/kiali/util"
 )
 
 type IstioConfigService struct {
 	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
 	businessLayer *Layer
+	// user applying the changes, recorded in the Istio config history
+	user string
 }
 
 type IstioConfigCriteria struct {
@@ -507,7 +511,11 @@ func (in *IstioConfigService) DeleteIstioConfigDetail(api, namespace, resourceTy
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "DeleteIstioConfigDetail")
 	defer promtimer.ObserveNow(&err)
 
+	before := in.getRecordedObject(namespace, resourceType, name)
 	err = in.k8s.DeleteIstioObject(api, namespace, resourceType, name)
+	if err == nil {
+		in.recordRevision(namespace, resourceType, name, models.DeleteOperation, before, nil)
+	}
 
 	// Cache is stopped after a Create/Update/Delete operation to force a refresh
 	if kialiCache != nil && err == nil {
@@ -536,9 +544,16 @@ func (in *IstioConfigService) modifyIstioConfigDetail(api, namespace, resourceTy
 	if create {
 		// Create new object
 		result, err = in.k8s.CreateIstioObject(api, namespace, updatedType, json)
+		if err == nil {
+			in.recordRevision(namespace, resourceType, result.GetObjectMeta().Name, models.CreateOperation, nil, result)
+		}
 	} else {
 		// Update/Path existing object
+		before := in.getRecordedObject(namespace, resourceType, name)
 		result, err = in.k8s.UpdateIstioObject(api, namespace, updatedType, name, json)
+		if err == nil {
+			in.recordRevision(namespace, resourceType, name, models.UpdateOperation, before, result)
+		}
 	}
 	if err != nil {
 		return istioConfigDetail, err
@@ -597,6 +612,28 @@ func (in *IstioConfigService) CreateIstioConfigDetail(api, namespace, resourceTy
 	return in.modifyIstioConfigDetail(api, namespace, resourceType, "", json, true)
 }
 
+// reviewableIstioObject returns the fields of an Istio object that are set by the user
+func reviewableIstioObject(objectType string, object kubernetes.IstioObject) map[string]interface{} {
+	meta := object.GetObjectMeta()
+	metadata := map[string]interface{}{
+		"name":      meta.Name,
+		"namespace": meta.Namespace,
+	}
+	if len(meta.Labels) > 0 {
+		metadata["labels"] = meta.Labels
+	}
+	if len(meta.Annotations) > 0 {
+		metadata["annotations"] = meta.Annotations
+	}
+	// Objects fetched from the API may not include their kind
+	return map[string]interface{}{
+		"apiVersion": kubernetes.ApiToVersion[kubernetes.ResourceTypesToAPI[objectType]],
+		"kind":       kubernetes.PluralType[objectType],
+		"metadata":   metadata,
+		"spec":       object.GetSpec(),
+	}
+}
+
 func (in *IstioConfigService) GeIstioConfigPermissions(namespaces []string) models.IstioConfigPermissions {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "GeIstioConfigPermissions")
@@ -0,0 +1,246 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// batchApplyOrder is the order in which the object types of a batch are applied, so the objects referenced exist
+// before the ones referencing them: hosts and subsets before the routes using them, gateways before the routes bound
+// to them. Objects are rolled back in the reverse order.
+var batchApplyOrder = []string{
+	kubernetes.ServiceEntries,
+	kubernetes.DestinationRules,
+	kubernetes.Gateways,
+	kubernetes.VirtualServices,
+	kubernetes.Sidecars,
+	kubernetes.PeerAuthentications,
+	kubernetes.RequestAuthentications,
+	kubernetes.AuthorizationPolicies,
+}
+
+// batchObject is an object of a batch, with the existing object to restore on rollback. The body is the object to
+// create, or the JSON Merge Patch from the existing object to the object of the batch.
+type batchObject struct {
+	models.IstioConfigBatchObject
+	order  int
+	body   []byte
+	before kubernetes.IstioObject
+}
+
+// ApplyIstioConfigBatch validates several Istio objects together and applies them in dependency order. Objects are
+// created when they don't exist, and updated otherwise with the JSON Merge Patch from the live object to the object of
+// the batch, so the fields missing in the batch are removed. The same patch is validated in the dry run. Nothing is
+// applied when the objects introduce error checks or when dryRun is set. If applying an object fails, the objects
+// already applied are rolled back: created objects are deleted and updated ones restored.
+func (in *IstioConfigService) ApplyIstioConfigBatch(namespace string, body []byte, dryRun bool) (models.IstioConfigBatchResult, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ApplyIstioConfigBatch")
+	defer promtimer.ObserveNow(&err)
+
+	result := models.IstioConfigBatchResult{Objects: []models.IstioConfigBatchObject{}}
+	var objects []*batchObject
+	if objects, err = in.parseBatch(namespace, body); err != nil {
+		return result, err
+	}
+
+	changes := make([]models.IstioConfigChange, 0, len(objects))
+	for _, o := range objects {
+		changes = append(changes, models.IstioConfigChange{Operation: o.Operation, ObjectType: o.ObjectType, Name: o.Name, Object: o.body})
+		result.Objects = append(result.Objects, o.IstioConfigBatchObject)
+	}
+	if result.Validations, err = in.businessLayer.Validations.DryRunBatchValidations(namespace, changes); err != nil {
+		return result, err
+	}
+	result.Valid = !hasErrorChecks(result.Validations.Introduced)
+	if !result.Valid || dryRun {
+		return result, nil
+	}
+
+	for i, o := range objects {
+		api := GetIstioAPI(o.ObjectType)
+		if o.Operation == models.CreateOperation {
+			_, err = in.CreateIstioConfigDetail(api, namespace, o.ObjectType, o.body)
+		} else {
+			_, err = in.UpdateIstioConfigDetail(api, namespace, o.ObjectType, o.Name, string(o.body))
+		}
+		if err != nil {
+			result.Error = fmt.Sprintf("Error applying %s [%s]: %v", o.ObjectType, o.Name, err)
+			if rollbackErr := in.rollbackBatch(namespace, objects[:i], &result); rollbackErr != nil {
+				result.Error = fmt.Sprintf("%s. Error rolling back: %v", result.Error, rollbackErr)
+			} else {
+				result.RolledBack = true
+			}
+			return result, err
+		}
+		result.Objects[i].Applied = true
+	}
+	return result, nil
+}
+
+// parseBatch returns the objects of a batch in the order they are applied
+func (in *IstioConfigService) parseBatch(namespace string, body []byte) ([]*batchObject, error) {
+	manifests, err := util.ParseManifests(body)
+	if err != nil {
+		return nil, errors2.NewBadRequest(fmt.Sprintf("batch could not be parsed: %v", err))
+	}
+	if len(manifests) == 0 {
+		return nil, errors2.NewBadRequest("batch without objects")
+	}
+
+	objects := make([]*batchObject, 0, len(manifests))
+	found := map[string]bool{}
+	for _, manifest := range manifests {
+		kind, _ := manifest["kind"].(string)
+		objectType, order := batchObjectType(kind)
+		if objectType == "" {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("kind not supported in a batch: %s", kind))
+		}
+		metadata, _ := manifest["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		if name == "" {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("%s without name", kind))
+		}
+		if ns, _ := metadata["namespace"].(string); ns != "" && ns != namespace {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("%s [%s] of namespace [%s] can't be applied in namespace [%s]", kind, name, ns, namespace))
+		}
+		key := objectType + "/" + name
+		if found[key] {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("%s [%s] found more than once", kind, name))
+		}
+		found[key] = true
+
+		// Kind and apiVersion are added on create
+		delete(manifest, "kind")
+		delete(manifest, "apiVersion")
+		o := &batchObject{
+			IstioConfigBatchObject: models.IstioConfigBatchObject{ObjectType: objectType, Name: name, Operation: models.CreateOperation},
+			order:                  order,
+		}
+		if o.body, err = json.Marshal(manifest); err != nil {
+			return nil, err
+		}
+		if existing, getErr := in.k8s.GetIstioObject(namespace, objectType, name); getErr == nil {
+			o.Operation = models.UpdateOperation
+			o.before = existing
+			if o.body, err = updatePatch(namespace, objectType, existing, o.body); err != nil {
+				return nil, errors2.NewBadRequest(fmt.Sprintf("%s [%s] could not be parsed: %v", kind, name, err))
+			}
+		} else if !errors2.IsNotFound(getErr) {
+			return nil, getErr
+		}
+		objects = append(objects, o)
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool { return objects[i].order < objects[j].order })
+	return objects, nil
+}
+
+// rollbackBatch restores the objects applied, in the reverse order. It continues on errors, returning all of them.
+// Objects restored are no longer reported as applied in the result.
+func (in *IstioConfigService) rollbackBatch(namespace string, applied []*batchObject, result *models.IstioConfigBatchResult) error {
+	errs := []string{}
+	for i := len(applied) - 1; i >= 0; i-- {
+		o := applied[i]
+		api := GetIstioAPI(o.ObjectType)
+		var err error
+		if o.Operation == models.CreateOperation {
+			err = in.DeleteIstioConfigDetail(api, namespace, o.ObjectType, o.Name)
+		} else {
+			err = in.restoreIstioObject(api, namespace, o.ObjectType, o.before)
+		}
+		if err != nil {
+			log.Errorf("Error rolling back %s [%s/%s]: %v", o.ObjectType, namespace, o.Name, err)
+			errs = append(errs, fmt.Sprintf("%s [%s]: %v", o.ObjectType, o.Name, err))
+		} else {
+			result.Objects[i].Applied = false
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// restoreIstioObject patches the current object back to the user fields of the given object
+func (in *IstioConfigService) restoreIstioObject(api, namespace, objectType string, object kubernetes.IstioObject) error {
+	name := object.GetObjectMeta().Name
+	current, err := in.k8s.GetIstioObject(namespace, objectType, name)
+	if err != nil {
+		return err
+	}
+	var from, to interface{}
+	if from, err = toGenericJSON(reviewableIstioObject(objectType, current)); err != nil {
+		return err
+	}
+	if to, err = toGenericJSON(reviewableIstioObject(objectType, object)); err != nil {
+		return err
+	}
+	patch, err := json.Marshal(util.CreateMergePatch(from, to))
+	if err != nil {
+		return err
+	}
+	_, err = in.UpdateIstioConfigDetail(api, namespace, objectType, name, string(patch))
+	return err
+}
+
+// updatePatch returns the JSON Merge Patch that transforms the user fields of the live object into the ones of the
+// desired object
+func updatePatch(namespace, objectType string, live kubernetes.IstioObject, desired []byte) ([]byte, error) {
+	object := &kubernetes.GenericIstioObject{}
+	if err := json.Unmarshal(desired, object); err != nil {
+		return nil, err
+	}
+	object.Namespace = namespace
+	from, err := toGenericJSON(reviewableIstioObject(objectType, live))
+	if err != nil {
+		return nil, err
+	}
+	to, err := toGenericJSON(reviewableIstioObject(objectType, object))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(util.CreateMergePatch(from, to))
+}
+
+// batchObjectType returns the object type of a kind and its position in the apply order, empty when not supported
+func batchObjectType(kind string) (string, int) {
+	for i, objectType := range batchApplyOrder {
+		if kubernetes.PluralType[objectType] == kind {
+			return objectType, i
+		}
+	}
+	return "", 0
+}
+
+func hasErrorChecks(validations models.IstioValidations) bool {
+	for _, v := range validations {
+		for _, c := range v.Checks {
+			if c.Severity == models.ErrorSeverity {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// toGenericJSON converts a value into the generic maps and lists decoded from JSON
+func toGenericJSON(value interface{}) (interface{}, error) {
+	var generic interface{}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &generic)
+	return generic, err
+}
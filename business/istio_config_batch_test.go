@@ -0,0 +1,162 @@
+package business
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+const customerBatch = `
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: customer-vs
+spec:
+  hosts:
+  - customer
+  http:
+  - route:
+    - destination:
+        host: customer
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: customer-dr
+  namespace: test
+spec:
+  host: customer
+  trafficPolicy:
+    connectionPool:
+      tcp:
+        maxConnections: 10
+`
+
+func TestApplyIstioConfigBatch(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s, applied := mockIstioConfigBatch(nil)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	result, err := layer.IstioConfig.ApplyIstioConfigBatch("test", []byte(customerBatch), false)
+	assert.NoError(err)
+	assert.True(result.Valid)
+	assert.False(result.RolledBack)
+	// DestinationRules are applied before the VirtualServices using them
+	assert.Equal([]models.IstioConfigBatchObject{
+		{ObjectType: kubernetes.DestinationRules, Name: "customer-dr", Operation: models.UpdateOperation, Applied: true},
+		{ObjectType: kubernetes.VirtualServices, Name: "customer-vs", Operation: models.CreateOperation, Applied: true},
+	}, result.Objects)
+	assert.Equal([]string{"UpdateIstioObject destinationrules", "CreateIstioObject virtualservices"}, *applied)
+}
+
+func TestApplyIstioConfigBatchUpdatePatch(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+	k8s := vs.k8s.(*kubetest.K8SClientMock)
+	customerDr := data.AddSubsetToDestinationRule(data.CreateSubset("v1", "v1"), data.CreateEmptyDestinationRule("test", "customer-dr", "customer"))
+	k8s.On("GetIstioObject", "test", kubernetes.DestinationRules, "customer-dr").Return(customerDr, nil)
+	var patch string
+	k8s.On("UpdateIstioObject", mock.Anything, "test", kubernetes.DestinationRules, "customer-dr", mock.Anything).Run(func(args mock.Arguments) {
+		patch = args.String(4)
+	}).Return(customerDr, nil)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	result, err := layer.IstioConfig.ApplyIstioConfigBatch("test", []byte(`
+kind: DestinationRule
+metadata:
+  name: customer-dr
+spec:
+  host: customer
+  trafficPolicy:
+    connectionPool:
+      tcp:
+        maxConnections: 10
+`), false)
+	assert.NoError(err)
+	assert.True(result.Valid)
+	// The subsets missing in the batch are removed
+	assert.JSONEq(`{"spec":{"subsets":null,"trafficPolicy":{"connectionPool":{"tcp":{"maxConnections":10}}}}}`, patch)
+}
+
+func TestApplyIstioConfigBatchRollback(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s, applied := mockIstioConfigBatch(errors.New("create failed"))
+
+	layer := NewWithBackends(k8s, nil, nil)
+	result, err := layer.IstioConfig.ApplyIstioConfigBatch("test", []byte(customerBatch), false)
+	assert.Error(err)
+	assert.True(result.RolledBack)
+	assert.Contains(result.Error, "create failed")
+	assert.False(result.Objects[0].Applied)
+	assert.False(result.Objects[1].Applied)
+	// The DestinationRule updated is restored
+	assert.Equal([]string{"UpdateIstioObject destinationrules", "CreateIstioObject virtualservices", "UpdateIstioObject destinationrules"}, *applied)
+}
+
+func TestApplyIstioConfigBatchInvalid(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s, applied := mockIstioConfigBatch(nil)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	result, err := layer.IstioConfig.ApplyIstioConfigBatch("test", []byte(`
+kind: VirtualService
+metadata:
+  name: customer-vs
+spec:
+  hosts:
+  - customer
+  http:
+  - route:
+    - destination:
+        host: nonexistent
+`), false)
+	assert.NoError(err)
+	assert.False(result.Valid)
+	assert.Contains(result.Validations.Introduced, models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test", Name: "customer-vs"})
+	assert.Empty(*applied)
+
+	_, err = layer.IstioConfig.ApplyIstioConfigBatch("test", []byte(`{"kind": "VirtualService", "metadata": {"name": "customer-vs", "namespace": "other"}}`), false)
+	assert.True(errors2.IsBadRequest(err))
+	_, err = layer.IstioConfig.ApplyIstioConfigBatch("test", []byte(`{"kind": "Deployment", "metadata": {"name": "customer"}}`), false)
+	assert.True(errors2.IsBadRequest(err))
+}
+
+// mockIstioConfigBatch returns the validation mocks with an existing customer-dr, and the writes done on them
+func mockIstioConfigBatch(createErr error) (*kubetest.K8SClientMock, *[]string) {
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+	k8s := vs.k8s.(*kubetest.K8SClientMock)
+
+	applied := []string{}
+	customerDr := fakeCombinedIstioDetails().DestinationRules[1]
+	k8s.On("GetIstioObject", "test", kubernetes.DestinationRules, "customer-dr").Return(customerDr, nil)
+	k8s.On("GetIstioObject", "test", kubernetes.VirtualServices, "customer-vs").Return((*kubernetes.GenericIstioObject)(nil), errors2.NewNotFound(schema.GroupResource{Resource: kubernetes.VirtualServices}, "customer-vs"))
+	k8s.On("UpdateIstioObject", mock.Anything, "test", kubernetes.DestinationRules, "customer-dr", mock.Anything).Run(func(args mock.Arguments) {
+		applied = append(applied, "UpdateIstioObject "+args.String(2))
+	}).Return(customerDr, nil)
+	k8s.On("CreateIstioObject", mock.Anything, "test", kubernetes.VirtualServices, mock.Anything).Run(func(args mock.Arguments) {
+		applied = append(applied, "CreateIstioObject "+args.String(2))
+	}).Return(data.CreateEmptyVirtualService("customer-vs", "test", []string{"customer"}), createErr)
+	return k8s, &applied
+}
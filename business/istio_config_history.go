@@ -0,0 +1,422 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// IstioConfigHistoryStore keeps the revisions of the Istio objects written through Kiali.
+// Stores are shared by all the requests, so they must be safe for concurrent use.
+type IstioConfigHistoryStore interface {
+	// AddRevision stores a new revision of an object, numbered after the last one stored, and returns it
+	AddRevision(revision models.IstioConfigRevision) (models.IstioConfigRevision, error)
+	// GetRevisions returns the revisions of an object, the oldest first
+	GetRevisions(namespace, objectType, name string) ([]models.IstioConfigRevision, error)
+}
+
+// Global store of the Istio config history, nil when the history is disabled
+var istioConfigHistory IstioConfigHistoryStore
+var istioConfigHistoryOnce sync.Once
+
+func initIstioConfigHistory() {
+	conf := config.Get().IstioConfigHistory
+	if !conf.Enabled {
+		return
+	}
+	switch conf.Store {
+	case config.IstioConfigHistoryConfigMapStore:
+		istioConfigHistory = NewConfigMapHistoryStore(getKialiSAClient)
+	case config.IstioConfigHistoryMemoryStore:
+		istioConfigHistory = NewMemoryHistoryStore()
+	default:
+		log.Errorf("Istio config history store not supported: %s. History is disabled", conf.Store)
+	}
+}
+
+// SetIstioConfigHistoryStore allows for specifying the store of the Istio config history, nil disables it.
+// Mock friendly. Used only with tests.
+func SetIstioConfigHistoryStore(store IstioConfigHistoryStore) {
+	istioConfigHistoryOnce.Do(func() {})
+	istioConfigHistory = store
+}
+
+// getKialiSAClient returns a client using the Kiali service account, as users may not be allowed to write the history
+func getKialiSAClient() (kubernetes.ClientInterface, error) {
+	clientFactory, err := kubernetes.GetClientFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientFactory.GetClient(kialiToken)
+}
+
+// appendRevision numbers the revision after the last one and drops the oldest revisions over the configured maximum
+func appendRevision(revisions []models.IstioConfigRevision, revision models.IstioConfigRevision) ([]models.IstioConfigRevision, models.IstioConfigRevision) {
+	revision.Revision = 1
+	if len(revisions) > 0 {
+		revision.Revision = revisions[len(revisions)-1].Revision + 1
+	}
+	revisions = append(revisions, revision)
+	if max := config.Get().IstioConfigHistory.MaxRevisions; max > 0 && len(revisions) > max {
+		revisions = revisions[len(revisions)-max:]
+	}
+	return revisions, revision
+}
+
+// historyKey identifies an object in the history of its namespace
+func historyKey(objectType, name string) string {
+	return objectType + "." + name
+}
+
+// memoryHistoryStore keeps the history in memory, so it is lost on restart and not shared between replicas
+type memoryHistoryStore struct {
+	lock      sync.RWMutex
+	revisions map[string][]models.IstioConfigRevision
+}
+
+func NewMemoryHistoryStore() IstioConfigHistoryStore {
+	return &memoryHistoryStore{revisions: map[string][]models.IstioConfigRevision{}}
+}
+
+func (s *memoryHistoryStore) AddRevision(revision models.IstioConfigRevision) (models.IstioConfigRevision, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := revision.Namespace + "/" + historyKey(revision.ObjectType, revision.Name)
+	s.revisions[key], revision = appendRevision(s.revisions[key], revision)
+	return revision, nil
+}
+
+func (s *memoryHistoryStore) GetRevisions(namespace, objectType, name string) ([]models.IstioConfigRevision, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	revisions := s.revisions[namespace+"/"+historyKey(objectType, name)]
+	return append([]models.IstioConfigRevision{}, revisions...), nil
+}
+
+// configMapHistoryStore keeps the history in a ConfigMap per namespace, with a key per object holding its revisions
+type configMapHistoryStore struct {
+	getClient func() (kubernetes.ClientInterface, error)
+}
+
+// Number of times a revision is added when the ConfigMap is modified concurrently
+const configMapHistoryRetries = 3
+
+func NewConfigMapHistoryStore(getClient func() (kubernetes.ClientInterface, error)) IstioConfigHistoryStore {
+	return &configMapHistoryStore{getClient: getClient}
+}
+
+func (s *configMapHistoryStore) AddRevision(revision models.IstioConfigRevision) (models.IstioConfigRevision, error) {
+	k8s, err := s.getClient()
+	if err != nil {
+		return revision, err
+	}
+	name := config.Get().IstioConfigHistory.ConfigMapName
+	key := historyKey(revision.ObjectType, revision.Name)
+
+	for i := 0; ; i++ {
+		cm, err := k8s.GetConfigMap(revision.Namespace, name)
+		create := errors2.IsNotFound(err)
+		if err != nil && !create {
+			return revision, err
+		}
+		if create {
+			cm = &core_v1.ConfigMap{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:      name,
+					Namespace: revision.Namespace,
+					Labels:    map[string]string{managedByLabel: managedByKiali},
+				},
+			}
+		} else {
+			cm = cm.DeepCopy()
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+
+		revisions, err := parseRevisions(cm.Data[key])
+		if err != nil {
+			return revision, err
+		}
+		var added models.IstioConfigRevision
+		revisions, added = appendRevision(revisions, revision)
+		if err = fitRevisions(cm, key, revisions); err != nil {
+			return revision, err
+		}
+
+		if create {
+			_, err = k8s.CreateConfigMap(revision.Namespace, cm)
+		} else {
+			_, err = k8s.UpdateConfigMap(revision.Namespace, cm)
+		}
+		// Other revision was added since the ConfigMap was read
+		if (errors2.IsConflict(err) || errors2.IsAlreadyExists(err)) && i < configMapHistoryRetries {
+			continue
+		}
+		if err != nil {
+			return revision, err
+		}
+		return added, nil
+	}
+}
+
+// fitRevisions sets the revisions of an object in the ConfigMap, dropping its oldest revisions while the ConfigMap
+// exceeds the configured size. Other objects are left untouched, so an error is returned when the last revision doesn't fit.
+func fitRevisions(cm *core_v1.ConfigMap, key string, revisions []models.IstioConfigRevision) error {
+	maxBytes := config.Get().IstioConfigHistory.MaxConfigMapBytes
+	others := 0
+	for k, v := range cm.Data {
+		if k != key {
+			others += len(k) + len(v)
+		}
+	}
+	for {
+		data, err := json.Marshal(revisions)
+		if err != nil {
+			return err
+		}
+		size := others + len(key) + len(data)
+		if maxBytes <= 0 || size <= maxBytes {
+			cm.Data[key] = string(data)
+			return nil
+		}
+		if len(revisions) == 1 {
+			return fmt.Errorf("revision %d of %s doesn't fit in ConfigMap %s: %d bytes over the limit of %d bytes", revisions[0].Revision, key, cm.Name, size, maxBytes)
+		}
+		revisions = revisions[1:]
+	}
+}
+
+func (s *configMapHistoryStore) GetRevisions(namespace, objectType, name string) ([]models.IstioConfigRevision, error) {
+	k8s, err := s.getClient()
+	if err != nil {
+		return nil, err
+	}
+	cm, err := k8s.GetConfigMap(namespace, config.Get().IstioConfigHistory.ConfigMapName)
+	if errors2.IsNotFound(err) {
+		return []models.IstioConfigRevision{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseRevisions(cm.Data[historyKey(objectType, name)])
+}
+
+func parseRevisions(data string) ([]models.IstioConfigRevision, error) {
+	revisions := []models.IstioConfigRevision{}
+	if data == "" {
+		return revisions, nil
+	}
+	if err := json.Unmarshal([]byte(data), &revisions); err != nil {
+		return nil, fmt.Errorf("invalid Istio config history: %v", err)
+	}
+	sort.SliceStable(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions, nil
+}
+
+// recordRevision stores a change of an object applied through Kiali. The history doesn't block the changes,
+// so errors are only logged.
+func (in *IstioConfigService) recordRevision(namespace, objectType, name, operation string, before, after kubernetes.IstioObject) {
+	if istioConfigHistory == nil {
+		return
+	}
+	revision := models.IstioConfigRevision{
+		Namespace:  namespace,
+		ObjectType: objectType,
+		Name:       name,
+		Operation:  operation,
+		User:       in.user,
+		Timestamp:  time.Now(),
+	}
+	var err error
+	if before != nil {
+		if revision.Before, err = json.Marshal(reviewableIstioObject(objectType, before)); err != nil {
+			log.Errorf("Error recording %s of %s [%s/%s]: %v", operation, objectType, namespace, name, err)
+			return
+		}
+	}
+	if after != nil {
+		if revision.After, err = json.Marshal(reviewableIstioObject(objectType, after)); err != nil {
+			log.Errorf("Error recording %s of %s [%s/%s]: %v", operation, objectType, namespace, name, err)
+			return
+		}
+	}
+	if _, err = istioConfigHistory.AddRevision(revision); err != nil {
+		log.Errorf("Error recording %s of %s [%s/%s]: %v", operation, objectType, namespace, name, err)
+	}
+}
+
+// getRecordedObject returns the object before a change, only when the history is enabled
+func (in *IstioConfigService) getRecordedObject(namespace, objectType, name string) kubernetes.IstioObject {
+	if istioConfigHistory == nil {
+		return nil
+	}
+	object, err := in.k8s.GetIstioObject(namespace, objectType, name)
+	if err != nil {
+		log.Debugf("Object %s [%s/%s] not found for the history: %v", objectType, namespace, name, err)
+		return nil
+	}
+	return object
+}
+
+// GetIstioConfigRevisions returns the revisions of an Istio object written through Kiali, the oldest first
+func (in *IstioConfigService) GetIstioConfigRevisions(namespace, objectType, name string) ([]models.IstioConfigRevision, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "GetIstioConfigRevisions")
+	defer promtimer.ObserveNow(&err)
+
+	if istioConfigHistory == nil {
+		err = errors2.NewBadRequest("Istio config history is disabled")
+		return nil, err
+	}
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	var revisions []models.IstioConfigRevision
+	revisions, err = istioConfigHistory.GetRevisions(namespace, objectType, name)
+	return revisions, err
+}
+
+// DiffIstioConfigRevisions returns the YAML differences of an object between two revisions.
+// When from is 0, the object before the to revision is compared with the object after it.
+func (in *IstioConfigService) DiffIstioConfigRevisions(namespace, objectType, name string, from, to int) (models.IstioConfigRevisionDiff, error) {
+	diff := models.IstioConfigRevisionDiff{From: from, To: to}
+	revisions, err := in.GetIstioConfigRevisions(namespace, objectType, name)
+	if err != nil {
+		return diff, err
+	}
+	if len(revisions) == 0 {
+		return diff, revisionNotFound(objectType, name, to)
+	}
+	if to == 0 {
+		to = revisions[len(revisions)-1].Revision
+		diff.To = to
+	}
+	toRevision, found := findRevision(revisions, to)
+	if !found {
+		return diff, revisionNotFound(objectType, name, to)
+	}
+	fromObject := toRevision.Before
+	if from != 0 {
+		fromRevision, found := findRevision(revisions, from)
+		if !found {
+			return diff, revisionNotFound(objectType, name, from)
+		}
+		fromObject = fromRevision.After
+	}
+
+	fromYAML, err := revisionYAML(fromObject)
+	if err != nil {
+		return diff, err
+	}
+	toYAML, err := revisionYAML(toRevision.After)
+	if err != nil {
+		return diff, err
+	}
+	diff.Diff = util.DiffLines(fromYAML, toYAML)
+	return diff, nil
+}
+
+// RevertIstioConfig restores an object as it was after the given revision. The object is created when it was deleted
+// since, and deleted when the revision was its deletion. The revert is recorded as a new revision.
+func (in *IstioConfigService) RevertIstioConfig(namespace, objectType, name string, revision int) (models.IstioConfigDetails, error) {
+	details := models.IstioConfigDetails{Namespace: models.Namespace{Name: namespace}, ObjectType: objectType}
+	revisions, err := in.GetIstioConfigRevisions(namespace, objectType, name)
+	if err != nil {
+		return details, err
+	}
+	target, found := findRevision(revisions, revision)
+	if !found {
+		return details, revisionNotFound(objectType, name, revision)
+	}
+
+	api := GetIstioAPI(objectType)
+	current, err := in.k8s.GetIstioObject(namespace, objectType, name)
+	if err != nil && !errors2.IsNotFound(err) {
+		return details, err
+	}
+	exists := err == nil
+
+	if len(target.After) == 0 {
+		if exists {
+			err = in.DeleteIstioConfigDetail(api, namespace, objectType, name)
+		}
+		return details, err
+	}
+
+	var targetObject map[string]interface{}
+	if err = json.Unmarshal(target.After, &targetObject); err != nil {
+		return details, err
+	}
+	if !exists {
+		// Kind and apiVersion are added on create
+		delete(targetObject, "kind")
+		delete(targetObject, "apiVersion")
+		body, err := json.Marshal(targetObject)
+		if err != nil {
+			return details, err
+		}
+		return in.CreateIstioConfigDetail(api, namespace, objectType, body)
+	}
+
+	currentObject, err := toGenericJSON(reviewableIstioObject(objectType, current))
+	if err != nil {
+		return details, err
+	}
+	patch, err := json.Marshal(util.CreateMergePatch(currentObject, targetObject))
+	if err != nil {
+		return details, err
+	}
+	return in.UpdateIstioConfigDetail(api, namespace, objectType, name, string(patch))
+}
+
+func findRevision(revisions []models.IstioConfigRevision, revision int) (models.IstioConfigRevision, bool) {
+	for _, r := range revisions {
+		if r.Revision == revision {
+			return r, true
+		}
+	}
+	return models.IstioConfigRevision{}, false
+}
+
+func revisionNotFound(objectType, name string, revision int) error {
+	return errors2.NewNotFound(schema.GroupResource{Group: GetIstioAPI(objectType), Resource: objectType}, name+" revision "+strconv.Itoa(revision))
+}
+
+// revisionYAML returns the YAML of an object stored in a revision, empty when the object didn't exist
+func revisionYAML(object json.RawMessage) (string, error) {
+	if len(object) == 0 {
+		return "", nil
+	}
+	var generic interface{}
+	if err := json.Unmarshal(object, &generic); err != nil {
+		return "", err
+	}
+	b, err := util.MarshalYAML(generic)
+	return string(b), err
+}
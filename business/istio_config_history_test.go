@@ -0,0 +1,224 @@
+package business
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestIstioConfigHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	v1 := fakeHistoryVirtualService("v1")
+	v2 := fakeHistoryVirtualService("v2")
+	api := GetIstioAPI(kubernetes.VirtualServices)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("CreateIstioObject", api, "bookinfo", kubernetes.VirtualServices, mock.AnythingOfType("string")).Return(v1, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(v1, nil).Once()
+	k8s.On("UpdateIstioObject", api, "bookinfo", kubernetes.VirtualServices, "reviews", `{"spec":{"http":[{"route":[{"destination":{"subset":"v2"}}]}]}}`).Return(v2, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	SetIstioConfigHistoryStore(NewMemoryHistoryStore())
+	defer SetIstioConfigHistoryStore(nil)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	layer.SetUser("admin")
+
+	_, err := layer.IstioConfig.CreateIstioConfigDetail(api, "bookinfo", kubernetes.VirtualServices, []byte(`{"metadata":{"name":"reviews"}}`))
+	assert.NoError(err)
+	_, err = layer.IstioConfig.UpdateIstioConfigDetail(api, "bookinfo", kubernetes.VirtualServices, "reviews", `{"spec":{"http":[{"route":[{"destination":{"subset":"v2"}}]}]}}`)
+	assert.NoError(err)
+
+	revisions, err := layer.IstioConfig.GetIstioConfigRevisions("bookinfo", kubernetes.VirtualServices, "reviews")
+	assert.NoError(err)
+	assert.Len(revisions, 2)
+	assert.Equal(1, revisions[0].Revision)
+	assert.Equal(models.CreateOperation, revisions[0].Operation)
+	assert.Equal("admin", revisions[0].User)
+	assert.Empty(revisions[0].Before)
+	assert.Equal(2, revisions[1].Revision)
+	assert.Equal(models.UpdateOperation, revisions[1].Operation)
+
+	var after map[string]interface{}
+	assert.NoError(json.Unmarshal(revisions[1].After, &after))
+	assert.Equal("VirtualService", after["kind"])
+	assert.Equal("networking.istio.io/v1alpha3", after["apiVersion"])
+
+	diff, err := layer.IstioConfig.DiffIstioConfigRevisions("bookinfo", kubernetes.VirtualServices, "reviews", 0, 0)
+	assert.NoError(err)
+	assert.Equal(2, diff.To)
+	assert.Contains(diff.Diff, "-        subset: v1\n")
+	assert.Contains(diff.Diff, "+        subset: v2\n")
+
+	diff, err = layer.IstioConfig.DiffIstioConfigRevisions("bookinfo", kubernetes.VirtualServices, "reviews", 2, 1)
+	assert.NoError(err)
+	assert.Contains(diff.Diff, "-        subset: v2\n")
+	assert.Contains(diff.Diff, "+        subset: v1\n")
+
+	// Revert only patches the fields changed since the revision
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(v2, nil).Twice()
+	k8s.On("UpdateIstioObject", api, "bookinfo", kubernetes.VirtualServices, "reviews", `{"spec":{"http":[{"route":[{"destination":{"subset":"v1"}}]}]}}`).Return(v1, nil)
+	_, err = layer.IstioConfig.RevertIstioConfig("bookinfo", kubernetes.VirtualServices, "reviews", 1)
+	assert.NoError(err)
+
+	revisions, err = layer.IstioConfig.GetIstioConfigRevisions("bookinfo", kubernetes.VirtualServices, "reviews")
+	assert.NoError(err)
+	assert.Len(revisions, 3)
+	assert.Equal(models.UpdateOperation, revisions[2].Operation)
+	assert.JSONEq(string(revisions[0].After), string(revisions[2].After))
+
+	_, err = layer.IstioConfig.RevertIstioConfig("bookinfo", kubernetes.VirtualServices, "reviews", 10)
+	assert.True(errors.IsNotFound(err))
+}
+
+func TestIstioConfigHistoryDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	SetIstioConfigHistoryStore(nil)
+
+	_, err := NewWithBackends(k8s, nil, nil).IstioConfig.GetIstioConfigRevisions("bookinfo", kubernetes.VirtualServices, "reviews")
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestMemoryHistoryStoreMaxRevisions(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.IstioConfigHistory.MaxRevisions = 2
+	config.Set(conf)
+
+	store := NewMemoryHistoryStore()
+	for i := 0; i < 3; i++ {
+		_, err := store.AddRevision(models.IstioConfigRevision{Namespace: "bookinfo", ObjectType: kubernetes.VirtualServices, Name: "reviews"})
+		assert.NoError(err)
+	}
+	revisions, err := store.GetRevisions("bookinfo", kubernetes.VirtualServices, "reviews")
+	assert.NoError(err)
+	assert.Len(revisions, 2)
+	assert.Equal(2, revisions[0].Revision)
+	assert.Equal(3, revisions[1].Revision)
+}
+
+func TestConfigMapHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	name := conf.IstioConfigHistory.ConfigMapName
+	revision := models.IstioConfigRevision{Namespace: "bookinfo", ObjectType: kubernetes.VirtualServices, Name: "reviews"}
+
+	// The ConfigMap is created with the first revision
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetConfigMap", "bookinfo", name).Return((*core_v1.ConfigMap)(nil), errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name))
+	var created *core_v1.ConfigMap
+	k8s.On("CreateConfigMap", "bookinfo", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*core_v1.ConfigMap)
+	}).Return(&core_v1.ConfigMap{}, nil)
+
+	store := NewConfigMapHistoryStore(func() (kubernetes.ClientInterface, error) { return k8s, nil })
+	added, err := store.AddRevision(revision)
+	assert.NoError(err)
+	assert.Equal(1, added.Revision)
+	assert.Equal(name, created.Name)
+	assert.Contains(created.Data, "virtualservices.reviews")
+
+	// Revisions added concurrently are retried
+	existing := created.DeepCopy()
+	k8s = new(kubetest.K8SClientMock)
+	k8s.On("GetConfigMap", "bookinfo", name).Return(existing, nil)
+	k8s.On("UpdateConfigMap", "bookinfo", mock.Anything).Return((*core_v1.ConfigMap)(nil), errors.NewConflict(schema.GroupResource{Resource: "configmaps"}, name, nil)).Once()
+	var updated *core_v1.ConfigMap
+	k8s.On("UpdateConfigMap", "bookinfo", mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(*core_v1.ConfigMap)
+	}).Return(&core_v1.ConfigMap{}, nil).Once()
+
+	store = NewConfigMapHistoryStore(func() (kubernetes.ClientInterface, error) { return k8s, nil })
+	added, err = store.AddRevision(revision)
+	assert.NoError(err)
+	assert.Equal(2, added.Revision)
+	k8s.AssertNumberOfCalls(t, "UpdateConfigMap", 2)
+
+	k8s = new(kubetest.K8SClientMock)
+	k8s.On("GetConfigMap", "bookinfo", name).Return(updated, nil)
+	store = NewConfigMapHistoryStore(func() (kubernetes.ClientInterface, error) { return k8s, nil })
+	revisions, err := store.GetRevisions("bookinfo", kubernetes.VirtualServices, "reviews")
+	assert.NoError(err)
+	assert.Len(revisions, 2)
+	// The ConfigMap read is never modified
+	assert.Len(existing.Data, 1)
+	assert.NotEqual(existing.Data["virtualservices.reviews"], updated.Data["virtualservices.reviews"])
+}
+
+func TestConfigMapHistoryStoreMaxBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.IstioConfigHistory.MaxConfigMapBytes = 1200
+	config.Set(conf)
+	name := conf.IstioConfigHistory.ConfigMapName
+	revision := models.IstioConfigRevision{Namespace: "bookinfo", ObjectType: kubernetes.VirtualServices, Name: "reviews", After: json.RawMessage(`"` + strings.Repeat("a", 200) + `"`)}
+
+	cm := &core_v1.ConfigMap{ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "bookinfo"}, Data: map[string]string{"destinationrules.reviews": strings.Repeat("b", 300)}}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetConfigMap", "bookinfo", name).Return(cm, nil)
+	k8s.On("UpdateConfigMap", "bookinfo", mock.Anything).Run(func(args mock.Arguments) {
+		*cm = *args.Get(1).(*core_v1.ConfigMap)
+	}).Return(&core_v1.ConfigMap{}, nil)
+
+	// The oldest revisions of the object are dropped to fit in the ConfigMap, other objects are kept
+	store := NewConfigMapHistoryStore(func() (kubernetes.ClientInterface, error) { return k8s, nil })
+	for i := 0; i < 5; i++ {
+		_, err := store.AddRevision(revision)
+		assert.NoError(err)
+	}
+	revisions, err := store.GetRevisions("bookinfo", kubernetes.VirtualServices, "reviews")
+	assert.NoError(err)
+	assert.Len(revisions, 2)
+	assert.Equal(4, revisions[0].Revision)
+	assert.Equal(5, revisions[1].Revision)
+	assert.Len(cm.Data["destinationrules.reviews"], 300)
+
+	revision.After = json.RawMessage(`"` + strings.Repeat("a", 1000) + `"`)
+	_, err = store.AddRevision(revision)
+	assert.Error(err)
+}
+
+func fakeHistoryVirtualService(subset string) kubernetes.IstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "reviews",
+			Namespace: "bookinfo",
+		},
+		Spec: map[string]interface{}{
+			"hosts": []interface{}{"reviews"},
+			"http": []interface{}{
+				map[string]interface{}{
+					"route": []interface{}{
+						map[string]interface{}{"destination": map[string]interface{}{"subset": subset}},
+					},
+				},
+			},
+		},
+	}
+}
@@ -3,8 +3,10 @@ package business
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	auth_v1 "k8s.io/api/authorization/v1"
@@ -14,6 +16,7 @@ import (
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
 	"github.com/kiali/kiali/tests/data"
 )
 
@@ -762,3 +765,48 @@ func TestFilterIstioObjectsForWorkloadSelector(t *testing.T) {
 	sec := kubernetes.FilterIstioObjectsForWorkloadSelector(s, istioObjects)
 	assert.Equal(3, len(sec))
 }
+
+func TestGenerateSidecar(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+
+	destination := func(service, namespace string) *model.Sample {
+		return &model.Sample{Metric: model.Metric{
+			"source_workload":               "productpage-v1",
+			"source_workload_namespace":     "bookinfo",
+			"destination_service":           model.LabelValue(service),
+			"destination_service_namespace": model.LabelValue(namespace),
+		}}
+	}
+	queryTime := time.Now()
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetOutboundTraffic", "bookinfo", "", "1h", queryTime).Return(model.Vector{
+		destination("reviews.bookinfo.svc.cluster.local", "bookinfo"),
+		destination("details.bookinfo.svc.cluster.local", "bookinfo"),
+		destination("istiod.istio-system.svc.cluster.local", "istio-system"),
+		destination("www.wikipedia.org", "unknown"),
+	}, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	sidecar, err := NewWithBackends(k8s, prom, nil).IstioConfig.GenerateSidecar("bookinfo", "", "1h", queryTime)
+	assert.NoError(err)
+	assert.Equal("default", sidecar.GetObjectMeta().Name)
+	assert.Equal("bookinfo", sidecar.GetObjectMeta().Namespace)
+	assert.Equal("Sidecar", sidecar.GetTypeMeta().Kind)
+	assert.Nil(sidecar.GetSpec()["workloadSelector"])
+	assert.Equal([]interface{}{
+		map[string]interface{}{
+			"hosts": []interface{}{
+				"istio-system/*",
+				"*/www.wikipedia.org",
+				"bookinfo/details.bookinfo.svc.cluster.local",
+				"bookinfo/reviews.bookinfo.svc.cluster.local",
+			},
+		},
+	}, sidecar.GetSpec()["egress"])
+}
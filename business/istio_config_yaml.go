@@ -0,0 +1,238 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// lastAppliedAnnotation holds the object applied by kubectl, used as original of the three-way merge
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// manifestOrder is the order of the object types in the manifests of a list, the same as they are applied in a batch
+var manifestOrder = append(append([]string{kubernetes.WorkloadEntries}, batchApplyOrder...), kubernetes.EnvoyFilters)
+
+// IstioConfigListManifests returns the objects of a list as manifests with the fields set by the user,
+// so they can be applied again
+func (in *IstioConfigService) IstioConfigListManifests(list models.IstioConfigList) ([]map[string]interface{}, error) {
+	items := map[string]interface{}{
+		kubernetes.Gateways:               list.Gateways,
+		kubernetes.VirtualServices:        list.VirtualServices.Items,
+		kubernetes.DestinationRules:       list.DestinationRules.Items,
+		kubernetes.ServiceEntries:         list.ServiceEntries,
+		kubernetes.WorkloadEntries:        list.WorkloadEntries,
+		kubernetes.EnvoyFilters:           list.EnvoyFilters,
+		kubernetes.Sidecars:               list.Sidecars,
+		kubernetes.AuthorizationPolicies:  list.AuthorizationPolicies,
+		kubernetes.PeerAuthentications:    list.PeerAuthentications,
+		kubernetes.RequestAuthentications: list.RequestAuthentications,
+	}
+	manifests := []map[string]interface{}{}
+	for _, objectType := range manifestOrder {
+		generic, err := toGenericJSON(items[objectType])
+		if err != nil {
+			return nil, err
+		}
+		objects, _ := generic.([]interface{})
+		for _, o := range objects {
+			if object, isMap := o.(map[string]interface{}); isMap {
+				manifests = append(manifests, reviewableManifest(objectType, object))
+			}
+		}
+	}
+	return manifests, nil
+}
+
+// IstioConfigDetailsManifest returns the object of the details as a manifest with the fields set by the user
+func (in *IstioConfigService) IstioConfigDetailsManifest(details models.IstioConfigDetails) (map[string]interface{}, error) {
+	var object interface{}
+	switch details.ObjectType {
+	case kubernetes.Gateways:
+		object = details.Gateway
+	case kubernetes.VirtualServices:
+		object = details.VirtualService
+	case kubernetes.DestinationRules:
+		object = details.DestinationRule
+	case kubernetes.ServiceEntries:
+		object = details.ServiceEntry
+	case kubernetes.WorkloadEntries:
+		object = details.WorkloadEntry
+	case kubernetes.EnvoyFilters:
+		object = details.EnvoyFilter
+	case kubernetes.Sidecars:
+		object = details.Sidecar
+	case kubernetes.AuthorizationPolicies:
+		object = details.AuthorizationPolicy
+	case kubernetes.PeerAuthentications:
+		object = details.PeerAuthentication
+	case kubernetes.RequestAuthentications:
+		object = details.RequestAuthentication
+	default:
+		return nil, fmt.Errorf("object type not found: %v", details.ObjectType)
+	}
+	generic, err := toGenericJSON(object)
+	if err != nil {
+		return nil, err
+	}
+	manifest, isMap := generic.(map[string]interface{})
+	if !isMap {
+		return nil, fmt.Errorf("%s not found in the details", details.ObjectType)
+	}
+	return reviewableManifest(details.ObjectType, manifest), nil
+}
+
+// CreateIstioConfigDetails creates several objects of the same type, in order. If one fails, the objects already
+// created are deleted.
+func (in *IstioConfigService) CreateIstioConfigDetails(api, namespace, resourceType string, objects []map[string]interface{}) ([]models.IstioConfigDetails, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "CreateIstioConfigDetails")
+	defer promtimer.ObserveNow(&err)
+
+	bodies := make([][]byte, 0, len(objects))
+	for _, object := range objects {
+		if err = checkManifestKind(resourceType, object); err != nil {
+			return nil, err
+		}
+		// Kind and apiVersion are added on create
+		delete(object, "kind")
+		delete(object, "apiVersion")
+		var body []byte
+		if body, err = json.Marshal(object); err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, body)
+	}
+
+	created := make([]models.IstioConfigDetails, 0, len(bodies))
+	for _, body := range bodies {
+		var details models.IstioConfigDetails
+		if details, err = in.CreateIstioConfigDetail(api, namespace, resourceType, body); err != nil {
+			for i := len(created) - 1; i >= 0; i-- {
+				name := in.manifestName(created[i])
+				if deleteErr := in.DeleteIstioConfigDetail(api, namespace, resourceType, name); deleteErr != nil {
+					log.Errorf("Error deleting %s [%s/%s] created before the error: %v", resourceType, namespace, name, deleteErr)
+				}
+			}
+			return nil, err
+		}
+		created = append(created, details)
+	}
+	return created, nil
+}
+
+// ReplaceIstioConfigDetail updates an object to the given full object with a three-way merge, as kubectl apply does.
+// The original object is the last one applied by kubectl or, when not found, the last revision written through Kiali.
+// Without original, the fields set by the user that are not in the given object are removed.
+func (in *IstioConfigService) ReplaceIstioConfigDetail(api, namespace, resourceType, name string, object map[string]interface{}) (models.IstioConfigDetails, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ReplaceIstioConfigDetail")
+	defer promtimer.ObserveNow(&err)
+
+	details := models.IstioConfigDetails{Namespace: models.Namespace{Name: namespace}, ObjectType: resourceType}
+	if err = checkManifestKind(resourceType, object); err != nil {
+		return details, err
+	}
+	modified := reviewableManifest(resourceType, object)
+	metadata := modified["metadata"].(map[string]interface{})
+	if n, _ := metadata["name"].(string); n != "" && n != name {
+		err = errors2.NewBadRequest(fmt.Sprintf("name [%s] doesn't match the object updated [%s]", n, name))
+		return details, err
+	}
+	if ns, _ := metadata["namespace"].(string); ns != "" && ns != namespace {
+		err = errors2.NewBadRequest(fmt.Sprintf("namespace [%s] doesn't match the namespace updated [%s]", ns, namespace))
+		return details, err
+	}
+	metadata["name"] = name
+	metadata["namespace"] = namespace
+
+	var current kubernetes.IstioObject
+	if current, err = in.k8s.GetIstioObject(namespace, resourceType, name); err != nil {
+		return details, err
+	}
+	var currentManifest interface{}
+	if currentManifest, err = toGenericJSON(reviewableIstioObject(resourceType, current)); err != nil {
+		return details, err
+	}
+	original := in.originalManifest(namespace, resourceType, name, current)
+	if original == nil {
+		original = currentManifest
+	}
+
+	var patch []byte
+	if patch, err = json.Marshal(util.CreateThreeWayMergePatch(original, modified, currentManifest)); err != nil {
+		return details, err
+	}
+	details, err = in.UpdateIstioConfigDetail(api, namespace, resourceType, name, string(patch))
+	return details, err
+}
+
+// originalManifest returns the object the user modified, nil when not found
+func (in *IstioConfigService) originalManifest(namespace, resourceType, name string, current kubernetes.IstioObject) interface{} {
+	if lastApplied, found := current.GetObjectMeta().Annotations[lastAppliedAnnotation]; found {
+		var original map[string]interface{}
+		if err := json.Unmarshal([]byte(lastApplied), &original); err == nil {
+			return reviewableManifest(resourceType, original)
+		}
+		log.Debugf("Invalid %s annotation in %s [%s/%s]", lastAppliedAnnotation, resourceType, namespace, name)
+	}
+	if istioConfigHistory == nil {
+		return nil
+	}
+	revisions, err := istioConfigHistory.GetRevisions(namespace, resourceType, name)
+	if err != nil || len(revisions) == 0 || len(revisions[len(revisions)-1].After) == 0 {
+		return nil
+	}
+	var original interface{}
+	if err = json.Unmarshal(revisions[len(revisions)-1].After, &original); err != nil {
+		return nil
+	}
+	return original
+}
+
+// reviewableManifest returns the fields of a generic Istio object that are set by the user, as reviewableIstioObject
+func reviewableManifest(objectType string, object map[string]interface{}) map[string]interface{} {
+	meta, _ := object["metadata"].(map[string]interface{})
+	metadata := map[string]interface{}{
+		"name":      meta["name"],
+		"namespace": meta["namespace"],
+	}
+	for _, field := range []string{"labels", "annotations"} {
+		if values, isMap := meta[field].(map[string]interface{}); isMap && len(values) > 0 {
+			metadata[field] = values
+		}
+	}
+	manifest := map[string]interface{}{
+		"apiVersion": kubernetes.ApiToVersion[kubernetes.ResourceTypesToAPI[objectType]],
+		"kind":       kubernetes.PluralType[objectType],
+		"metadata":   metadata,
+	}
+	if spec, found := object["spec"]; found {
+		manifest["spec"] = spec
+	}
+	return manifest
+}
+
+// checkManifestKind returns a BadRequest error when the object is of another kind than the type managed
+func checkManifestKind(resourceType string, object map[string]interface{}) error {
+	if kind, _ := object["kind"].(string); kind != "" && kind != kubernetes.PluralType[resourceType] {
+		return errors2.NewBadRequest(fmt.Sprintf("kind [%s] doesn't match the object type [%s]", kind, resourceType))
+	}
+	return nil
+}
+
+// manifestName returns the name of the object of the details
+func (in *IstioConfigService) manifestName(details models.IstioConfigDetails) string {
+	manifest, err := in.IstioConfigDetailsManifest(details)
+	if err != nil {
+		return ""
+	}
+	name, _ := manifest["metadata"].(map[string]interface{})["name"].(string)
+	return name
+}
@@ -0,0 +1,133 @@
+package business
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func TestReplaceIstioConfigDetail(t *testing.T) {
+	assert := assert.New(t)
+
+	current := &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "reviews",
+			Namespace: "bookinfo",
+			Labels:    map[string]string{"set-by": "others"},
+			Annotations: map[string]string{
+				lastAppliedAnnotation: `{"kind":"VirtualService","metadata":{"name":"reviews"},"spec":{"hosts":["reviews"],"gateways":["bookinfo-gateway"]}}`,
+			},
+		},
+		Spec: map[string]interface{}{
+			"hosts":    []interface{}{"reviews"},
+			"gateways": []interface{}{"bookinfo-gateway"},
+			"exportTo": []interface{}{"."},
+		},
+	}
+	api := GetIstioAPI(kubernetes.VirtualServices)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(current, nil)
+	// Gateways removed since the last kubectl apply are deleted, while fields set by others are kept
+	k8s.On("UpdateIstioObject", api, "bookinfo", kubernetes.VirtualServices, "reviews", `{"spec":{"gateways":null,"http":[{"route":[{"destination":{"host":"reviews"}}]}]}}`).Return(current, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	SetIstioConfigHistoryStore(nil)
+
+	manifests, err := util.ParseManifests([]byte(`
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: reviews
+  resourceVersion: "42"
+spec:
+  hosts:
+  - reviews
+  http:
+  - route:
+    - destination:
+        host: reviews
+`))
+	assert.NoError(err)
+	layer := NewWithBackends(k8s, nil, nil)
+	_, err = layer.IstioConfig.ReplaceIstioConfigDetail(api, "bookinfo", kubernetes.VirtualServices, "reviews", manifests[0])
+	assert.NoError(err)
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+
+	_, err = layer.IstioConfig.ReplaceIstioConfigDetail(api, "bookinfo", kubernetes.VirtualServices, "ratings", manifests[0])
+	assert.Error(err)
+	_, err = layer.IstioConfig.ReplaceIstioConfigDetail(api, "bookinfo", kubernetes.DestinationRules, "reviews", manifests[0])
+	assert.Error(err)
+}
+
+func TestIstioConfigListManifests(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := models.VirtualService{}
+	vs.Metadata = meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo", ResourceVersion: "42"}
+	vs.Spec.Hosts = []interface{}{"reviews"}
+	dr := models.DestinationRule{}
+	dr.Metadata = meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}
+	dr.Spec.Host = "reviews"
+	list := models.IstioConfigList{
+		VirtualServices:  models.VirtualServices{Items: []models.VirtualService{vs}},
+		DestinationRules: models.DestinationRules{Items: []models.DestinationRule{dr}},
+	}
+
+	manifests, err := (&IstioConfigService{}).IstioConfigListManifests(list)
+	assert.NoError(err)
+	assert.Len(manifests, 2)
+	// Listed in the order they are applied
+	assert.Equal("DestinationRule", manifests[0]["kind"])
+	assert.Equal("VirtualService", manifests[1]["kind"])
+	assert.Equal("networking.istio.io/v1alpha3", manifests[1]["apiVersion"])
+	assert.Equal(map[string]interface{}{"name": "reviews", "namespace": "bookinfo"}, manifests[1]["metadata"])
+	assert.Equal([]interface{}{"reviews"}, manifests[1]["spec"].(map[string]interface{})["hosts"])
+}
+
+func TestCreateIstioConfigDetailsRollback(t *testing.T) {
+	assert := assert.New(t)
+	api := GetIstioAPI(kubernetes.VirtualServices)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("CreateIstioObject", api, "bookinfo", kubernetes.VirtualServices, mock.Anything).Return(fakeHistoryVirtualService("v1"), nil).Once()
+	k8s.On("CreateIstioObject", api, "bookinfo", kubernetes.VirtualServices, mock.Anything).Return(fakeHistoryVirtualService("v1"), errors.New("create failed")).Once()
+	k8s.On("DeleteIstioObject", api, "bookinfo", kubernetes.VirtualServices, "reviews").Return(nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	SetIstioConfigHistoryStore(nil)
+
+	manifests, err := util.ParseManifests([]byte(`
+kind: VirtualService
+metadata:
+  name: reviews
+---
+kind: VirtualService
+metadata:
+  name: ratings
+`))
+	assert.NoError(err)
+	layer := NewWithBackends(k8s, nil, nil)
+	_, err = layer.IstioConfig.CreateIstioConfigDetails(api, "bookinfo", kubernetes.VirtualServices, manifests)
+	assert.Error(err)
+	// The objects created before the error are deleted
+	k8s.AssertCalled(t, "DeleteIstioObject", api, "bookinfo", kubernetes.VirtualServices, "reviews")
+
+	manifests, err = util.ParseManifests([]byte(`{"kind": "DestinationRule", "metadata": {"name": "reviews"}}`))
+	assert.NoError(err)
+	_, err = layer.IstioConfig.CreateIstioConfigDetails(api, "bookinfo", kubernetes.VirtualServices, manifests)
+	assert.Error(err)
+}
@@ -0,0 +1,389 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// Types of the objects whose references are looked up, besides Gateways and DestinationRules
+const (
+	ServiceReferences  = "services"
+	WorkloadReferences = "workloads"
+)
+
+// referenceTarget is an object whose references are looked up
+type referenceTarget struct {
+	namespace string
+	// Host of a service, or of the DestinationRule whose subsets are referenced
+	host *kubernetes.Host
+	// Subsets of a DestinationRule
+	subsets map[string]bool
+	// Gateway referenced by the VirtualServices
+	gateway *kubernetes.Host
+	// Labels of a workload, matched by selectors
+	labels labels.Set
+	// Namespaces of the cluster, used to parse the hosts with two parts
+	clusterNamespaces []string
+}
+
+// referenceTypes are the types of the Istio objects that can reference each type of object
+var referenceTypes = map[string][]string{
+	ServiceReferences:           {kubernetes.VirtualServices, kubernetes.DestinationRules, kubernetes.Sidecars, kubernetes.EnvoyFilters},
+	WorkloadReferences:          {kubernetes.Gateways, kubernetes.Sidecars, kubernetes.EnvoyFilters, kubernetes.AuthorizationPolicies, kubernetes.PeerAuthentications},
+	kubernetes.Gateways:         {kubernetes.VirtualServices},
+	kubernetes.DestinationRules: {kubernetes.VirtualServices},
+}
+
+// GetIstioReferences returns the Istio objects referencing a service, workload, Gateway or DestinationRule, with the
+// JSON path of each reference:
+// - VirtualServices routing to a service, bound to a Gateway or routing to the subsets of a DestinationRule
+// - DestinationRules and Sidecars with the host of a service, EnvoyFilters patching its cluster or virtual host
+// - Gateways of all the namespaces, and Sidecars, EnvoyFilters, AuthorizationPolicies and PeerAuthentications selecting a workload
+// - AuthorizationPolicies and PeerAuthentications without selector in the namespace of a workload or in the root namespace
+func (in *IstioConfigService) GetIstioReferences(namespace, objectType, name string) (models.IstioReferences, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "GetIstioReferences")
+	defer promtimer.ObserveNow(&err)
+
+	references := models.IstioReferences{ObjectType: objectType, Namespace: namespace, Name: name, References: []models.IstioReference{}}
+	types, found := referenceTypes[objectType]
+	if !found {
+		err = errors2.NewBadRequest(fmt.Sprintf("references of %s not supported", objectType))
+		return references, err
+	}
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return references, err
+	}
+	var nss []models.Namespace
+	if nss, err = in.businessLayer.Namespace.GetNamespaces(); err != nil {
+		return references, err
+	}
+	clusterNamespaces := make([]string, 0, len(nss))
+	for _, ns := range nss {
+		clusterNamespaces = append(clusterNamespaces, ns.Name)
+	}
+	var target *referenceTarget
+	if target, err = in.getReferenceTarget(namespace, objectType, name, clusterNamespaces); err != nil {
+		return references, err
+	}
+
+	namespaces := make(map[string][]string, len(types))
+	for _, t := range types {
+		namespaces[t] = referenceNamespaces(namespace, objectType, t, clusterNamespaces)
+	}
+	var objects map[string][]kubernetes.IstioObject
+	if objects, err = in.fetchReferencingObjects(namespaces); err != nil {
+		return references, err
+	}
+	for _, t := range types {
+		for _, o := range objects[t] {
+			for _, path := range target.referencesFrom(t, o) {
+				references.References = append(references.References, models.IstioReference{
+					ObjectType: t,
+					Namespace:  o.GetObjectMeta().Namespace,
+					Name:       o.GetObjectMeta().Name,
+					Path:       path,
+				})
+			}
+		}
+	}
+	sort.SliceStable(references.References, func(i, j int) bool {
+		ri, rj := references.References[i], references.References[j]
+		if ri.ObjectType != rj.ObjectType {
+			return ri.ObjectType < rj.ObjectType
+		}
+		if ri.Namespace != rj.Namespace {
+			return ri.Namespace < rj.Namespace
+		}
+		return ri.Name < rj.Name
+	})
+	return references, nil
+}
+
+// getReferenceTarget returns the object whose references are looked up, an error when not found
+func (in *IstioConfigService) getReferenceTarget(namespace, objectType, name string, clusterNamespaces []string) (*referenceTarget, error) {
+	target := &referenceTarget{namespace: namespace, clusterNamespaces: clusterNamespaces}
+	domain := config.Get().ExternalServices.Istio.IstioIdentityDomain
+	switch objectType {
+	case ServiceReferences:
+		if _, err := in.k8s.GetService(namespace, name); err != nil {
+			return nil, err
+		}
+		target.host = &kubernetes.Host{Service: name, Namespace: namespace, Cluster: domain, CompleteInput: true}
+	case WorkloadReferences:
+		workload, err := fetchWorkload(in.businessLayer, namespace, name, "")
+		if err != nil {
+			return nil, err
+		}
+		target.labels = labels.Set(workload.Labels)
+	case kubernetes.Gateways:
+		if _, err := in.k8s.GetIstioObject(namespace, objectType, name); err != nil {
+			return nil, err
+		}
+		target.gateway = &kubernetes.Host{Service: name, Namespace: namespace, Cluster: domain, CompleteInput: true}
+	case kubernetes.DestinationRules:
+		dr, err := in.k8s.GetIstioObject(namespace, objectType, name)
+		if err != nil {
+			return nil, err
+		}
+		hostName, _ := dr.GetSpec()["host"].(string)
+		host := kubernetes.GetHost(hostName, namespace, "", clusterNamespaces)
+		target.host = &host
+		target.subsets = map[string]bool{}
+		for _, s := range referenceList(dr.GetSpec(), "subsets") {
+			if subset, ok := s.(map[string]interface{}); ok {
+				if subsetName, ok := subset["name"].(string); ok {
+					target.subsets[subsetName] = true
+				}
+			}
+		}
+	}
+	return target, nil
+}
+
+// referenceNamespaces returns the namespaces of the objects of a type that can reference the target. Selectors only
+// apply to the workloads of their namespace, or to all of them from the root namespace, except the selectors of the
+// Gateways, which apply to the workloads of all the namespaces.
+func referenceNamespaces(namespace, objectType, referencingType string, clusterNamespaces []string) []string {
+	if objectType != WorkloadReferences || referencingType == kubernetes.Gateways {
+		return clusterNamespaces
+	}
+	namespaces := []string{namespace}
+	if istioNamespace := config.Get().IstioNamespace; istioNamespace != namespace {
+		namespaces = append(namespaces, istioNamespace)
+	}
+	return namespaces
+}
+
+// fetchReferencingObjects returns the Istio objects of each type in its namespaces, by type
+func (in *IstioConfigService) fetchReferencingObjects(namespaces map[string][]string) (map[string][]kubernetes.IstioObject, error) {
+	objects := make(map[string][]kubernetes.IstioObject, len(namespaces))
+	var mutex sync.Mutex
+	wg := sync.WaitGroup{}
+	count := 0
+	for _, nss := range namespaces {
+		count += len(nss)
+	}
+	errChan := make(chan error, count)
+	for t, nss := range namespaces {
+		for _, ns := range nss {
+			wg.Add(1)
+			go func(namespace, objectType string) {
+				defer wg.Done()
+				var list []kubernetes.IstioObject
+				var err error
+				// Check if namespace is cached
+				if IsResourceCached(namespace, objectType) {
+					list, err = kialiCache.GetIstioObjects(namespace, objectType, "")
+				} else {
+					list, err = in.k8s.GetIstioObjects(namespace, objectType, "")
+				}
+				if err != nil {
+					errChan <- err
+					return
+				}
+				mutex.Lock()
+				objects[objectType] = append(objects[objectType], list...)
+				mutex.Unlock()
+			}(ns, t)
+		}
+	}
+	wg.Wait()
+	if len(errChan) != 0 {
+		return nil, <-errChan
+	}
+	return objects, nil
+}
+
+// referencesFrom returns the JSON paths of the fields of an object referencing the target
+func (t *referenceTarget) referencesFrom(objectType string, object kubernetes.IstioObject) []string {
+	paths := []string{}
+	spec := object.GetSpec()
+	namespace := object.GetObjectMeta().Namespace
+	switch objectType {
+	case kubernetes.VirtualServices:
+		if t.host != nil && t.subsets == nil {
+			for i, h := range referenceList(spec, "hosts") {
+				if t.isHost(h, namespace) {
+					paths = append(paths, fmt.Sprintf("spec.hosts[%d]", i))
+				}
+			}
+		}
+		for _, protocol := range []string{"http", "tcp", "tls"} {
+			for i, r := range referenceList(spec, protocol) {
+				route, _ := r.(map[string]interface{})
+				for j, d := range referenceList(route, "route") {
+					destination, _ := d.(map[string]interface{})
+					paths = append(paths, t.destinationReferences(fmt.Sprintf("spec.%s[%d].route[%d].destination", protocol, i, j), destination["destination"], namespace)...)
+				}
+				if protocol == "http" {
+					paths = append(paths, t.destinationReferences(fmt.Sprintf("spec.http[%d].mirror", i), route["mirror"], namespace)...)
+				}
+				if t.gateway != nil {
+					for m, mt := range referenceList(route, "match") {
+						match, _ := mt.(map[string]interface{})
+						for g, gw := range referenceList(match, "gateways") {
+							if t.isGateway(gw, namespace) {
+								paths = append(paths, fmt.Sprintf("spec.%s[%d].match[%d].gateways[%d]", protocol, i, m, g))
+							}
+						}
+					}
+				}
+			}
+		}
+		if t.gateway != nil {
+			for i, gw := range referenceList(spec, "gateways") {
+				if t.isGateway(gw, namespace) {
+					paths = append(paths, fmt.Sprintf("spec.gateways[%d]", i))
+				}
+			}
+		}
+	case kubernetes.DestinationRules:
+		if t.isHost(spec["host"], namespace) {
+			paths = append(paths, "spec.host")
+		}
+	case kubernetes.Sidecars:
+		if t.host != nil {
+			for i, e := range referenceList(spec, "egress") {
+				egress, _ := e.(map[string]interface{})
+				for j, h := range referenceList(egress, "hosts") {
+					if t.isImported(h, namespace) {
+						paths = append(paths, fmt.Sprintf("spec.egress[%d].hosts[%d]", i, j))
+					}
+				}
+			}
+		}
+		if workloadSelector, ok := spec["workloadSelector"].(map[string]interface{}); ok && t.isSelected(workloadSelector["labels"]) {
+			paths = append(paths, "spec.workloadSelector.labels")
+		}
+	case kubernetes.EnvoyFilters:
+		if t.host != nil {
+			for i, p := range referenceList(spec, "configPatches") {
+				patch, _ := p.(map[string]interface{})
+				match, _ := patch["match"].(map[string]interface{})
+				if cluster, ok := match["cluster"].(map[string]interface{}); ok && t.isHost(cluster["service"], namespace) {
+					paths = append(paths, fmt.Sprintf("spec.configPatches[%d].match.cluster.service", i))
+				}
+				if routeConfiguration, ok := match["routeConfiguration"].(map[string]interface{}); ok {
+					if vhost, ok := routeConfiguration["vhost"].(map[string]interface{}); ok {
+						// Virtual hosts are named host:port
+						if vhostName, ok := vhost["name"].(string); ok && t.isHost(strings.Split(vhostName, ":")[0], namespace) {
+							paths = append(paths, fmt.Sprintf("spec.configPatches[%d].match.routeConfiguration.vhost.name", i))
+						}
+					}
+				}
+			}
+		}
+		if workloadSelector, ok := spec["workloadSelector"].(map[string]interface{}); ok && t.isSelected(workloadSelector["labels"]) {
+			paths = append(paths, "spec.workloadSelector.labels")
+		}
+	case kubernetes.Gateways:
+		if t.isSelected(spec["selector"]) {
+			paths = append(paths, "spec.selector")
+		}
+	case kubernetes.AuthorizationPolicies, kubernetes.PeerAuthentications:
+		selector, _ := spec["selector"].(map[string]interface{})
+		if matchLabels, _ := selector["matchLabels"].(map[string]interface{}); len(matchLabels) == 0 {
+			// Policies without selector apply to all the workloads of their namespace, or of the mesh from the root namespace
+			if t.labels != nil && (namespace == t.namespace || namespace == config.Get().IstioNamespace) {
+				paths = append(paths, "metadata.namespace")
+			}
+		} else if t.isSelected(matchLabels) {
+			paths = append(paths, "spec.selector.matchLabels")
+		}
+	}
+	return paths
+}
+
+// destinationReferences returns the paths of the host or the subset of a destination referencing the target
+func (t *referenceTarget) destinationReferences(path string, d interface{}, namespace string) []string {
+	destination, ok := d.(map[string]interface{})
+	if !ok || !t.isHost(destination["host"], namespace) {
+		return nil
+	}
+	if t.subsets == nil {
+		return []string{path + ".host"}
+	}
+	if subset, ok := destination["subset"].(string); ok && t.subsets[subset] {
+		return []string{path + ".subset"}
+	}
+	return nil
+}
+
+// isHost returns true when the host of an object of the namespace is the host of the target. Wildcard hosts don't
+// reference a particular host.
+func (t *referenceTarget) isHost(h interface{}, namespace string) bool {
+	hostName, ok := h.(string)
+	if t.host == nil || !ok || hostName == "" || strings.Contains(hostName, "*") {
+		return false
+	}
+	host := kubernetes.GetHost(hostName, namespace, "", t.clusterNamespaces)
+	if !t.host.CompleteInput {
+		return !host.CompleteInput && host.Service == t.host.Service
+	}
+	return host.CompleteInput && host.Service == t.host.Service && host.Namespace == t.host.Namespace
+}
+
+// isImported returns true when a host of the egress of a Sidecar, in namespace/host format, imports the target.
+// Importing all the hosts of the target namespace counts as a reference.
+func (t *referenceTarget) isImported(h interface{}, namespace string) bool {
+	egressHost, ok := h.(string)
+	if !ok {
+		return false
+	}
+	parts := strings.SplitN(egressHost, "/", 2)
+	if len(parts) != 2 {
+		return false
+	}
+	hostNamespace, hostName := parts[0], parts[1]
+	if hostNamespace == "." {
+		hostNamespace = namespace
+	}
+	if hostNamespace != "*" && hostNamespace != t.host.Namespace {
+		return false
+	}
+	if hostName == "*" {
+		return hostNamespace == t.host.Namespace
+	}
+	return t.isHost(hostName, t.host.Namespace)
+}
+
+// isGateway returns true when a gateway of a VirtualService of the namespace is the target Gateway
+func (t *referenceTarget) isGateway(g interface{}, namespace string) bool {
+	gateway, ok := g.(string)
+	if !ok || gateway == "mesh" {
+		return false
+	}
+	host := kubernetes.ParseGatewayAsHost(gateway, namespace, t.gateway.Cluster)
+	return host.Service == t.gateway.Service && host.Namespace == t.gateway.Namespace
+}
+
+// isSelected returns true when a non empty selector matches the labels of the target workload
+func (t *referenceTarget) isSelected(s interface{}) bool {
+	selector, ok := s.(map[string]interface{})
+	if t.labels == nil || !ok || len(selector) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if value, ok := v.(string); !ok || !t.labels.Has(k) || t.labels.Get(k) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// referenceList returns a list field of a generic object, nil when not found
+func referenceList(object map[string]interface{}, field string) []interface{} {
+	list, _ := object[field].([]interface{})
+	return list
+}
@@ -0,0 +1,182 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestGetServiceReferences(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockReferencingObjects()
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}}, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	references, err := layer.IstioConfig.GetIstioReferences("bookinfo", ServiceReferences, "reviews")
+	assert.NoError(err)
+	assert.Equal([]models.IstioReference{
+		{ObjectType: kubernetes.DestinationRules, Namespace: "bookinfo", Name: "reviews", Path: "spec.host"},
+		{ObjectType: kubernetes.EnvoyFilters, Namespace: "istio-system", Name: "reviews-filter", Path: "spec.configPatches[0].match.cluster.service"},
+		{ObjectType: kubernetes.Sidecars, Namespace: "bookinfo", Name: "default", Path: "spec.egress[0].hosts[0]"},
+		{ObjectType: kubernetes.Sidecars, Namespace: "istio-system", Name: "default", Path: "spec.egress[0].hosts[1]"},
+		{ObjectType: kubernetes.VirtualServices, Namespace: "bookinfo", Name: "reviews", Path: "spec.hosts[0]"},
+		{ObjectType: kubernetes.VirtualServices, Namespace: "bookinfo", Name: "reviews", Path: "spec.http[0].route[0].destination.host"},
+		{ObjectType: kubernetes.VirtualServices, Namespace: "bookinfo", Name: "reviews", Path: "spec.http[0].route[1].destination.host"},
+		{ObjectType: kubernetes.VirtualServices, Namespace: "istio-system", Name: "ingress", Path: "spec.http[0].route[0].destination.host"},
+	}, references.References)
+
+	_, err = layer.IstioConfig.GetIstioReferences("bookinfo", "deployments", "reviews")
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestGetGatewayAndDestinationRuleReferences(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockReferencingObjects()
+	k8s.On("GetIstioObject", "istio-system", kubernetes.Gateways, "bookinfo-gateway").Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.DestinationRules, "reviews").Return(fakeReferencingObject("bookinfo", "reviews", map[string]interface{}{
+		"host":    "reviews",
+		"subsets": []interface{}{map[string]interface{}{"name": "v2"}},
+	}), nil)
+	k8s.On("GetIstioObject", "istio-system", kubernetes.DestinationRules, "reviews").Return(fakeReferencingObject("istio-system", "reviews", map[string]interface{}{
+		"host":    "reviews.bookinfo",
+		"subsets": []interface{}{map[string]interface{}{"name": "v2"}},
+	}), nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	references, err := layer.IstioConfig.GetIstioReferences("istio-system", kubernetes.Gateways, "bookinfo-gateway")
+	assert.NoError(err)
+	assert.Equal([]models.IstioReference{
+		{ObjectType: kubernetes.VirtualServices, Namespace: "istio-system", Name: "ingress", Path: "spec.gateways[0]"},
+	}, references.References)
+
+	// Only the routes to the subsets of the DestinationRule reference it
+	references, err = layer.IstioConfig.GetIstioReferences("bookinfo", kubernetes.DestinationRules, "reviews")
+	assert.NoError(err)
+	assert.Equal([]models.IstioReference{
+		{ObjectType: kubernetes.VirtualServices, Namespace: "bookinfo", Name: "reviews", Path: "spec.http[0].route[1].destination.subset"},
+	}, references.References)
+
+	// Hosts in service.namespace format are resolved with the namespaces of the cluster
+	references, err = layer.IstioConfig.GetIstioReferences("istio-system", kubernetes.DestinationRules, "reviews")
+	assert.NoError(err)
+	assert.Equal([]models.IstioReference{
+		{ObjectType: kubernetes.VirtualServices, Namespace: "bookinfo", Name: "reviews", Path: "spec.http[0].route[1].destination.subset"},
+	}, references.References)
+}
+
+func TestReferencesFromSelectors(t *testing.T) {
+	assert := assert.New(t)
+
+	target := &referenceTarget{namespace: "bookinfo", labels: map[string]string{"app": "reviews", "version": "v1"}}
+	assert.Equal([]string{"spec.selector.matchLabels"}, target.referencesFrom(kubernetes.AuthorizationPolicies, fakeReferencingObject("bookinfo", "allow", map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "reviews"}},
+	})))
+	assert.Empty(target.referencesFrom(kubernetes.PeerAuthentications, fakeReferencingObject("bookinfo", "strict", map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "reviews", "version": "v2"}},
+	})))
+	// Policies without selector apply to the workloads of their namespace, or to all of them from the root namespace
+	assert.Equal([]string{"metadata.namespace"}, target.referencesFrom(kubernetes.PeerAuthentications, fakeReferencingObject("bookinfo", "default", map[string]interface{}{})))
+	assert.Equal([]string{"metadata.namespace"}, target.referencesFrom(kubernetes.AuthorizationPolicies, fakeReferencingObject("istio-system", "deny-all", map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{}},
+	})))
+	assert.Empty(target.referencesFrom(kubernetes.AuthorizationPolicies, fakeReferencingObject("ratings", "deny-all", map[string]interface{}{})))
+	assert.Equal([]string{"spec.workloadSelector.labels"}, target.referencesFrom(kubernetes.EnvoyFilters, fakeReferencingObject("bookinfo", "lua", map[string]interface{}{
+		"workloadSelector": map[string]interface{}{"labels": map[string]interface{}{"version": "v1"}},
+	})))
+}
+
+func TestReferenceNamespaces(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	clusterNamespaces := []string{"bookinfo", "istio-system", "travel"}
+	assert.Equal(clusterNamespaces, referenceNamespaces("bookinfo", ServiceReferences, kubernetes.VirtualServices, clusterNamespaces))
+	assert.Equal([]string{"bookinfo", "istio-system"}, referenceNamespaces("bookinfo", WorkloadReferences, kubernetes.Sidecars, clusterNamespaces))
+	assert.Equal([]string{"istio-system"}, referenceNamespaces("istio-system", WorkloadReferences, kubernetes.AuthorizationPolicies, clusterNamespaces))
+	// Gateways select the workloads of all the namespaces, i.e. the ingress gateway of istio-system from bookinfo
+	assert.Equal(clusterNamespaces, referenceNamespaces("istio-system", WorkloadReferences, kubernetes.Gateways, clusterNamespaces))
+}
+
+// mockReferencingObjects returns a mock with the Istio objects of the bookinfo and istio-system namespaces
+func mockReferencingObjects() *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", mock.AnythingOfType("string")).Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("GetNamespaces", mock.AnythingOfType("string")).Return([]core_v1.Namespace{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-system"}},
+	}, nil)
+
+	objects := map[string]map[string][]kubernetes.IstioObject{
+		"bookinfo": {
+			kubernetes.VirtualServices: {fakeReferencingObject("bookinfo", "reviews", map[string]interface{}{
+				"hosts": []interface{}{"reviews"},
+				"http": []interface{}{map[string]interface{}{"route": []interface{}{
+					map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "v1"}},
+					map[string]interface{}{"destination": map[string]interface{}{"host": "reviews.bookinfo.svc.cluster.local", "subset": "v2"}},
+					map[string]interface{}{"destination": map[string]interface{}{"host": "ratings"}},
+				}}},
+			})},
+			kubernetes.DestinationRules: {
+				fakeReferencingObject("bookinfo", "reviews", map[string]interface{}{"host": "reviews"}),
+				fakeReferencingObject("bookinfo", "all", map[string]interface{}{"host": "*.bookinfo.svc.cluster.local"}),
+			},
+			kubernetes.Sidecars: {fakeReferencingObject("bookinfo", "default", map[string]interface{}{
+				"egress": []interface{}{map[string]interface{}{"hosts": []interface{}{"./*", "istio-system/*"}}},
+			})},
+		},
+		"istio-system": {
+			kubernetes.VirtualServices: {fakeReferencingObject("istio-system", "ingress", map[string]interface{}{
+				"hosts":    []interface{}{"*"},
+				"gateways": []interface{}{"bookinfo-gateway", "mesh"},
+				"http": []interface{}{map[string]interface{}{"route": []interface{}{
+					map[string]interface{}{"destination": map[string]interface{}{"host": "reviews.bookinfo", "subset": "v3"}},
+				}}},
+			})},
+			kubernetes.Sidecars: {fakeReferencingObject("istio-system", "default", map[string]interface{}{
+				"egress": []interface{}{map[string]interface{}{"hosts": []interface{}{"*/ratings.bookinfo.svc.cluster.local", "*/reviews.bookinfo.svc.cluster.local"}}},
+			})},
+			kubernetes.EnvoyFilters: {fakeReferencingObject("istio-system", "reviews-filter", map[string]interface{}{
+				"configPatches": []interface{}{map[string]interface{}{
+					"match": map[string]interface{}{"cluster": map[string]interface{}{"service": "reviews.bookinfo.svc.cluster.local"}},
+				}},
+			})},
+		},
+	}
+	for _, ns := range []string{"bookinfo", "istio-system"} {
+		for _, objectType := range []string{kubernetes.VirtualServices, kubernetes.DestinationRules, kubernetes.Sidecars, kubernetes.EnvoyFilters} {
+			list := objects[ns][objectType]
+			if list == nil {
+				list = []kubernetes.IstioObject{}
+			}
+			k8s.On("GetIstioObjects", ns, objectType, "").Return(list, nil)
+		}
+	}
+	return k8s
+}
+
+func fakeReferencingObject(namespace, name string, spec map[string]interface{}) kubernetes.IstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       spec,
+	}
+}
@@ -0,0 +1,361 @@
+package business
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/destinationrules"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// minTrafficLookback is the lookback under which the lack of traffic is considered a weak evidence of unused config
+const minTrafficLookback = 24 * time.Hour
+
+// destinationTraffic is the set of destination hosts with traffic, with the destination versions seen for each host
+type destinationTraffic map[string]map[string]bool
+
+// GetUnusedIstioConfig returns the Istio objects of the namespace, or the parts of them, that have no effect.
+// Objects pointing to, or selecting, nothing in the registry are found with the checkers, with high confidence.
+// VirtualServices, ServiceEntries and DestinationRule subsets with no traffic during the lookback duration are found
+// with the telemetry: the confidence is lower when the data range, the lookback limited by the Prometheus retention,
+// is short or the object is newer than the data range.
+func (in *IstioValidationsService) GetUnusedIstioConfig(namespace, lookback string, queryTime time.Time) (models.UnusedIstioConfigReport, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetUnusedIstioConfig")
+	defer promtimer.ObserveNow(&err)
+
+	report := models.UnusedIstioConfigReport{Namespace: namespace, Lookback: lookback, Items: []models.UnusedIstioConfig{}}
+
+	var lookbackDuration model.Duration
+	if lookbackDuration, err = model.ParseDuration(lookback); err != nil {
+		return report, err
+	}
+	if in.prom == nil {
+		err = errors.New("prometheus is required to find the Istio config with no traffic")
+		return report, err
+	}
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return report, err
+	}
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	var istioDetails kubernetes.IstioDetails
+	var namespaces models.Namespaces
+	var workloads models.WorkloadList
+	var services []core_v1.Service
+	var rbacDetails kubernetes.RBACDetails
+
+	wg.Add(5)
+	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
+	go in.fetchNamespaces(&namespaces, errChan, &wg)
+	go in.fetchWorkloads(&workloads, namespace, errChan, &wg)
+	go in.fetchServices(&services, namespace, errChan, &wg)
+	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
+	wg.Wait()
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			err = e
+			return report, err
+		}
+	}
+
+	var peerAuthentications []kubernetes.IstioObject
+	if peerAuthentications, err = in.getPeerAuthentications(namespace); err != nil {
+		return report, err
+	}
+
+	unused := unusedConfigFinder{
+		namespace:      namespace,
+		namespaces:     namespaces.GetNames(),
+		serviceEntries: kubernetes.ServiceEntryHostnames(istioDetails.ServiceEntries),
+	}
+	if unused.traffic, err = getDestinationTraffic(in, unused.trafficNamespaces(istioDetails), lookback, queryTime); err != nil {
+		return report, err
+	}
+	// Traffic older than the retention is not found, whatever the lookback
+	dataRange := time.Duration(lookbackDuration)
+	if retention, ok := getPrometheusRetention(in); ok && retention < dataRange {
+		dataRange = retention
+	}
+	unused.lookbackStart = queryTime.Add(-dataRange)
+	unused.shortLookback = dataRange < minTrafficLookback
+
+	for _, dr := range istioDetails.DestinationRules {
+		unused.destinationRule(dr, namespaces, workloads, services)
+	}
+	for _, vs := range istioDetails.VirtualServices {
+		unused.virtualService(vs)
+	}
+	for _, se := range istioDetails.ServiceEntries {
+		unused.serviceEntry(se)
+	}
+	for _, ap := range rbacDetails.AuthorizationPolicies {
+		unused.selector(kubernetes.AuthorizationPolicies, common.SelectorNoWorkloadFoundChecker(kubernetes.AuthorizationPolicies, ap, workloads))
+	}
+	for _, pa := range peerAuthentications {
+		unused.selector(kubernetes.PeerAuthentications, common.SelectorNoWorkloadFoundChecker(kubernetes.PeerAuthentications, pa, workloads))
+	}
+	for _, ra := range istioDetails.RequestAuthentications {
+		unused.selector(kubernetes.RequestAuthentications, common.SelectorNoWorkloadFoundChecker(kubernetes.RequestAuthentications, ra, workloads))
+	}
+	for _, sc := range istioDetails.Sidecars {
+		unused.selector(kubernetes.Sidecars, common.WorkloadSelectorNoWorkloadFoundChecker(kubernetes.Sidecars, sc, workloads))
+	}
+
+	report.Items = unused.items
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		if report.Items[i].ObjectType != report.Items[j].ObjectType {
+			return report.Items[i].ObjectType < report.Items[j].ObjectType
+		}
+		return report.Items[i].Name < report.Items[j].Name
+	})
+	return report, nil
+}
+
+// getDestinationTraffic returns the destination hosts of the namespaces with traffic during the lookback, with the versions called
+func getDestinationTraffic(in *IstioValidationsService, namespaces []string, lookback string, queryTime time.Time) (destinationTraffic, error) {
+	rates, err := in.prom.GetDestinationTraffic(namespaces, lookback, queryTime)
+	if err != nil {
+		return nil, err
+	}
+
+	traffic := destinationTraffic{}
+	for _, sample := range rates {
+		host := string(sample.Metric["destination_service"])
+		if host == "" || host == "unknown" {
+			continue
+		}
+		if traffic[host] == nil {
+			traffic[host] = map[string]bool{}
+		}
+		traffic[host][string(sample.Metric["destination_version"])] = true
+	}
+	return traffic, nil
+}
+
+// getPrometheusRetention returns the retention of the Prometheus TSDB, when set in its flags
+func getPrometheusRetention(in *IstioValidationsService) (time.Duration, bool) {
+	flags, err := in.prom.GetFlags()
+	if err != nil {
+		log.Debugf("Prometheus retention not found, the lookback is assumed to be retained: %v", err)
+		return 0, false
+	}
+	// storage.tsdb.retention is deprecated by storage.tsdb.retention.time, both are 0s when not set
+	for _, flag := range []string{"storage.tsdb.retention.time", "storage.tsdb.retention"} {
+		if retention, err := model.ParseDuration(flags[flag]); err == nil && retention > 0 {
+			return time.Duration(retention), true
+		}
+	}
+	return 0, false
+}
+
+// unusedConfigFinder collects the unused config of a namespace
+type unusedConfigFinder struct {
+	namespace      string
+	namespaces     []string
+	traffic        destinationTraffic
+	lookbackStart  time.Time
+	shortLookback  bool
+	serviceEntries map[string][]string
+	items          []models.UnusedIstioConfig
+}
+
+func (in *unusedConfigFinder) add(objectType string, object kubernetes.IstioObject, path, reason, confidence string) {
+	in.items = append(in.items, models.UnusedIstioConfig{
+		ObjectType: objectType,
+		Namespace:  object.GetObjectMeta().Namespace,
+		Name:       object.GetObjectMeta().Name,
+		Path:       path,
+		Reason:     reason,
+		Confidence: confidence,
+	})
+}
+
+// trafficConfidence returns the confidence of a finding based on the lack of traffic for the object
+func (in *unusedConfigFinder) trafficConfidence(object kubernetes.IstioObject) string {
+	if in.shortLookback || object.GetObjectMeta().CreationTimestamp.Time.After(in.lookbackStart) {
+		return models.LowConfidence
+	}
+	return models.MediumConfidence
+}
+
+// trafficNamespaces returns the namespaces of the destination services to look up traffic to: the namespace, where the
+// ServiceEntries are reported, and the namespaces of the service hosts of the DestinationRules and VirtualServices
+func (in *unusedConfigFinder) trafficNamespaces(details kubernetes.IstioDetails) []string {
+	hosts := []string{}
+	for _, dr := range details.DestinationRules {
+		if host, ok := dr.GetSpec()["host"].(string); ok {
+			hosts = append(hosts, host)
+		}
+	}
+	for _, vs := range details.VirtualServices {
+		hosts = append(hosts, virtualServiceHosts(vs)...)
+	}
+
+	domain := config.Get().ExternalServices.Istio.IstioIdentityDomain
+	found := map[string]bool{in.namespace: true}
+	namespaces := []string{in.namespace}
+	for _, h := range hosts {
+		host := kubernetes.GetHost(h, in.namespace, domain, in.namespaces)
+		if host.CompleteInput && !found[host.Namespace] && !strings.Contains(host.Namespace, "*") {
+			found[host.Namespace] = true
+			namespaces = append(namespaces, host.Namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// host returns the FQDN of a host of an object of the namespace, as reported in the telemetry
+func (in *unusedConfigFinder) host(hostName string) string {
+	domain := config.Get().ExternalServices.Istio.IstioIdentityDomain
+	return kubernetes.GetHost(hostName, in.namespace, domain, in.namespaces).String()
+}
+
+// hasTraffic returns true if the host received traffic. Wildcard hosts match the traffic to any host of their domain.
+func (in *unusedConfigFinder) hasTraffic(host string) bool {
+	if strings.HasPrefix(host, "*") {
+		suffix := strings.TrimPrefix(host, "*")
+		for h := range in.traffic {
+			if strings.HasSuffix(h, suffix) {
+				return true
+			}
+		}
+		return false
+	}
+	return in.traffic[host] != nil
+}
+
+// destinationRule finds DestinationRules whose host matches no service and subsets with no workloads or no traffic
+func (in *unusedConfigFinder) destinationRule(dr kubernetes.IstioObject, namespaces models.Namespaces, workloads models.WorkloadList, services []core_v1.Service) {
+	checker := destinationrules.NoDestinationChecker{
+		Namespace:       in.namespace,
+		Namespaces:      namespaces,
+		WorkloadList:    workloads,
+		DestinationRule: dr,
+		ServiceEntries:  in.serviceEntries,
+		Services:        services,
+	}
+	checks, _ := checker.Check()
+	flagged := map[string]bool{}
+	for _, check := range checks {
+		switch check.Message {
+		case models.CheckMessage("destinationrules.nodest.matchingregistry"):
+			in.add(kubernetes.DestinationRules, dr, check.Path, "host matches no service in the registry", models.HighConfidence)
+			// Subsets of a missing service are unused too
+			return
+		case models.CheckMessage("destinationrules.nodest.subsetlabels"):
+			flagged[check.Path] = true
+			in.add(kubernetes.DestinationRules, dr, check.Path, "subset labels match no workload", models.HighConfidence)
+		}
+	}
+
+	host, ok := dr.GetSpec()["host"].(string)
+	if !ok {
+		return
+	}
+	host = in.host(host)
+	subsets, _ := dr.GetSpec()["subsets"].([]interface{})
+	versionLabel := config.Get().IstioLabels.VersionLabelName
+	for i, s := range subsets {
+		path := "spec/subsets[" + strconv.Itoa(i) + "]"
+		subset, _ := s.(map[string]interface{})
+		subsetLabels, _ := subset["labels"].(map[string]interface{})
+		// Only subsets by version can be told apart in the telemetry
+		version, ok := subsetLabels[versionLabel].(string)
+		if !ok || flagged[path] || strings.HasPrefix(host, "*") {
+			continue
+		}
+		if !in.traffic[host][version] {
+			in.add(kubernetes.DestinationRules, dr, path, "subset never routed to", in.trafficConfidence(dr))
+		}
+	}
+}
+
+// virtualService finds VirtualServices with no traffic to their hosts nor to their route destinations
+func (in *unusedConfigFinder) virtualService(vs kubernetes.IstioObject) {
+	hosts := []string{}
+	for _, host := range virtualServiceHosts(vs) {
+		hosts = append(hosts, in.host(host))
+	}
+	in.noTraffic(kubernetes.VirtualServices, vs, hosts, "no traffic to the hosts nor to the route destinations")
+}
+
+// virtualServiceHosts returns the hosts of a VirtualService and of its route destinations, as written
+func virtualServiceHosts(vs kubernetes.IstioObject) []string {
+	hosts := []string{}
+	if vsHosts, ok := vs.GetSpec()["hosts"].([]interface{}); ok {
+		for _, h := range vsHosts {
+			if host, ok := h.(string); ok {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		routes, _ := vs.GetSpec()[protocol].([]interface{})
+		for _, r := range routes {
+			route, _ := r.(map[string]interface{})
+			destinations, _ := route["route"].([]interface{})
+			for _, d := range destinations {
+				destination, _ := d.(map[string]interface{})
+				if target, ok := destination["destination"].(map[string]interface{}); ok {
+					if host, ok := target["host"].(string); ok {
+						hosts = append(hosts, host)
+					}
+				}
+			}
+		}
+	}
+	return hosts
+}
+
+// serviceEntry finds ServiceEntries with no traffic to their hosts
+func (in *unusedConfigFinder) serviceEntry(se kubernetes.IstioObject) {
+	hosts := []string{}
+	if seHosts, ok := se.GetSpec()["hosts"].([]interface{}); ok {
+		for _, h := range seHosts {
+			if host, ok := h.(string); ok {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	in.noTraffic(kubernetes.ServiceEntries, se, hosts, "no traffic to the hosts")
+}
+
+func (in *unusedConfigFinder) noTraffic(objectType string, object kubernetes.IstioObject, hosts []string, reason string) {
+	if len(hosts) == 0 {
+		return
+	}
+	for _, host := range hosts {
+		if in.hasTraffic(host) {
+			return
+		}
+	}
+	in.add(objectType, object, "spec/hosts", reason, in.trafficConfidence(object))
+}
+
+// selector finds objects whose selector matches no workload
+func (in *unusedConfigFinder) selector(objectType string, checker common.GenericNoWorkloadFoundChecker) {
+	checks, _ := checker.Check()
+	for _, check := range checks {
+		in.add(objectType, checker.Subject, check.Path, "selector matches no workload", models.HighConfidence)
+	}
+}
@@ -0,0 +1,114 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestGetUnusedIstioConfig(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	queryTime := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetDestinationTraffic", []string{"test"}, "7d", queryTime).Return(model.Vector{
+		&model.Sample{Metric: model.Metric{"destination_service": "customer.test.svc.cluster.local", "destination_version": "v1"}},
+	}, nil)
+	prom.On("GetDestinationTraffic", []string{"test"}, "1h", queryTime).Return(model.Vector{}, nil)
+	prom.On("GetFlags").Return(prom_v1.FlagsResult{"storage.tsdb.retention.time": "15d"}, nil).Times(2)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+	vs.prom = prom
+
+	report, err := vs.GetUnusedIstioConfig("test", "7d", queryTime)
+	assert.NoError(err)
+	assert.Equal("7d", report.Lookback)
+	assert.Equal([]models.UnusedIstioConfig{
+		{ObjectType: kubernetes.DestinationRules, Namespace: "test", Name: "product-dr", Path: "spec/subsets[0]", Reason: "subset labels match no workload", Confidence: models.HighConfidence},
+		{ObjectType: kubernetes.VirtualServices, Namespace: "test", Name: "product-vs", Path: "spec/hosts", Reason: "no traffic to the hosts nor to the route destinations", Confidence: models.MediumConfidence},
+	}, report.Items)
+
+	// A short lookback is a weak evidence of unused config
+	report, err = vs.GetUnusedIstioConfig("test", "1h", queryTime)
+	assert.NoError(err)
+	assert.Len(report.Items, 2)
+	assert.Equal(models.LowConfidence, report.Items[1].Confidence)
+
+	// Traffic is only retained for a short time
+	prom.On("GetFlags").Return(prom_v1.FlagsResult{"storage.tsdb.retention.time": "0s", "storage.tsdb.retention": "12h"}, nil)
+	report, err = vs.GetUnusedIstioConfig("test", "7d", queryTime)
+	assert.NoError(err)
+	assert.Len(report.Items, 2)
+	assert.Equal(models.LowConfidence, report.Items[1].Confidence)
+
+	_, err = vs.GetUnusedIstioConfig("test", "a week", queryTime)
+	assert.Error(err)
+}
+
+func TestUnusedConfigTrafficNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	unused := unusedConfigFinder{namespace: "bookinfo", namespaces: []string{"bookinfo", "ratings"}}
+	assert.Equal([]string{"bookinfo", "ratings", "reviews"}, unused.trafficNamespaces(kubernetes.IstioDetails{
+		DestinationRules: []kubernetes.IstioObject{
+			data.CreateEmptyDestinationRule("bookinfo", "details", "details"),
+			data.CreateEmptyDestinationRule("bookinfo", "ratings", "ratings.ratings"),
+			data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews.reviews.svc.cluster.local"),
+			data.CreateEmptyDestinationRule("bookinfo", "github", "api.github.com"),
+		},
+	}))
+}
+
+func TestUnusedConfigFinder(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	unused := unusedConfigFinder{
+		namespace:     "bookinfo",
+		namespaces:    []string{"bookinfo"},
+		lookbackStart: time.Date(2020, 5, 25, 10, 0, 0, 0, time.UTC),
+		traffic: destinationTraffic{
+			"reviews.bookinfo.svc.cluster.local": {"v1": true},
+			"api.github.com":                     {"unknown": true},
+		},
+	}
+
+	// Only the subsets by version without traffic are reported
+	dr := data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews")
+	dr = data.AddSubsetToDestinationRule(data.CreateSubset("v1", "v1"), dr)
+	dr = data.AddSubsetToDestinationRule(data.CreateSubset("v2", "v2"), dr)
+	dr = data.AddSubsetToDestinationRule(map[string]interface{}{"name": "canary", "labels": map[string]interface{}{"track": "canary"}}, dr)
+	unused.destinationRule(dr, models.Namespaces{{Name: "bookinfo"}}, models.WorkloadList{Workloads: []models.WorkloadListItem{
+		{Name: "reviews-v1", Labels: map[string]string{"app": "reviews", "version": "v1"}},
+		{Name: "reviews-v2", Labels: map[string]string{"app": "reviews", "version": "v2"}},
+		{Name: "reviews-canary", Labels: map[string]string{"app": "reviews", "track": "canary"}},
+	}}, []core_v1.Service{{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec:       core_v1.ServiceSpec{Selector: map[string]string{"app": "reviews"}},
+	}})
+
+	// Wildcard hosts match the traffic of their domain
+	unused.serviceEntry(data.CreateEmptyMeshExternalServiceEntry("github", "bookinfo", []string{"*.github.com"}))
+	unused.serviceEntry(data.CreateEmptyMeshExternalServiceEntry("google", "bookinfo", []string{"www.google.com"}))
+
+	assert.Equal([]models.UnusedIstioConfig{
+		{ObjectType: kubernetes.DestinationRules, Namespace: "bookinfo", Name: "reviews", Path: "spec/subsets[1]", Reason: "subset never routed to", Confidence: models.MediumConfidence},
+		{ObjectType: kubernetes.ServiceEntries, Namespace: "bookinfo", Name: "google", Path: "spec/hosts", Reason: "no traffic to the hosts", Confidence: models.MediumConfidence},
+	}, unused.items)
+}
@@ -2,22 +2,28 @@ package business
 
 import (
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/business/checkers"
+	"github.com/kiali/kiali/business/checkers/custom"
+	"github.com/kiali/kiali/business/checkers/sidecars"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
 type IstioValidationsService struct {
 	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
 	businessLayer *Layer
 }
 
@@ -86,7 +92,8 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 		}
 	}
 
-	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces)
+	outboundTraffic := in.getSidecarsOutboundTraffic(namespace, istioDetails.Sidecars)
+	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, outboundTraffic)
 
 	if service != "" {
 		objectCheckers = append(objectCheckers, in.getServiceCheckers(namespace, services, deployments, pods)...)
@@ -94,6 +101,7 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 
 	// Get group validations for same kind istio objects
 	validations := runObjectCheckers(objectCheckers)
+	suppressValidations(validations, newValidationAnnotations(istioDetails, services, gatewaysPerNamespace, mtlsDetails, rbacDetails))
 	if service != "" {
 		validations = validations.FilterBySingleType("service", service)
 	}
@@ -101,13 +109,94 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	return validations, nil
 }
 
+// namespaceValidationsDetails groups the namespace-local objects needed to validate a namespace
+type namespaceValidationsDetails struct {
+	istioDetails        kubernetes.IstioDetails
+	services            []core_v1.Service
+	rbacDetails         kubernetes.RBACDetails
+	peerAuthentications []kubernetes.IstioObject
+}
+
+// GetMeshValidations returns the validations of all the namespaces accessible by the user, grouped by namespace.
+// It validates the whole mesh in a single pass, so the data shared between namespaces is fetched only once.
+func (in *IstioValidationsService) GetMeshValidations() (models.NamespaceValidations, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetMeshValidations")
+	defer promtimer.ObserveNow(&err)
+
+	var namespaces models.Namespaces
+	if namespaces, err = in.businessLayer.Namespace.GetNamespaces(); err != nil {
+		return nil, err
+	}
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	var workloadsPerNamespace map[string]models.WorkloadList
+	var gatewaysPerNamespace [][]kubernetes.IstioObject
+	var mtlsDetails kubernetes.MTLSDetails
+	details := make([]namespaceValidationsDetails, len(namespaces))
+
+	wg.Add(3 + 4*len(namespaces))
+	go in.fetchAllWorkloads(&workloadsPerNamespace, errChan, &wg)
+	go in.fetchGatewaysPerNamespace(&gatewaysPerNamespace, errChan, &wg)
+	// Non-local mTLS configs are shared by all namespaces, only the namespace PeerAuthentications are replaced below
+	go in.fetchNonLocalmTLSConfigs(&mtlsDetails, config.Get().IstioNamespace, errChan, &wg)
+	for i, ns := range namespaces {
+		go in.fetchDetails(&details[i].istioDetails, ns.Name, errChan, &wg)
+		go in.fetchServices(&details[i].services, ns.Name, errChan, &wg)
+		go in.fetchAuthorizationDetails(&details[i].rbacDetails, ns.Name, errChan, &wg)
+		go fetchIstioObjects(&details[i].peerAuthentications, ns.Name, in.getPeerAuthentications, &wg, errChan)
+	}
+
+	wg.Wait()
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			err = e
+			return nil, err
+		}
+	}
+
+	// The traffic of the namespaces is queried in parallel, when the Sidecar traffic validations apply
+	outboundTraffic := make([][]sidecars.OutboundTraffic, len(namespaces))
+	wg.Add(len(namespaces))
+	for i, ns := range namespaces {
+		go func(i int, namespace string) {
+			defer wg.Done()
+			outboundTraffic[i] = in.getSidecarsOutboundTraffic(namespace, details[i].istioDetails.Sidecars)
+		}(i, ns.Name)
+	}
+	wg.Wait()
+
+	namespaceValidations := models.NamespaceValidations{}
+	for i, ns := range namespaces {
+		nsMtlsDetails := mtlsDetails
+		nsMtlsDetails.PeerAuthentications = details[i].peerAuthentications
+
+		objectCheckers := in.getAllObjectCheckers(ns.Name, details[i].istioDetails, details[i].services, workloadsPerNamespace, workloadsPerNamespace[ns.Name], gatewaysPerNamespace, nsMtlsDetails, details[i].rbacDetails, namespaces, outboundTraffic[i])
+		validations := runObjectCheckers(objectCheckers)
+		suppressValidations(validations, newValidationAnnotations(details[i].istioDetails, details[i].services, gatewaysPerNamespace, nsMtlsDetails, details[i].rbacDetails))
+		namespaceValidations[ns.Name] = validations.FilterByNamespace(ns.Name)
+	}
+
+	return namespaceValidations, nil
+}
+
+func (in *IstioValidationsService) getPeerAuthentications(namespace string) ([]kubernetes.IstioObject, error) {
+	if IsResourceCached(namespace, kubernetes.PeerAuthentications) {
+		return kialiCache.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
+	}
+	return in.k8s.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
+}
+
 func (in *IstioValidationsService) getServiceCheckers(namespace string, services []core_v1.Service, deployments []apps_v1.Deployment, pods []core_v1.Pod) []ObjectChecker {
 	return []ObjectChecker{
 		checkers.ServiceChecker{Services: services, Deployments: deployments, Pods: pods},
 	}
 }
 
-func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, services []core_v1.Service, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace) []ObjectChecker {
+func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, services []core_v1.Service, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace, outboundTraffic []sidecars.OutboundTraffic) []ObjectChecker {
 	return []ObjectChecker{
 		checkers.NoServiceChecker{Namespace: namespace, Namespaces: namespaces, IstioDetails: &istioDetails, Services: services, WorkloadList: workloads, GatewaysPerNamespace: gatewaysPerNamespace, AuthorizationDetails: &rbacDetails},
 		checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, VirtualServices: istioDetails.VirtualServices},
@@ -116,11 +205,90 @@ func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioD
 		checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads},
 		checkers.ServiceEntryChecker{ServiceEntries: istioDetails.ServiceEntries},
 		checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, Namespace: namespace, Namespaces: namespaces, Services: services, ServiceEntries: istioDetails.ServiceEntries, WorkloadList: workloads, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices},
-		checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: namespaces, WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries},
+		checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: namespaces, WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries, OutboundTraffic: outboundTraffic},
 		checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: workloads},
+		in.getCustomRulesChecker(istioDetails, mtlsDetails, rbacDetails),
 	}
 }
 
+// getCustomRulesChecker returns the checker of the custom rules defined in the Validations config
+func (in *IstioValidationsService) getCustomRulesChecker(istioDetails kubernetes.IstioDetails, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails) ObjectChecker {
+	return checkers.CustomRulesChecker{
+		Rules: custom.LoadedRules(),
+		IstioObjects: map[string][]kubernetes.IstioObject{
+			kubernetes.VirtualServices:        istioDetails.VirtualServices,
+			kubernetes.DestinationRules:       istioDetails.DestinationRules,
+			kubernetes.ServiceEntries:         istioDetails.ServiceEntries,
+			kubernetes.Gateways:               istioDetails.Gateways,
+			kubernetes.Sidecars:               istioDetails.Sidecars,
+			kubernetes.RequestAuthentications: istioDetails.RequestAuthentications,
+			kubernetes.PeerAuthentications:    mtlsDetails.PeerAuthentications,
+			kubernetes.AuthorizationPolicies:  rbacDetails.AuthorizationPolicies,
+		},
+	}
+}
+
+// getSidecarsOutboundTraffic returns the outbound traffic of the namespace workloads, used to validate the egress
+// hosts of the Sidecars. It returns nil, skipping those validations, when they are disabled or not applicable.
+func (in *IstioValidationsService) getSidecarsOutboundTraffic(namespace string, sidecarList []kubernetes.IstioObject) []sidecars.OutboundTraffic {
+	conf := config.Get().Validations.SidecarTraffic
+	if !conf.Enabled || in.prom == nil {
+		return nil
+	}
+	withEgress := false
+	for _, sc := range sidecarList {
+		if _, found := sc.GetSpec()["egress"]; found {
+			withEgress = true
+			break
+		}
+	}
+	if !withEgress {
+		return nil
+	}
+
+	traffic, err := getOutboundTraffic(in.prom, namespace, "", conf.Lookback, time.Now())
+	if err != nil {
+		// Telemetry is not required to validate the config, the rest of validations are still returned
+		log.Errorf("Skipping Sidecar traffic validations for namespace [%s]: %v", namespace, err)
+		return nil
+	}
+	return traffic
+}
+
+// getOutboundTraffic returns the destination services called by the workloads of the namespace during the lookback duration.
+// The traffic is limited to a single workload when it is not empty.
+func getOutboundTraffic(prom prometheus.ClientInterface, namespace, workload, lookback string, queryTime time.Time) ([]sidecars.OutboundTraffic, error) {
+	rates, err := prom.GetOutboundTraffic(namespace, workload, lookback, queryTime)
+	if err != nil {
+		return nil, err
+	}
+
+	traffic := make([]sidecars.OutboundTraffic, 0, len(rates))
+	found := map[sidecars.OutboundTraffic]bool{}
+	for _, sample := range rates {
+		if string(sample.Metric["source_workload_namespace"]) != namespace {
+			continue
+		}
+		t := sidecars.OutboundTraffic{
+			SourceWorkload:   string(sample.Metric["source_workload"]),
+			Service:          string(sample.Metric["destination_service"]),
+			ServiceName:      string(sample.Metric["destination_service_name"]),
+			ServiceNamespace: string(sample.Metric["destination_service_namespace"]),
+		}
+		// Traffic out of the service registry is not affected by the Sidecar egress hosts
+		switch t.Service {
+		case "", "unknown", "PassthroughCluster", "BlackHoleCluster":
+			continue
+		}
+		if t.SourceWorkload == "" || t.SourceWorkload == "unknown" || found[t] {
+			continue
+		}
+		found[t] = true
+		traffic = append(traffic, t)
+	}
+	return traffic, nil
+}
+
 func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, objectType string, object string) (models.IstioValidations, error) {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetIstioObjectValidations")
@@ -176,7 +344,8 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 		objectCheckers = []ObjectChecker{serviceEntryChecker}
 	case kubernetes.Sidecars:
 		sidecarsChecker := checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: namespaces,
-			WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries}
+			WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries,
+			OutboundTraffic: in.getSidecarsOutboundTraffic(namespace, istioDetails.Sidecars)}
 		objectCheckers = []ObjectChecker{sidecarsChecker}
 	case kubernetes.AuthorizationPolicies:
 		authPoliciesChecker := checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies,
@@ -209,8 +378,12 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 	if objectCheckers == nil {
 		return models.IstioValidations{}, err
 	}
+	objectCheckers = append(objectCheckers, in.getCustomRulesChecker(istioDetails, mtlsDetails, rbacDetails))
 
-	return runObjectCheckers(objectCheckers).FilterByKey(models.ObjectTypeSingular[objectType], object), nil
+	validations := runObjectCheckers(objectCheckers)
+	suppressValidations(validations, newValidationAnnotations(istioDetails, services, gatewaysPerNamespace, mtlsDetails, rbacDetails))
+
+	return validations.FilterByKey(models.ObjectTypeSingular[objectType], object), nil
 }
 
 func runObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
@@ -224,6 +397,64 @@ func runObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
 	return objectTypeValidations
 }
 
+// validationAnnotations holds the annotations of the validated objects indexed by its validation key
+type validationAnnotations map[models.IstioValidationKey]map[string]string
+
+func newValidationAnnotations(istioDetails kubernetes.IstioDetails, services []core_v1.Service, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails) validationAnnotations {
+	annotations := validationAnnotations{}
+	annotations.addIstioObjects(checkers.VirtualCheckerType, istioDetails.VirtualServices)
+	annotations.addIstioObjects(checkers.DestinationRuleCheckerType, istioDetails.DestinationRules)
+	annotations.addIstioObjects(checkers.DestinationRuleCheckerType, mtlsDetails.DestinationRules)
+	annotations.addIstioObjects(checkers.ServiceEntryCheckerType, istioDetails.ServiceEntries)
+	annotations.addIstioObjects(checkers.GatewayCheckerType, istioDetails.Gateways)
+	for _, gateways := range gatewaysPerNamespace {
+		annotations.addIstioObjects(checkers.GatewayCheckerType, gateways)
+	}
+	annotations.addIstioObjects(checkers.SidecarCheckerType, istioDetails.Sidecars)
+	annotations.addIstioObjects(checkers.RequestAuthenticationCheckerType, istioDetails.RequestAuthentications)
+	annotations.addIstioObjects(checkers.PeerAuthenticationCheckerType, mtlsDetails.PeerAuthentications)
+	annotations.addIstioObjects(checkers.PeerAuthenticationCheckerType, mtlsDetails.MeshPeerAuthentications)
+	annotations.addIstioObjects(checkers.AuthorizationPolicyCheckerType, rbacDetails.AuthorizationPolicies)
+	for _, s := range services {
+		if len(s.Annotations) > 0 {
+			annotations[models.BuildKey(checkers.ServiceCheckerType, s.Name, s.Namespace)] = s.Annotations
+		}
+	}
+	return annotations
+}
+
+func (va validationAnnotations) addIstioObjects(objectType string, objects []kubernetes.IstioObject) {
+	for _, o := range objects {
+		meta := o.GetObjectMeta()
+		if len(meta.Annotations) > 0 {
+			va[models.BuildKey(objectType, meta.Name, meta.Namespace)] = meta.Annotations
+		}
+	}
+}
+
+// suppressValidations moves to the suppressed list the checks whose codes are suppressed, either globally or per
+// namespace, in the Validations config or in the models.SuppressValidationsAnnotation of the validated object.
+func suppressValidations(validations models.IstioValidations, annotations validationAnnotations) {
+	conf := config.Get().Validations
+	for key, validation := range validations {
+		codes := map[string]bool{}
+		addSuppressedCodes(codes, conf.Suppress)
+		addSuppressedCodes(codes, conf.SuppressByNamespace[key.Namespace])
+		if value, found := annotations[key][models.SuppressValidationsAnnotation]; found {
+			addSuppressedCodes(codes, strings.Split(value, ","))
+		}
+		validation.SuppressChecks(codes)
+	}
+}
+
+func addSuppressedCodes(codes map[string]bool, values []string) {
+	for _, v := range values {
+		if code := strings.ToUpper(strings.TrimSpace(v)); code != "" {
+			codes[code] = true
+		}
+	}
+}
+
 // The following idea is used underneath: if errChan has at least one record, we'll effectively cancel the request (if scheduled in such order). On the other hand, if we can't
 // write to the buffered errChan, we just ignore the error as select does not block even if channel is full. This is because a single error is enough to cancel the whole request.
 
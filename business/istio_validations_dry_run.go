@@ -0,0 +1,328 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/business/checkers/sidecars"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// dryRunDetails holds the objects needed to validate a namespace, so they can be modified in memory
+type dryRunDetails struct {
+	istioDetails          kubernetes.IstioDetails
+	services              []core_v1.Service
+	namespaces            models.Namespaces
+	workloads             models.WorkloadList
+	workloadsPerNamespace map[string]models.WorkloadList
+	gatewaysPerNamespace  [][]kubernetes.IstioObject
+	mtlsDetails           kubernetes.MTLSDetails
+	rbacDetails           kubernetes.RBACDetails
+}
+
+// DryRunValidations validates a namespace as if the proposed change was applied, without applying it to the cluster.
+// It returns the checks introduced, resolved and unchanged by the change, including the checks of the objects of other
+// namespaces referencing an updated or deleted Gateway or DestinationRule.
+func (in *IstioValidationsService) DryRunValidations(namespace string, change models.IstioConfigChange) (models.DryRunValidations, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "DryRunValidations")
+	defer promtimer.ObserveNow(&err)
+
+	var validations models.DryRunValidations
+	validations, err = in.dryRunChanges(namespace, []models.IstioConfigChange{change})
+	return validations, err
+}
+
+// DryRunBatchValidations validates a namespace as if all the proposed changes were applied together.
+// It returns the checks introduced, resolved and unchanged by the whole batch.
+func (in *IstioValidationsService) DryRunBatchValidations(namespace string, changes []models.IstioConfigChange) (models.DryRunValidations, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "DryRunBatchValidations")
+	defer promtimer.ObserveNow(&err)
+
+	var validations models.DryRunValidations
+	validations, err = in.dryRunChanges(namespace, changes)
+	return validations, err
+}
+
+func (in *IstioValidationsService) dryRunChanges(namespace string, changes []models.IstioConfigChange) (models.DryRunValidations, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return models.DryRunValidations{}, err
+	}
+
+	current, err := in.fetchDryRunDetails(namespace)
+	if err != nil {
+		return models.DryRunValidations{}, err
+	}
+	changed := current
+	objects := make([]kubernetes.IstioObject, len(changes))
+	names := make([]string, len(changes))
+	for i, change := range changes {
+		if names[i], objects[i], err = in.applyChange(&changed, namespace, change); err != nil {
+			return models.DryRunValidations{}, err
+		}
+	}
+	validations := in.diffDryRunDetails(namespace, current, changed)
+
+	// Objects of other namespaces referencing the changed objects only see them through the lists holding the objects
+	// of all the namespaces
+	referencing, err := in.referencingNamespaces(namespace, changes)
+	if err != nil {
+		return models.DryRunValidations{}, err
+	}
+	for _, ns := range referencing {
+		current, err := in.fetchDryRunDetails(ns)
+		if err != nil {
+			return models.DryRunValidations{}, err
+		}
+		changed := current
+		for i, change := range changes {
+			for _, list := range changed.objectLists(namespace, change.ObjectType)[1:] {
+				*list = replaceIstioObject(*list, namespace, names[i], objects[i])
+			}
+		}
+		diff := in.diffDryRunDetails(ns, current, changed)
+		validations.Introduced.MergeValidations(diff.Introduced)
+		validations.Resolved.MergeValidations(diff.Resolved)
+		validations.Unchanged.MergeValidations(diff.Unchanged)
+	}
+	return validations, nil
+}
+
+// referencingNamespaces returns the other namespaces with objects referencing the updated or deleted Gateways and
+// DestinationRules, sorted.
+func (in *IstioValidationsService) referencingNamespaces(namespace string, changes []models.IstioConfigChange) ([]string, error) {
+	found := map[string]bool{namespace: true}
+	namespaces := []string{}
+	for _, change := range changes {
+		if change.ObjectType != kubernetes.Gateways && change.ObjectType != kubernetes.DestinationRules {
+			continue
+		}
+		if change.Operation != models.UpdateOperation && change.Operation != models.DeleteOperation {
+			continue
+		}
+		references, err := in.businessLayer.IstioConfig.GetIstioReferences(namespace, change.ObjectType, change.Name)
+		if errors.IsNotFound(err) {
+			// Created by a previous change of the batch
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, r := range references.References {
+			if !found[r.Namespace] {
+				found[r.Namespace] = true
+				namespaces = append(namespaces, r.Namespace)
+			}
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// fetchDryRunDetails fetches the objects needed to validate a namespace
+func (in *IstioValidationsService) fetchDryRunDetails(namespace string) (dryRunDetails, error) {
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	var details dryRunDetails
+	wg.Add(8)
+	go in.fetchDetails(&details.istioDetails, namespace, errChan, &wg)
+	go in.fetchNamespaces(&details.namespaces, errChan, &wg)
+	go in.fetchWorkloads(&details.workloads, namespace, errChan, &wg)
+	go in.fetchAllWorkloads(&details.workloadsPerNamespace, errChan, &wg)
+	go in.fetchGatewaysPerNamespace(&details.gatewaysPerNamespace, errChan, &wg)
+	go in.fetchNonLocalmTLSConfigs(&details.mtlsDetails, namespace, errChan, &wg)
+	go in.fetchAuthorizationDetails(&details.rbacDetails, namespace, errChan, &wg)
+	go in.fetchServices(&details.services, namespace, errChan, &wg)
+
+	wg.Wait()
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			return dryRunDetails{}, e
+		}
+	}
+	return details, nil
+}
+
+// diffDryRunDetails returns the checks of the namespace introduced, resolved and unchanged by the changed details
+func (in *IstioValidationsService) diffDryRunDetails(namespace string, current, changed dryRunDetails) models.DryRunValidations {
+	// Traffic is queried once, for the Sidecars before and after the changes
+	sidecarList := make([]kubernetes.IstioObject, 0, len(current.istioDetails.Sidecars)+len(changed.istioDetails.Sidecars))
+	sidecarList = append(sidecarList, current.istioDetails.Sidecars...)
+	sidecarList = append(sidecarList, changed.istioDetails.Sidecars...)
+	outboundTraffic := in.getSidecarsOutboundTraffic(namespace, sidecarList)
+	before := in.validateDryRunDetails(namespace, current, outboundTraffic)
+	after := in.validateDryRunDetails(namespace, changed, outboundTraffic)
+	return models.DiffValidations(before, after)
+}
+
+func (in *IstioValidationsService) validateDryRunDetails(namespace string, details dryRunDetails, outboundTraffic []sidecars.OutboundTraffic) models.IstioValidations {
+	objectCheckers := in.getAllObjectCheckers(namespace, details.istioDetails, details.services, details.workloadsPerNamespace, details.workloads, details.gatewaysPerNamespace, details.mtlsDetails, details.rbacDetails, details.namespaces, outboundTraffic)
+	validations := runObjectCheckers(objectCheckers)
+	suppressValidations(validations, newValidationAnnotations(details.istioDetails, details.services, details.gatewaysPerNamespace, details.mtlsDetails, details.rbacDetails))
+	return validations.FilterByNamespace(namespace)
+}
+
+// applyChange applies the change on the details and returns the name of the changed object and the object, nil when
+// deleted. Lists holding the changed object type are replaced, never modified, as they may be shared with the cache or
+// with the unchanged details.
+func (in *IstioValidationsService) applyChange(details *dryRunDetails, namespace string, change models.IstioConfigChange) (string, kubernetes.IstioObject, error) {
+	lists := details.objectLists(namespace, change.ObjectType)
+	if len(lists) == 0 {
+		return "", nil, errors.NewBadRequest(fmt.Sprintf("dry run not supported for object type: %s", change.ObjectType))
+	}
+	resource := schema.GroupResource{Group: GetIstioAPI(change.ObjectType), Resource: change.ObjectType}
+
+	var object kubernetes.IstioObject
+	name := change.Name
+	switch change.Operation {
+	case models.CreateOperation:
+		body, err := in.businessLayer.IstioConfig.ParseJsonForCreate(change.ObjectType, change.Object)
+		if err != nil {
+			return "", nil, errors.NewBadRequest(err.Error())
+		}
+		created := &kubernetes.GenericIstioObject{}
+		if err = json.Unmarshal([]byte(body), created); err != nil {
+			return "", nil, errors.NewBadRequest(err.Error())
+		}
+		if name == "" {
+			name = created.Name
+		}
+		if name == "" {
+			return "", nil, errors.NewBadRequest("name of the object to create is required")
+		}
+		if findIstioObject(*lists[0], namespace, name) != nil {
+			return "", nil, errors.NewAlreadyExists(resource, name)
+		}
+		created.Name = name
+		created.Namespace = namespace
+		object = created
+	case models.UpdateOperation, models.DeleteOperation:
+		existing := findIstioObject(*lists[0], namespace, name)
+		if existing == nil {
+			return "", nil, errors.NewNotFound(resource, name)
+		}
+		if change.Operation == models.UpdateOperation {
+			updated, err := patchIstioObject(existing, change.Object)
+			if err != nil {
+				return "", nil, errors.NewBadRequest(err.Error())
+			}
+			// Patches can't move the object
+			meta := updated.GetObjectMeta()
+			meta.Name = name
+			meta.Namespace = namespace
+			updated.SetObjectMeta(meta)
+			object = updated
+		}
+	default:
+		return "", nil, errors.NewBadRequest(fmt.Sprintf("operation not supported: %s", change.Operation))
+	}
+
+	for _, list := range lists {
+		*list = replaceIstioObject(*list, namespace, name, object)
+	}
+	return name, object, nil
+}
+
+// objectLists returns the lists of the details holding the objects of the given type and namespace.
+// The first one is always the list of the namespace objects.
+func (d *dryRunDetails) objectLists(namespace, objectType string) []*[]kubernetes.IstioObject {
+	switch objectType {
+	case kubernetes.VirtualServices:
+		return []*[]kubernetes.IstioObject{&d.istioDetails.VirtualServices}
+	case kubernetes.DestinationRules:
+		return []*[]kubernetes.IstioObject{&d.istioDetails.DestinationRules, &d.mtlsDetails.DestinationRules}
+	case kubernetes.ServiceEntries:
+		return []*[]kubernetes.IstioObject{&d.istioDetails.ServiceEntries}
+	case kubernetes.Sidecars:
+		return []*[]kubernetes.IstioObject{&d.istioDetails.Sidecars}
+	case kubernetes.RequestAuthentications:
+		return []*[]kubernetes.IstioObject{&d.istioDetails.RequestAuthentications}
+	case kubernetes.AuthorizationPolicies:
+		return []*[]kubernetes.IstioObject{&d.rbacDetails.AuthorizationPolicies}
+	case kubernetes.Gateways:
+		lists := []*[]kubernetes.IstioObject{&d.istioDetails.Gateways}
+		// The outer list is copied, so the namespace list can be replaced without modifying the original details.
+		// Gateways per namespace are fetched in the same order than namespaces.
+		d.gatewaysPerNamespace = append([][]kubernetes.IstioObject{}, d.gatewaysPerNamespace...)
+		for i, ns := range d.namespaces {
+			if ns.Name == namespace && i < len(d.gatewaysPerNamespace) {
+				lists = append(lists, &d.gatewaysPerNamespace[i])
+			}
+		}
+		return lists
+	case kubernetes.PeerAuthentications:
+		lists := []*[]kubernetes.IstioObject{&d.mtlsDetails.PeerAuthentications}
+		if namespace == config.Get().IstioNamespace {
+			lists = append(lists, &d.mtlsDetails.MeshPeerAuthentications)
+		}
+		return lists
+	}
+	return nil
+}
+
+func findIstioObject(objects []kubernetes.IstioObject, namespace, name string) kubernetes.IstioObject {
+	for _, o := range objects {
+		if o.GetObjectMeta().Name == name && o.GetObjectMeta().Namespace == namespace {
+			return o
+		}
+	}
+	return nil
+}
+
+// replaceIstioObject returns a copy of objects where the object with the given name is replaced by replacement.
+// A nil replacement removes the object and a replacement not found is appended.
+func replaceIstioObject(objects []kubernetes.IstioObject, namespace, name string, replacement kubernetes.IstioObject) []kubernetes.IstioObject {
+	replaced := make([]kubernetes.IstioObject, 0, len(objects)+1)
+	found := false
+	for _, o := range objects {
+		if o.GetObjectMeta().Name == name && o.GetObjectMeta().Namespace == namespace {
+			found = true
+			if replacement != nil {
+				replaced = append(replaced, replacement)
+			}
+		} else {
+			replaced = append(replaced, o)
+		}
+	}
+	if !found && replacement != nil {
+		replaced = append(replaced, replacement)
+	}
+	return replaced
+}
+
+// patchIstioObject applies a JSON Merge Patch on a copy of the object, as UpdateIstioObject does in the cluster
+func patchIstioObject(object kubernetes.IstioObject, jsonPatch []byte) (kubernetes.IstioObject, error) {
+	var patch interface{}
+	if err := json.Unmarshal(jsonPatch, &patch); err != nil {
+		return nil, err
+	}
+	var target interface{}
+	if b, err := json.Marshal(object); err != nil {
+		return nil, err
+	} else if err = json.Unmarshal(b, &target); err != nil {
+		return nil, err
+	}
+	patched, err := json.Marshal(util.MergePatch(target, patch))
+	if err != nil {
+		return nil, err
+	}
+	result := &kubernetes.GenericIstioObject{}
+	if err = json.Unmarshal(patched, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -1,21 +1,28 @@
 package business
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	osapps_v1 "github.com/openshift/api/apps/v1"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	apps_v1 "k8s.io/api/apps/v1"
 	batch_v1 "k8s.io/api/batch/v1"
 	batch_v1beta1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kiali/kiali/business/checkers/custom"
+	"github.com/kiali/kiali/business/checkers/sidecars"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
 	"github.com/kiali/kiali/tests/data"
 )
 
@@ -32,6 +39,129 @@ func TestGetNamespaceValidations(t *testing.T) {
 	assert.True(validations[models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test", Name: "product-vs"}].Valid)
 }
 
+func TestGetMeshValidations(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(),
+		[]string{"details", "product", "customer"}, fakePods())
+
+	validations, err := vs.GetMeshValidations()
+	assert.NoError(err)
+	assert.Len(validations, 2)
+	assert.Contains(validations, "test")
+	assert.Contains(validations, "test2")
+	assert.True(validations["test"][models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test", Name: "product-vs"}].Valid)
+	for key := range validations["test2"] {
+		assert.Equal("test2", key.Namespace)
+	}
+}
+
+func TestDryRunValidations(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(),
+		[]string{"details", "product", "customer"}, fakePods())
+	productDr := models.IstioValidationKey{ObjectType: "destinationrule", Namespace: "test", Name: "product-dr"}
+
+	// Removing the subset fixes the labels not found, but breaks the route of the VirtualService
+	diff, err := vs.DryRunValidations("test", models.IstioConfigChange{Operation: models.UpdateOperation, ObjectType: "destinationrules", Name: "product-dr", Object: []byte(`{"spec":{"subsets":null}}`)})
+	assert.NoError(err)
+	assert.Len(diff.Introduced, 1)
+	assert.Contains(diff.Introduced, models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test", Name: "product-vs"})
+	assert.Len(diff.Resolved, 1)
+	assert.Equal("KIA0203", diff.Resolved[productDr].Checks[0].Code)
+
+	diff, err = vs.DryRunValidations("test", models.IstioConfigChange{Operation: models.DeleteOperation, ObjectType: "destinationrules", Name: "product-dr"})
+	assert.NoError(err)
+	assert.Len(diff.Resolved, 1)
+	assert.Contains(diff.Resolved, productDr)
+
+	diff, err = vs.DryRunValidations("test", models.IstioConfigChange{Operation: models.CreateOperation, ObjectType: "destinationrules", Object: []byte(`{"metadata":{"name":"reviews-dr"},"spec":{"host":"nonexistent"}}`)})
+	assert.NoError(err)
+	assert.Len(diff.Introduced, 1)
+	reviewsDr := diff.Introduced[models.IstioValidationKey{ObjectType: "destinationrule", Namespace: "test", Name: "reviews-dr"}]
+	assert.NotNil(reviewsDr)
+	assert.False(reviewsDr.Valid)
+	assert.Equal("KIA0202", reviewsDr.Checks[0].Code)
+	assert.Contains(diff.Unchanged, productDr)
+
+	_, err = vs.DryRunValidations("test", models.IstioConfigChange{Operation: models.DeleteOperation, ObjectType: "destinationrules", Name: "unknown-dr"})
+	assert.True(errors.IsNotFound(err))
+
+	_, err = vs.DryRunValidations("test", models.IstioConfigChange{Operation: models.DeleteOperation, ObjectType: "envoyfilters", Name: "product-ef"})
+	assert.True(errors.IsBadRequest(err))
+
+	// The cluster objects are not modified
+	validations, err := vs.GetValidations("test", "")
+	assert.NoError(err)
+	assert.False(validations[productDr].Valid)
+}
+
+func TestDryRunValidationsReferencingNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	boundVs := data.CreateEmptyVirtualService("bound-vs", "test2", []string{"product"})
+	boundVs.GetSpec()["gateways"] = []interface{}{"test/first"}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObjects", "test", "gateways", "").Return(getGateway("first"), nil)
+	k8s.On("GetIstioObjects", "test2", "gateways", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", "test", "virtualservices", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", "test2", "virtualservices", "").Return([]kubernetes.IstioObject{boundVs}, nil)
+	k8s.On("GetIstioObject", "test", "gateways", "first").Return(getGateway("first")[0], nil)
+	vs := mockCombinedValidationServiceWith(k8s, fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+
+	// Deleting the Gateway breaks the VirtualService of the other namespace bound to it
+	diff, err := vs.DryRunValidations("test", models.IstioConfigChange{Operation: models.DeleteOperation, ObjectType: "gateways", Name: "first"})
+	assert.NoError(err)
+	boundVsKey := models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test2", Name: "bound-vs"}
+	assert.Contains(diff.Introduced, boundVsKey)
+	assert.Len(diff.Introduced[boundVsKey].Checks, 1)
+	assert.Equal("spec/gateways[0]", diff.Introduced[boundVsKey].Checks[0].Path)
+
+	// Updating the labels of the Gateway keeps it
+	diff, err = vs.DryRunValidations("test", models.IstioConfigChange{Operation: models.UpdateOperation, ObjectType: "gateways", Name: "first", Object: []byte(`{"metadata":{"labels":{"version":"v2"}}}`)})
+	assert.NoError(err)
+	assert.NotContains(diff.Introduced, boundVsKey)
+}
+
+func TestGetOutboundTraffic(t *testing.T) {
+	assert := assert.New(t)
+
+	sample := func(srcWorkload, srcNamespace, service string) *model.Sample {
+		return &model.Sample{Metric: model.Metric{
+			"source_workload":               model.LabelValue(srcWorkload),
+			"source_workload_namespace":     model.LabelValue(srcNamespace),
+			"destination_service":           model.LabelValue(service),
+			"destination_service_name":      model.LabelValue(strings.Split(service, ".")[0]),
+			"destination_service_namespace": "bookinfo",
+		}}
+	}
+	queryTime := time.Now()
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetOutboundTraffic", "bookinfo", "", "1h", queryTime).Return(model.Vector{
+		sample("productpage-v1", "bookinfo", "reviews.bookinfo.svc.cluster.local"),
+		sample("productpage-v1", "bookinfo", "reviews.bookinfo.svc.cluster.local"),
+		sample("productpage-v1", "bookinfo", "PassthroughCluster"),
+		sample("unknown", "bookinfo", "details.bookinfo.svc.cluster.local"),
+		sample("istio-ingressgateway", "istio-system", "productpage.bookinfo.svc.cluster.local"),
+	}, nil)
+
+	traffic, err := getOutboundTraffic(prom, "bookinfo", "", "1h", queryTime)
+	assert.NoError(err)
+	assert.Equal([]sidecars.OutboundTraffic{{
+		SourceWorkload:   "productpage-v1",
+		Service:          "reviews.bookinfo.svc.cluster.local",
+		ServiceName:      "reviews",
+		ServiceNamespace: "bookinfo",
+	}}, traffic)
+}
+
 func TestGetIstioObjectValidations(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
@@ -44,6 +174,98 @@ func TestGetIstioObjectValidations(t *testing.T) {
 	assert.NotEmpty(validations)
 }
 
+func TestGetValidationsSuppressedByConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(),
+		[]string{"details", "product", "customer"}, fakePods())
+
+	// Mocks reset the config, so it is set after building them
+	conf := config.NewConfig()
+	conf.Validations.SuppressByNamespace = map[string][]string{"test": {"KIA0203"}}
+	config.Set(conf)
+
+	validations, _ := vs.GetValidations("test", "")
+	validation := validations[models.IstioValidationKey{ObjectType: "destinationrule", Namespace: "test", Name: "product-dr"}]
+	assert.NotNil(validation)
+	assert.True(validation.Valid)
+	assert.Empty(validation.Checks)
+	assert.Len(validation.Suppressed, 1)
+	assert.Equal("KIA0203", validation.Suppressed[0].Code)
+}
+
+func TestGetValidationsWithCustomRules(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(),
+		[]string{"details", "product", "customer"}, fakePods())
+
+	conf := config.NewConfig()
+	conf.Validations.CustomRules = []config.CustomValidationRule{
+		{Code: "ORG0001", ObjectType: "virtualservices", Path: "spec/http[*]/timeout", Condition: "exists", Severity: "error", Message: "Timeout is required"},
+	}
+	config.Set(conf)
+	assert.NoError(custom.LoadRules(conf.Validations.CustomRules))
+	defer func() { _ = custom.LoadRules(nil) }()
+
+	validations, _ := vs.GetIstioObjectValidations("test", "virtualservices", "product-vs")
+	validation := validations[models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test", Name: "product-vs"}]
+	assert.NotNil(validation)
+	assert.False(validation.Valid)
+	assert.Len(validation.Checks, 1)
+	assert.Equal("ORG0001", validation.Checks[0].Code)
+	assert.Equal("spec/http[0]/timeout", validation.Checks[0].Path)
+}
+
+func TestGetValidationsUnknownObjectType(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(),
+		[]string{"details", "product", "customer"}, fakePods())
+
+	conf := config.NewConfig()
+	conf.Validations.CustomRules = []config.CustomValidationRule{
+		{Code: "ORG0001", ObjectType: "virtualservices", Path: "spec/http[*]/timeout", Condition: "exists", Severity: "error", Message: "Timeout is required"},
+	}
+	config.Set(conf)
+	assert.NoError(custom.LoadRules(conf.Validations.CustomRules))
+	defer func() { _ = custom.LoadRules(nil) }()
+
+	// Custom rules are not run for the types without checkers
+	validations, err := vs.GetIstioObjectValidations("test", "envoyfilters", "product-vs")
+	assert.NoError(err)
+	assert.Empty(validations)
+
+	_, err = vs.GetIstioObjectValidations("test", "unknown", "product-vs")
+	assert.Error(err)
+}
+
+func TestSuppressValidationsByAnnotation(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	dr := data.CreateEmptyDestinationRule("test", "product-dr", "product")
+	meta := dr.GetObjectMeta()
+	meta.Annotations = map[string]string{models.SuppressValidationsAnnotation: "kia0203, KIA0209"}
+	dr.SetObjectMeta(meta)
+
+	key := models.BuildKey("destinationrule", "product-dr", "test")
+	check := models.Build("destinationrules.nodest.subsetlabels", "spec/subsets[0]")
+	otherCheck := models.Build("destinationrules.multimatch", "spec/host")
+	validations := models.IstioValidations{
+		key: &models.IstioValidation{Name: "product-dr", ObjectType: "destinationrule", Valid: false, Checks: []*models.IstioCheck{&check, &otherCheck}},
+	}
+
+	suppressValidations(validations, newValidationAnnotations(kubernetes.IstioDetails{DestinationRules: []kubernetes.IstioObject{dr}}, nil, nil, kubernetes.MTLSDetails{}, kubernetes.RBACDetails{}))
+
+	assert.True(validations[key].Valid)
+	assert.Len(validations[key].Checks, 1)
+	assert.Equal("KIA0201", validations[key].Checks[0].Code)
+	assert.Len(validations[key].Suppressed, 1)
+	assert.Equal("KIA0203", validations[key].Suppressed[0].Code)
+}
+
 func TestGatewayValidation(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
@@ -62,6 +284,8 @@ func mockWorkLoadService(k8s *kubetest.K8SClientMock) WorkloadService {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakeRSSyncedWithPods(), nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(fakePods().Items, nil)
@@ -98,7 +322,11 @@ func mockMultiNamespaceGatewaysValidationService() IstioValidationsService {
 }
 
 func mockCombinedValidationService(istioObjects *kubernetes.IstioDetails, services []string, podList *core_v1.PodList) IstioValidationsService {
-	k8s := new(kubetest.K8SClientMock)
+	return mockCombinedValidationServiceWith(new(kubetest.K8SClientMock), istioObjects, services, podList)
+}
+
+// mockCombinedValidationServiceWith completes the mocks of k8s, whose previous mocks take precedence
+func mockCombinedValidationServiceWith(k8s *kubetest.K8SClientMock, istioObjects *kubernetes.IstioDetails, services []string, podList *core_v1.PodList) IstioValidationsService {
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "sidecars", "").Return(istioObjects.Sidecars, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "requestauthentications", "").Return(istioObjects.RequestAuthentications, nil)
 	k8s.On("GetServices", mock.AnythingOfType("string"), mock.AnythingOfType("map[string]string")).Return(fakeCombinedServices(services), nil)
@@ -120,6 +348,7 @@ func mockCombinedValidationService(istioObjects *kubernetes.IstioDetails, servic
 	k8s.On("GetIstioObjects", "test2", "gateways", "").Return(getGateway("second"), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "gateways", "").Return(fakeCombinedIstioDetails().Gateways, nil)
 	k8s.On("GetNamespaces", mock.AnythingOfType("string")).Return(fakeNamespaces(), nil)
+	k8s.On("GetIstioObject", "test", "destinationrules", "product-dr").Return(fakeCombinedIstioDetails().DestinationRules[0], nil)
 
 	mockWorkLoadService(k8s)
 
@@ -68,6 +68,7 @@ func IsResourceCached(namespace string, resource string) bool {
 func Get(token string) (*Layer, error) {
 	// Kiali Cache will be initialized once at first use of Business layer
 	once.Do(initKialiCache)
+	istioConfigHistoryOnce.Do(initIstioConfigHistory)
 
 	// Use an existing client factory if it exists, otherwise create and use in the future
 	if clientFactory == nil {
@@ -113,9 +114,9 @@ func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInter
 	temporaryLayer := &Layer{}
 	temporaryLayer.Health = HealthService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Svc = SvcService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
-	temporaryLayer.IstioConfig = IstioConfigService{k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.IstioConfig = IstioConfigService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
 	temporaryLayer.Workload = WorkloadService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
-	temporaryLayer.Validations = IstioValidationsService{k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.Validations = IstioValidationsService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
 	temporaryLayer.App = AppService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Namespace = NewNamespaceService(k8s)
 	temporaryLayer.Jaeger = JaegerService{loader: jaegerClient, businessLayer: temporaryLayer}
@@ -129,8 +130,15 @@ func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInter
 	return temporaryLayer
 }
 
+// SetUser sets the user applying the changes through the business layer
+func (in *Layer) SetUser(user string) {
+	in.IstioConfig.user = user
+}
+
 func Stop() {
 	if kialiCache != nil {
 		kialiCache.Stop()
 	}
+	stopChaosExperiments()
+	stopProxyLogLevelResets()
 }
@@ -0,0 +1,251 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+)
+
+// logParser extracts the fields of the log entries written in its format
+type logParser interface {
+	// parse fills the fields of the entry, and may set its severity and its message.
+	// It returns false when the message of the entry is not in the format of the parser.
+	parse(entry *LogEntry) bool
+}
+
+// newLogParsers returns the parsers tried in order on each entry, until one of them parses it
+func newLogParsers() []logParser {
+	conf := config.Get()
+	return []logParser{
+		envoyAccessLogParser{},
+		jsonLogParser{levelKeys: conf.LogParsing.LevelKeys, messageKeys: conf.LogParsing.MessageKeys},
+	}
+}
+
+// envoyAccessLogRe matches the default access log format of the Istio proxies:
+// [%START_TIME%] "%REQ(:METHOD)% %REQ(X-ENVOY-ORIGINAL-PATH?:PATH)% %PROTOCOL%" %RESPONSE_CODE% %RESPONSE_FLAGS%
+// "%DYNAMIC_METADATA(istio.mixer:status)%" "%UPSTREAM_TRANSPORT_FAILURE_REASON%" %BYTES_RECEIVED% %BYTES_SENT% %DURATION%
+// %RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)% "%REQ(X-FORWARDED-FOR)%" "%REQ(USER-AGENT)%" "%REQ(X-REQUEST-ID)%"
+// "%REQ(:AUTHORITY)%" "%UPSTREAM_HOST%" %UPSTREAM_CLUSTER% ...
+// The quoted fields after the flags changed between Istio versions, so any number of them is accepted.
+var envoyAccessLogRe = regexp.MustCompile(`^\[[^\]]+\] "(?P<method>\S+) (?P<path>\S+) (?P<protocol>\S+)" (?P<code>\d+) (?P<flags>\S+)(?: "[^"]*")* (?P<bytes_received>\d+) (?P<bytes_sent>\d+) (?P<duration>\d+) (?P<upstream_service_time>\S+) "(?P<forwarded_for>[^"]*)" "(?P<user_agent>[^"]*)" "(?P<request_id>[^"]*)" "(?P<authority>[^"]*)" "(?P<upstream_host>[^"]*)" (?P<upstream_cluster>\S+)`)
+
+// envoyJSONAccessLogKeys are the keys of the Istio proxies access logs in JSON, with the fields of the text format
+var envoyJSONAccessLogKeys = map[string]string{
+	"method":                "method",
+	"path":                  "path",
+	"protocol":              "protocol",
+	"response_code":         "code",
+	"response_flags":        "flags",
+	"bytes_received":        "bytes_received",
+	"bytes_sent":            "bytes_sent",
+	"duration":              "duration",
+	"upstream_service_time": "upstream_service_time",
+	"x_forwarded_for":       "forwarded_for",
+	"user_agent":            "user_agent",
+	"request_id":            "request_id",
+	"authority":             "authority",
+	"upstream_host":         "upstream_host",
+	"upstream_cluster":      "upstream_cluster",
+}
+
+// envoyAccessLogParser parses the access logs of the Istio proxies, in the default text format or in JSON
+type envoyAccessLogParser struct{}
+
+func (p envoyAccessLogParser) parse(entry *LogEntry) bool {
+	fields := map[string]string{}
+	if strings.HasPrefix(entry.Message, "[") {
+		match := envoyAccessLogRe.FindStringSubmatch(entry.Message)
+		if match == nil {
+			return false
+		}
+		for i, name := range envoyAccessLogRe.SubexpNames() {
+			if name != "" {
+				fields[name] = match[i]
+			}
+		}
+	} else if strings.HasPrefix(entry.Message, "{") {
+		values := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(entry.Message), &values); err != nil {
+			return false
+		}
+		if _, ok := values["response_code"]; !ok {
+			return false
+		}
+		if _, ok := values["upstream_cluster"]; !ok {
+			return false
+		}
+		for key, field := range envoyJSONAccessLogKeys {
+			if value, ok := values[key]; ok && value != nil {
+				fields[field] = logFieldValue(value)
+			}
+		}
+	} else {
+		return false
+	}
+
+	entry.Fields = fields
+	if code, err := strconv.Atoi(fields["code"]); err == nil {
+		switch {
+		case code >= 500:
+			entry.Severity = "ERROR"
+		case code >= 400:
+			entry.Severity = "WARN"
+		default:
+			entry.Severity = "INFO"
+		}
+	}
+	return true
+}
+
+// jsonLogParser parses the application logs in JSON. The level and the message are read from the first key found.
+type jsonLogParser struct {
+	levelKeys   []string
+	messageKeys []string
+}
+
+func (p jsonLogParser) parse(entry *LogEntry) bool {
+	if !strings.HasPrefix(entry.Message, "{") {
+		return false
+	}
+	values := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(entry.Message), &values); err != nil {
+		return false
+	}
+
+	fields := make(map[string]string, len(values))
+	for key, value := range values {
+		if value != nil {
+			fields[key] = logFieldValue(value)
+		}
+	}
+	entry.Fields = fields
+	for _, key := range p.levelKeys {
+		if level, ok := fields[key]; ok && level != "" {
+			entry.Severity = strings.ToUpper(level)
+			break
+		}
+	}
+	for _, key := range p.messageKeys {
+		if message, ok := fields[key]; ok {
+			entry.Message = message
+			break
+		}
+	}
+	return true
+}
+
+// logFieldValue returns the value of a JSON field as a string, integers without exponent
+func logFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	}
+}
+
+// LogFilter keeps the log entries whose message contains a text, or matches it as a regular expression,
+// and whose parsed fields meet all the conditions, i.e. code>=500
+type LogFilter struct {
+	Text   string
+	Regex  bool
+	Fields []string
+}
+
+// logFieldConditionRe matches a condition on a field: field, operator and value
+var logFieldConditionRe = regexp.MustCompile(`^\s*([A-Za-z0-9_.\-]+)\s*(>=|<=|!=|=|>|<)\s*(.*?)\s*$`)
+
+// logFieldCondition is a condition on a parsed field of the log entries
+type logFieldCondition struct {
+	field    string
+	operator string
+	value    string
+}
+
+// match compares the field with the value as numbers when both are numbers, otherwise only = and != are met.
+// Entries without the field never match.
+func (c logFieldCondition) match(entry LogEntry) bool {
+	value, ok := entry.Fields[c.field]
+	if !ok {
+		return false
+	}
+	number, err1 := strconv.ParseFloat(value, 64)
+	expected, err2 := strconv.ParseFloat(c.value, 64)
+	if err1 != nil || err2 != nil {
+		switch c.operator {
+		case "=":
+			return value == c.value
+		case "!=":
+			return value != c.value
+		}
+		return false
+	}
+	switch c.operator {
+	case "=":
+		return number == expected
+	case "!=":
+		return number != expected
+	case ">":
+		return number > expected
+	case ">=":
+		return number >= expected
+	case "<":
+		return number < expected
+	default:
+		return number <= expected
+	}
+}
+
+// entryParser returns the function parsing the fields of an entry, which returns false for the entries filtered out
+func (filter LogFilter) entryParser() (func(entry *LogEntry) bool, error) {
+	var matchText func(message string) bool
+	if filter.Text != "" {
+		if filter.Regex {
+			re, err := regexp.Compile(filter.Text)
+			if err != nil {
+				return nil, errors.NewBadRequest(fmt.Sprintf("invalid filter [%s]: %v", filter.Text, err))
+			}
+			matchText = re.MatchString
+		} else {
+			matchText = func(message string) bool {
+				return strings.Contains(message, filter.Text)
+			}
+		}
+	}
+	conditions := make([]logFieldCondition, 0, len(filter.Fields))
+	for _, condition := range filter.Fields {
+		match := logFieldConditionRe.FindStringSubmatch(condition)
+		if match == nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid field condition [%s], expected i.e. code>=500", condition))
+		}
+		conditions = append(conditions, logFieldCondition{field: match[1], operator: match[2], value: match[3]})
+	}
+
+	parsers := newLogParsers()
+	return func(entry *LogEntry) bool {
+		for _, parser := range parsers {
+			if parser.parse(entry) {
+				break
+			}
+		}
+		if matchText != nil && !matchText(entry.Message) {
+			return false
+		}
+		for _, condition := range conditions {
+			if !condition.match(*entry) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
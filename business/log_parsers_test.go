@@ -0,0 +1,104 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+)
+
+const fakeAccessLog = `[2020-11-25T21:26:18.409Z] "GET /reviews/0 HTTP/1.1" 503 UF,URX "-" "upstream connect error" 0 91 25 - "-" "Mozilla/5.0" "5d9a4c08-4e16-9b3c-a8b4-b1a71a6f2bd5" "reviews:9080" "10.1.1.3:9080" outbound|9080|v1|reviews.bookinfo.svc.cluster.local 10.1.1.5:40204 10.96.16.124:9080 10.1.1.5:33782 - default`
+
+func TestEnvoyAccessLogParser(t *testing.T) {
+	assert := assert.New(t)
+
+	entry := LogEntry{Message: fakeAccessLog, Severity: "INFO"}
+	assert.True(envoyAccessLogParser{}.parse(&entry))
+	assert.Equal("ERROR", entry.Severity)
+	assert.Equal("GET", entry.Fields["method"])
+	assert.Equal("/reviews/0", entry.Fields["path"])
+	assert.Equal("503", entry.Fields["code"])
+	assert.Equal("UF,URX", entry.Fields["flags"])
+	assert.Equal("25", entry.Fields["duration"])
+	assert.Equal("-", entry.Fields["upstream_service_time"])
+	assert.Equal("10.1.1.3:9080", entry.Fields["upstream_host"])
+	assert.Equal("outbound|9080|v1|reviews.bookinfo.svc.cluster.local", entry.Fields["upstream_cluster"])
+
+	// TCP connections have no method nor path
+	entry = LogEntry{Message: `[2020-11-25T21:26:18.409Z] "- - -" 0 - "-" "-" 358 1102 3 - "-" "-" "-" "-" "10.1.1.8:3306" outbound|3306||mysqldb.bookinfo.svc.cluster.local 10.1.1.5:40204 10.96.16.12:3306 10.1.1.5:33782 - -`}
+	assert.True(envoyAccessLogParser{}.parse(&entry))
+	assert.Equal("0", entry.Fields["code"])
+	assert.Equal("outbound|3306||mysqldb.bookinfo.svc.cluster.local", entry.Fields["upstream_cluster"])
+
+	entry = LogEntry{Message: `{"start_time":"2020-11-25T21:26:18.409Z","method":"POST","path":"/ratings","response_code":404,"response_flags":"-","duration":12,"upstream_cluster":"outbound|9080||ratings.bookinfo.svc.cluster.local","upstream_host":null}`}
+	assert.True(envoyAccessLogParser{}.parse(&entry))
+	assert.Equal("WARN", entry.Severity)
+	assert.Equal(map[string]string{
+		"method":           "POST",
+		"path":             "/ratings",
+		"code":             "404",
+		"flags":            "-",
+		"duration":         "12",
+		"upstream_cluster": "outbound|9080||ratings.bookinfo.svc.cluster.local",
+	}, entry.Fields)
+
+	for _, message := range []string{`[Envoy (Epoch 0)] [2020-11-25 21:26:18.409][1][info] starting`, `{"level":"info"}`, "INFO #1 Log Message"} {
+		entry = LogEntry{Message: message}
+		assert.False(envoyAccessLogParser{}.parse(&entry), message)
+		assert.Nil(entry.Fields)
+	}
+}
+
+func TestJSONLogParser(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := jsonLogParser{levelKeys: []string{"level", "severity"}, messageKeys: []string{"msg", "message"}}
+	entry := LogEntry{Message: `{"severity":"warning","message":"slow query","elapsed":1.5,"rows":12000,"tags":["db"]}`, Severity: "INFO"}
+	assert.True(parser.parse(&entry))
+	assert.Equal("WARNING", entry.Severity)
+	assert.Equal("slow query", entry.Message)
+	assert.Equal(map[string]string{"severity": "warning", "message": "slow query", "elapsed": "1.5", "rows": "12000", "tags": `["db"]`}, entry.Fields)
+
+	entry = LogEntry{Message: "INFO #1 Log Message", Severity: "INFO"}
+	assert.False(parser.parse(&entry))
+	assert.Equal("INFO #1 Log Message", entry.Message)
+}
+
+func TestLogFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	parseEntry, err := LogFilter{}.entryParser()
+	assert.NoError(err)
+	entry := LogEntry{Message: fakeAccessLog}
+	assert.True(parseEntry(&entry))
+	assert.Equal("503", entry.Fields["code"])
+
+	matches := func(filter LogFilter, message string) bool {
+		parseEntry, err := filter.entryParser()
+		assert.NoError(err)
+		return parseEntry(&LogEntry{Message: message})
+	}
+	assert.True(matches(LogFilter{Text: "GET /reviews"}, fakeAccessLog))
+	assert.False(matches(LogFilter{Text: "GET /ratings"}, fakeAccessLog))
+	assert.True(matches(LogFilter{Text: `" 5\d\d `, Regex: true}, fakeAccessLog))
+	assert.True(matches(LogFilter{Fields: []string{"code>=500", "method=GET"}}, fakeAccessLog))
+	assert.True(matches(LogFilter{Fields: []string{"duration > 20", "flags!=-"}}, fakeAccessLog))
+	assert.False(matches(LogFilter{Fields: []string{"code>=500", "method=POST"}}, fakeAccessLog))
+	assert.False(matches(LogFilter{Fields: []string{"code<500"}}, fakeAccessLog))
+	// Ordering only applies to numbers, and entries without the field never match
+	assert.False(matches(LogFilter{Fields: []string{"upstream_service_time>=0"}}, fakeAccessLog))
+	assert.False(matches(LogFilter{Fields: []string{"code>=500"}}, "INFO #1 Log Message"))
+	// The message of the JSON logs is filtered after being parsed
+	assert.True(matches(LogFilter{Text: "slow", Fields: []string{"level=error"}}, `{"level":"error","msg":"slow query"}`))
+	assert.False(matches(LogFilter{Text: "level"}, `{"level":"error","msg":"slow query"}`))
+
+	_, err = LogFilter{Text: "(", Regex: true}.entryParser()
+	assert.True(errors.IsBadRequest(err))
+	_, err = LogFilter{Fields: []string{"code"}}.entryParser()
+	assert.True(errors.IsBadRequest(err))
+}
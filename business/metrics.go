@@ -138,6 +138,37 @@ func (in *MetricsService) fetchAllMetrics(q models.IstioMetricsQuery, lb *Metric
 	return metrics, nil
 }
 
+// GetContainerMetrics returns the CPU and memory metrics of the containers of the given pods, by container, for the container
+// metrics requested by the filters of the query
+func (in *MetricsService) GetContainerMetrics(q models.IstioMetricsQuery, pods models.Pods) (models.MetricsMap, error) {
+	metrics := make(models.MetricsMap)
+	if len(pods) == 0 {
+		return metrics, nil
+	}
+	names := q.ContainerFilters()
+	namesOfPods := podNames(pods)
+	results := make([]prometheus.Metric, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i] = in.prom.FetchContainerRange(name, q.Namespace, namesOfPods, &q.RangeQuery)
+		}(i, name)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		converted, err := models.ConvertMetric(name, results[i], models.ConversionParams{Scale: 1.0})
+		if err != nil {
+			return nil, err
+		}
+		metrics[name] = converted
+	}
+	return metrics, nil
+}
+
 // GetStats computes metrics stats, currently response times, for a set of queries
 func (in *MetricsService) GetStats(queries []models.MetricsStatsQuery) (map[string]models.MetricsStats, error) {
 	type statsChanResult struct {
@@ -0,0 +1,81 @@
+package business
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/log"
+)
+
+// MaxLogStreamDuration is the longest time the logs of a pod are followed
+const MaxLogStreamDuration = time.Hour
+
+// logStreamBuffer is the number of log entries read ahead of the client. While the buffer is full, the pod logs are
+// not read, so a slow client slows down the stream from the Kubernetes API instead of filling the memory of Kiali.
+const logStreamBuffer = 100
+
+// StreamPodLogs follows the logs of a pod container, sending the entries in the returned channel as they are written.
+// The channel is closed when the stop channel is closed, after maxDuration or when the container terminates.
+func (in *WorkloadService) StreamPodLogs(namespace, name string, opts *LogOptions, maxDuration time.Duration, stop <-chan struct{}) (<-chan LogEntry, error) {
+	if maxDuration <= 0 || maxDuration > MaxLogStreamDuration {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid max duration [%v], it must be positive and at most %v", maxDuration, MaxLogStreamDuration))
+	}
+
+	parseEntry, err := opts.Filter.entryParser()
+	if err != nil {
+		return nil, err
+	}
+
+	k8sOpts := opts.PodLogOptions
+	k8sOpts.Follow = true
+	stream, err := in.k8s.StreamPodLogs(namespace, name, &k8sOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(chan LogEntry, logStreamBuffer)
+	quit := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		timer := time.NewTimer(maxDuration)
+		defer timer.Stop()
+		select {
+		case <-stop:
+		case <-timer.C:
+		case <-done:
+		}
+		close(quit)
+		// Closing the stream unblocks the read of the next line
+		stream.Close()
+	}()
+
+	go func() {
+		defer close(entries)
+		defer close(done)
+		reader := bufio.NewReader(stream)
+		for {
+			line, err := reader.ReadString('\n')
+			if entry, _, ok := parseLogLine(line); ok && parseEntry(&entry) {
+				select {
+				case entries <- entry:
+				case <-quit:
+					return
+				}
+			}
+			if err != nil {
+				select {
+				case <-quit:
+				default:
+					log.Debugf("End of the logs of pod [%s/%s]: %v", namespace, name, err)
+				}
+				return
+			}
+		}
+	}()
+
+	return entries, nil
+}
@@ -0,0 +1,83 @@
+package business
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+)
+
+func TestStreamPodLogs(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("StreamPodLogs", "bookinfo", "details-v1", mock.MatchedBy(func(opts *core_v1.PodLogOptions) bool {
+		return opts.Follow && opts.Container == "details"
+	})).Return(ioutil.NopCloser(strings.NewReader(
+		"2018-01-02T03:34:28+00:00 INFO #1 Log Message\n\n2018-01-02T04:34:28+00:00 WARN #2 Log Message")), nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	opts := &LogOptions{PodLogOptions: core_v1.PodLogOptions{Container: "details", Timestamps: true}}
+	entries, err := layer.Workload.StreamPodLogs("bookinfo", "details-v1", opts, time.Minute, make(chan struct{}))
+	assert.NoError(err)
+
+	received := []LogEntry{}
+	for entry := range entries {
+		received = append(received, entry)
+	}
+	// The stream ends with the logs of the container
+	assert.Len(received, 2)
+	assert.Equal("INFO #1 Log Message", received[0].Message)
+	assert.Equal("WARN", received[1].Severity)
+	assert.False(opts.Follow)
+
+	_, err = layer.Workload.StreamPodLogs("bookinfo", "details-v1", opts, 2*MaxLogStreamDuration, make(chan struct{}))
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestStreamPodLogsStop(t *testing.T) {
+	assert := assert.New(t)
+
+	reader, writer := io.Pipe()
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("StreamPodLogs", "bookinfo", "details-v1", mock.Anything).Return(reader, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	stop := make(chan struct{})
+	entries, err := layer.Workload.StreamPodLogs("bookinfo", "details-v1", &LogOptions{PodLogOptions: core_v1.PodLogOptions{Timestamps: true}}, time.Minute, stop)
+	assert.NoError(err)
+
+	go func() {
+		_, _ = writer.Write([]byte("2018-01-02T03:34:28+00:00 INFO #1 Log Message\n"))
+	}()
+	entry := <-entries
+	assert.Equal("INFO #1 Log Message", entry.Message)
+
+	// The stream from the pod is closed when the client stops following the logs
+	close(stop)
+	select {
+	case _, ok := <-entries:
+		assert.False(ok)
+	case <-time.After(5 * time.Second):
+		assert.Fail("stream not closed")
+	}
+	_, err = writer.Write([]byte("2018-01-02T04:34:28+00:00 INFO #2 Log Message\n"))
+	assert.Equal(io.ErrClosedPipe, err)
+}
@@ -156,8 +156,8 @@ func (in *SvcService) GetService(namespace, service, interval string, queryTime
 	additionalDetails := models.GetAdditionalDetails(conf, svc.ObjectMeta.Annotations)
 
 	wg := sync.WaitGroup{}
-	wg.Add(6)
-	errChan := make(chan error, 6)
+	wg.Add(7)
+	errChan := make(chan error, 7)
 
 	labelsSelector := labels.Set(svc.Spec.Selector).String()
 	// If service doesn't have any selector, we can't know which are the pods and workloads applying.
@@ -252,6 +252,13 @@ func (in *SvcService) GetService(namespace, service, interval string, queryTime
 		drCreate, drUpdate, drDelete = getPermissions(in.k8s, namespace, kubernetes.DestinationRules)
 	}()
 
+	var events models.Events
+	go func() {
+		defer wg.Done()
+		objects := serviceEventObjects(service)
+		events = parseEvents(fetchObjectEvents(in.businessLayer, namespace, objects), objects)
+	}()
+
 	wg.Wait()
 	if len(errChan) != 0 {
 		err = <-errChan
@@ -265,7 +272,7 @@ func (in *SvcService) GetService(namespace, service, interval string, queryTime
 		wo = append(wo, wi)
 	}
 
-	s := models.ServiceDetails{Workloads: wo, Health: hth, NamespaceMTLS: nsmtls, AdditionalDetails: additionalDetails}
+	s := models.ServiceDetails{Workloads: wo, Health: hth, NamespaceMTLS: nsmtls, AdditionalDetails: additionalDetails, Events: events}
 	s.SetService(svc)
 	s.SetPods(kubernetes.FilterPodsForEndpoints(eps, pods))
 	s.SetEndpoints(eps)
@@ -0,0 +1,103 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/business/checkers/sidecars"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// defaultSidecarName is the name of the generated namespace-wide Sidecar, as used by convention in Istio
+const defaultSidecarName = "default"
+
+// GenerateSidecar returns a Sidecar whose egress hosts are the services called, during the lookback duration, by the
+// workloads of the namespace or by a single workload when it is not empty. The control plane namespace is always included.
+// The object is not created, so it can be reviewed before calling CreateIstioConfigDetail.
+func (in *IstioConfigService) GenerateSidecar(namespace, workload, lookback string, queryTime time.Time) (kubernetes.IstioObject, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "GenerateSidecar")
+	defer promtimer.ObserveNow(&err)
+
+	if in.prom == nil {
+		err = errors.New("prometheus is required to generate a sidecar from the observed traffic")
+		return nil, err
+	}
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	sidecar := &kubernetes.GenericIstioObject{
+		TypeMeta: meta_v1.TypeMeta{
+			Kind:       kubernetes.PluralType[kubernetes.Sidecars],
+			APIVersion: kubernetes.ApiToVersion[kubernetes.ResourceTypesToAPI[kubernetes.Sidecars]],
+		},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      defaultSidecarName,
+			Namespace: namespace,
+		},
+		Spec: map[string]interface{}{},
+	}
+	if workload != "" {
+		var wk *models.Workload
+		if wk, err = in.businessLayer.Workload.GetWorkload(namespace, workload, "", false); err != nil {
+			return nil, err
+		}
+		if len(wk.Labels) == 0 {
+			err = errors2.NewBadRequest(fmt.Sprintf("workload [%s] has no labels to select it", workload))
+			return nil, err
+		}
+		selector := make(map[string]interface{}, len(wk.Labels))
+		for k, v := range wk.Labels {
+			selector[k] = v
+		}
+		sidecar.Name = workload
+		sidecar.Spec["workloadSelector"] = map[string]interface{}{"labels": selector}
+	}
+
+	var traffic []sidecars.OutboundTraffic
+	if traffic, err = getOutboundTraffic(in.prom, namespace, workload, lookback, queryTime); err != nil {
+		return nil, err
+	}
+
+	istioNamespace := config.Get().IstioNamespace
+	found := map[string]bool{}
+	hosts := make([]string, 0, len(traffic))
+	for _, t := range traffic {
+		// Services of the control plane are already included
+		if t.ServiceNamespace == istioNamespace {
+			continue
+		}
+		// ServiceEntries may be reported without namespace
+		hostNamespace := t.ServiceNamespace
+		if hostNamespace == "" || hostNamespace == "unknown" {
+			hostNamespace = "*"
+		}
+		host := hostNamespace + "/" + t.Service
+		if !found[host] {
+			found[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+
+	egressHosts := []interface{}{istioNamespace + "/*"}
+	for _, h := range hosts {
+		egressHosts = append(egressHosts, h)
+	}
+	sidecar.Spec["egress"] = []interface{}{
+		map[string]interface{}{"hosts": egressHosts},
+	}
+	return sidecar, nil
+}
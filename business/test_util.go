@@ -745,6 +745,124 @@ func FakePodsFromDaemonSet() []core_v1.Pod {
 	}
 }
 
+func FakeDaemonSets() []apps_v1.DaemonSet {
+	conf := config.NewConfig()
+	config.Set(conf)
+	appLabel := conf.IstioLabels.AppLabelName
+	versionLabel := conf.IstioLabels.VersionLabelName
+	t1, _ := time.Parse(time.RFC822Z, "08 Mar 18 17:44 +0300")
+	return []apps_v1.DaemonSet{
+		{
+			TypeMeta: meta_v1.TypeMeta{
+				Kind: "DaemonSet",
+			},
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:              "daemon-controller",
+				CreationTimestamp: meta_v1.NewTime(t1),
+			},
+			Spec: apps_v1.DaemonSetSpec{
+				Template: core_v1.PodTemplateSpec{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Labels:      map[string]string{appLabel: "httpbin", versionLabel: "v1"},
+						Annotations: map[string]string{"sidecar.istio.io/inject": "true"},
+					},
+				},
+			},
+			Status: apps_v1.DaemonSetStatus{
+				DesiredNumberScheduled: 3,
+				CurrentNumberScheduled: 3,
+				NumberAvailable:        2,
+			},
+		},
+	}
+}
+
+func FakeArgoRollouts() []kubernetes.ArgoRollout {
+	conf := config.NewConfig()
+	config.Set(conf)
+	appLabel := conf.IstioLabels.AppLabelName
+	t1, _ := time.Parse(time.RFC822Z, "08 Mar 18 17:44 +0300")
+	replicas := int32(4)
+	weight20 := int32(20)
+	weight50 := int32(50)
+	currentStep := int32(2)
+	rollout := kubernetes.ArgoRollout{
+		TypeMeta: meta_v1.TypeMeta{
+			Kind: "Rollout",
+		},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              "reviews",
+			CreationTimestamp: meta_v1.NewTime(t1),
+		},
+		Spec: kubernetes.ArgoRolloutSpec{
+			Replicas: &replicas,
+			Template: core_v1.PodTemplateSpec{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Labels: map[string]string{appLabel: "reviews"},
+				},
+			},
+		},
+		Status: kubernetes.ArgoRolloutStatus{
+			Replicas:          4,
+			AvailableReplicas: 4,
+			CurrentStepIndex:  &currentStep,
+			CurrentPodHash:    "7d9f",
+			StableRS:          "5c4b",
+			Phase:             "Paused",
+		},
+	}
+	rollout.Spec.Strategy.Canary = &kubernetes.ArgoRolloutCanaryStrategy{
+		CanaryService: "reviews-canary",
+		StableService: "reviews-stable",
+		Steps: []kubernetes.ArgoRolloutStep{
+			{SetWeight: &weight20},
+			{Pause: &kubernetes.ArgoRolloutPause{}},
+			{SetWeight: &weight50},
+		},
+	}
+	return []kubernetes.ArgoRollout{rollout}
+}
+
+func FakeArgoRolloutReplicaSets() []apps_v1.ReplicaSet {
+	controller := true
+	owner := []meta_v1.OwnerReference{{Controller: &controller, Kind: "Rollout", Name: "reviews"}}
+	replicaSet := func(hash string, replicas int32) apps_v1.ReplicaSet {
+		return apps_v1.ReplicaSet{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:            "reviews-" + hash,
+				Labels:          map[string]string{kubernetes.ArgoRolloutPodHashLabel: hash},
+				OwnerReferences: owner,
+			},
+			Status: apps_v1.ReplicaSetStatus{Replicas: replicas},
+		}
+	}
+	return []apps_v1.ReplicaSet{replicaSet("5c4b", 3), replicaSet("7d9f", 1)}
+}
+
+func FakePodsFromArgoRollout() []core_v1.Pod {
+	conf := config.NewConfig()
+	config.Set(conf)
+	appLabel := conf.IstioLabels.AppLabelName
+	controller := true
+	pods := []core_v1.Pod{}
+	for _, rs := range FakeArgoRolloutReplicaSets() {
+		hash := rs.Labels[kubernetes.ArgoRolloutPodHashLabel]
+		pods = append(pods, core_v1.Pod{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:   rs.Name + "-pod",
+				Labels: map[string]string{appLabel: "reviews", kubernetes.ArgoRolloutPodHashLabel: hash},
+				OwnerReferences: []meta_v1.OwnerReference{{
+					Controller: &controller,
+					Kind:       "ReplicaSet",
+					Name:       rs.Name,
+				}},
+				Annotations: kubetest.FakeIstioAnnotations(),
+			},
+		})
+	}
+	return pods
+}
+
 func FakeServices() []core_v1.Service {
 	return []core_v1.Service{
 		{
@@ -0,0 +1,663 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	// KialiWizardLabel is set on the objects generated by a traffic wizard, with the wizard as value
+	KialiWizardLabel = "kiali_wizard"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByKiali = "kiali"
+)
+
+// wizardSelector selects the objects generated by the traffic wizards
+var wizardSelector = fmt.Sprintf("%s=%s,%s", managedByLabel, managedByKiali, KialiWizardLabel)
+
+// wizardService is the service a traffic wizard is applied to, with its versions
+type wizardService struct {
+	namespace string
+	name      string
+	host      string
+	versions  []string
+}
+
+// ApplyWeightedRouting routes the traffic of a service to its versions by weight
+func (in *IstioConfigService) ApplyWeightedRouting(namespace, service string, routing models.WeightedRouting) (models.TrafficWizardResult, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ApplyWeightedRouting")
+	defer promtimer.ObserveNow(&err)
+
+	var svc *wizardService
+	if svc, err = in.getWizardService(namespace, service); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+	if len(routing.Weights) == 0 {
+		err = errors2.NewBadRequest("weights not set")
+		return models.TrafficWizardResult{}, err
+	}
+	total := 0
+	route := make([]interface{}, 0, len(routing.Weights))
+	for _, w := range routing.Weights {
+		if err = svc.checkVersion(w.Version); err != nil {
+			return models.TrafficWizardResult{}, err
+		}
+		if w.Weight < 0 || w.Weight > 100 {
+			err = errors2.NewBadRequest(fmt.Sprintf("weight of version [%s] out of range: %d", w.Version, w.Weight))
+			return models.TrafficWizardResult{}, err
+		}
+		total += w.Weight
+		route = append(route, map[string]interface{}{
+			"destination": svc.destination(w.Version),
+			"weight":      w.Weight,
+		})
+	}
+	if total != 100 {
+		err = errors2.NewBadRequest(fmt.Sprintf("weights add up to %d instead of 100", total))
+		return models.TrafficWizardResult{}, err
+	}
+
+	vsSpec := svc.virtualServiceSpec(map[string]interface{}{"route": route})
+	var result models.TrafficWizardResult
+	result, err = in.applyTrafficWizard(svc, models.WeightedRoutingWizard, svc.destinationRuleSpec(), vsSpec)
+	return result, err
+}
+
+// ApplyRequestRouting routes the requests of a service to a version by header or cookie
+func (in *IstioConfigService) ApplyRequestRouting(namespace, service string, routing models.RequestRouting) (models.TrafficWizardResult, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ApplyRequestRouting")
+	defer promtimer.ObserveNow(&err)
+
+	var svc *wizardService
+	if svc, err = in.getWizardService(namespace, service); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+	if len(routing.Rules) == 0 {
+		err = errors2.NewBadRequest("rules not set")
+		return models.TrafficWizardResult{}, err
+	}
+	routes := make([]interface{}, 0, len(routing.Rules)+1)
+	for _, rule := range routing.Rules {
+		var match map[string]interface{}
+		if match, err = requestRoutingMatch(rule); err != nil {
+			return models.TrafficWizardResult{}, err
+		}
+		if err = svc.checkVersion(rule.Version); err != nil {
+			return models.TrafficWizardResult{}, err
+		}
+		routes = append(routes, map[string]interface{}{
+			"match": []interface{}{match},
+			"route": []interface{}{map[string]interface{}{"destination": svc.destination(rule.Version)}},
+		})
+	}
+	if routing.DefaultVersion != "" {
+		if err = svc.checkVersion(routing.DefaultVersion); err != nil {
+			return models.TrafficWizardResult{}, err
+		}
+	}
+	routes = append(routes, map[string]interface{}{
+		"route": []interface{}{map[string]interface{}{"destination": svc.destination(routing.DefaultVersion)}},
+	})
+
+	vsSpec := svc.virtualServiceSpec(routes...)
+	var result models.TrafficWizardResult
+	result, err = in.applyTrafficWizard(svc, models.RequestRoutingWizard, svc.destinationRuleSpec(), vsSpec)
+	return result, err
+}
+
+// ApplyFaultInjection delays or aborts a percentage of the requests of a service
+func (in *IstioConfigService) ApplyFaultInjection(namespace, service string, fault models.FaultInjection) (models.TrafficWizardResult, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ApplyFaultInjection")
+	defer promtimer.ObserveNow(&err)
+
+	var svc *wizardService
+	if svc, err = in.getWizardService(namespace, service); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+	var faultSpec map[string]interface{}
+	if faultSpec, err = faultInjectionSpec(fault); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+
+	vsSpec := svc.virtualServiceSpec(map[string]interface{}{
+		"fault": faultSpec,
+		"route": []interface{}{map[string]interface{}{"destination": svc.destination("")}},
+	})
+	var result models.TrafficWizardResult
+	result, err = in.applyTrafficWizard(svc, models.FaultInjectionWizard, svc.destinationRuleSpec(), vsSpec)
+	return result, err
+}
+
+// ApplyRequestTimeouts sets the timeout and the retries of the requests of a service
+func (in *IstioConfigService) ApplyRequestTimeouts(namespace, service string, timeouts models.RequestTimeouts) (models.TrafficWizardResult, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ApplyRequestTimeouts")
+	defer promtimer.ObserveNow(&err)
+
+	var svc *wizardService
+	if svc, err = in.getWizardService(namespace, service); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+	if timeouts.Timeout == "" && timeouts.Retries == nil {
+		err = errors2.NewBadRequest("timeout or retries must be set")
+		return models.TrafficWizardResult{}, err
+	}
+	http := map[string]interface{}{
+		"route": []interface{}{map[string]interface{}{"destination": svc.destination("")}},
+	}
+	if timeouts.Timeout != "" {
+		if err = checkWizardDuration("timeout", timeouts.Timeout); err != nil {
+			return models.TrafficWizardResult{}, err
+		}
+		http["timeout"] = timeouts.Timeout
+	}
+	if r := timeouts.Retries; r != nil {
+		if r.Attempts <= 0 {
+			err = errors2.NewBadRequest(fmt.Sprintf("retry attempts must be positive: %d", r.Attempts))
+			return models.TrafficWizardResult{}, err
+		}
+		retries := map[string]interface{}{"attempts": r.Attempts}
+		if r.PerTryTimeout != "" {
+			if err = checkWizardDuration("perTryTimeout", r.PerTryTimeout); err != nil {
+				return models.TrafficWizardResult{}, err
+			}
+			retries["perTryTimeout"] = r.PerTryTimeout
+		}
+		if r.RetryOn != "" {
+			retries["retryOn"] = r.RetryOn
+		}
+		http["retries"] = retries
+	}
+
+	vsSpec := svc.virtualServiceSpec(http)
+	var result models.TrafficWizardResult
+	result, err = in.applyTrafficWizard(svc, models.RequestTimeoutsWizard, svc.destinationRuleSpec(), vsSpec)
+	return result, err
+}
+
+// ApplyCircuitBreaker limits the connections to the hosts of a service and ejects the failing ones
+func (in *IstioConfigService) ApplyCircuitBreaker(namespace, service string, breaker models.CircuitBreaker) (models.TrafficWizardResult, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ApplyCircuitBreaker")
+	defer promtimer.ObserveNow(&err)
+
+	var svc *wizardService
+	if svc, err = in.getWizardService(namespace, service); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+	var trafficPolicy map[string]interface{}
+	if trafficPolicy, err = circuitBreakerPolicy(breaker); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+
+	drSpec := svc.destinationRuleSpec()
+	drSpec["trafficPolicy"] = trafficPolicy
+	vsSpec := svc.virtualServiceSpec(map[string]interface{}{
+		"route": []interface{}{map[string]interface{}{"destination": svc.destination("")}},
+	})
+	var result models.TrafficWizardResult
+	result, err = in.applyTrafficWizard(svc, models.CircuitBreakerWizard, drSpec, vsSpec)
+	return result, err
+}
+
+// ApplyTrafficMirroring routes the requests of a service to a version and mirrors a percentage of them to another one
+func (in *IstioConfigService) ApplyTrafficMirroring(namespace, service string, mirroring models.TrafficMirroring) (models.TrafficWizardResult, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ApplyTrafficMirroring")
+	defer promtimer.ObserveNow(&err)
+
+	var svc *wizardService
+	if svc, err = in.getWizardService(namespace, service); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+	if err = svc.checkVersion(mirroring.Version); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+	if err = svc.checkVersion(mirroring.MirrorVersion); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+	if mirroring.Version == mirroring.MirrorVersion {
+		err = errors2.NewBadRequest(fmt.Sprintf("version [%s] can't be mirrored to itself", mirroring.Version))
+		return models.TrafficWizardResult{}, err
+	}
+	percentage := mirroring.Percentage
+	if percentage == 0 {
+		percentage = 100
+	}
+	if err = checkWizardPercentage(percentage); err != nil {
+		return models.TrafficWizardResult{}, err
+	}
+
+	vsSpec := svc.virtualServiceSpec(map[string]interface{}{
+		"route": []interface{}{map[string]interface{}{
+			"destination": svc.destination(mirroring.Version),
+			"weight":      100,
+		}},
+		"mirror":           svc.destination(mirroring.MirrorVersion),
+		"mirrorPercentage": map[string]interface{}{"value": percentage},
+	})
+	var result models.TrafficWizardResult
+	result, err = in.applyTrafficWizard(svc, models.TrafficMirroringWizard, svc.destinationRuleSpec(), vsSpec)
+	return result, err
+}
+
+// DeleteTrafficWizard deletes the VirtualServices and DestinationRules generated by the traffic wizards for a service
+func (in *IstioConfigService) DeleteTrafficWizard(namespace, service string) error {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "DeleteTrafficWizard")
+	defer promtimer.ObserveNow(&err)
+
+	var vs, dr []kubernetes.IstioObject
+	if vs, err = in.k8s.GetIstioObjects(namespace, kubernetes.VirtualServices, wizardSelector); err != nil {
+		return err
+	}
+	if dr, err = in.k8s.GetIstioObjects(namespace, kubernetes.DestinationRules, wizardSelector); err != nil {
+		return err
+	}
+	vs = kubernetes.FilterVirtualServices(vs, namespace, service)
+	dr = kubernetes.FilterDestinationRules(dr, namespace, service)
+	if len(vs) == 0 && len(dr) == 0 {
+		err = errors2.NewNotFound(schema.GroupResource{Group: "networking.istio.io", Resource: kubernetes.VirtualServices}, service)
+		return err
+	}
+
+	// VirtualServices are deleted before the DestinationRules defining the subsets they route to
+	for _, objects := range []struct {
+		objectType string
+		items      []kubernetes.IstioObject
+	}{{kubernetes.VirtualServices, vs}, {kubernetes.DestinationRules, dr}} {
+		api := GetIstioAPI(objects.objectType)
+		for _, o := range objects.items {
+			if deleteErr := in.DeleteIstioConfigDetail(api, namespace, objects.objectType, o.GetObjectMeta().Name); deleteErr != nil && !errors2.IsNotFound(deleteErr) {
+				err = deleteErr
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyTrafficWizard creates or updates the DestinationRule and the VirtualService of a wizard, named as the service.
+// Existing objects not generated by a wizard are never overwritten. The DestinationRule is applied first so the subsets
+// routed to exist, unless subsets are removed: the VirtualService not routing to them anymore is then applied first.
+// If the second object fails, the first one is rolled back: deleted when created, restored when updated.
+func (in *IstioConfigService) applyTrafficWizard(svc *wizardService, wizard string, drSpec, vsSpec map[string]interface{}) (models.TrafficWizardResult, error) {
+	result := models.TrafficWizardResult{Wizard: wizard}
+	objectTypes := []string{kubernetes.DestinationRules, kubernetes.VirtualServices}
+	specs := []map[string]interface{}{drSpec, vsSpec}
+
+	existing := make([]kubernetes.IstioObject, len(objectTypes))
+	for i, objectType := range objectTypes {
+		current, err := in.k8s.GetIstioObject(svc.namespace, objectType, svc.name)
+		if err == nil {
+			if !isWizardObject(current) {
+				return result, errors2.NewConflict(schema.GroupResource{Group: "networking.istio.io", Resource: objectType}, svc.name,
+					fmt.Errorf("%s not generated by a Kiali wizard already exists", kubernetes.PluralType[objectType]))
+			}
+			existing[i] = current
+		} else if !errors2.IsNotFound(err) {
+			return result, err
+		}
+	}
+
+	order := []int{0, 1}
+	if existing[0] != nil && removesSubsets(existing[0].GetSpec(), drSpec) {
+		order = []int{1, 0}
+	}
+	applied := make([]models.IstioConfigDetails, len(objectTypes))
+	for n, i := range order {
+		details, err := in.applyWizardObject(svc, objectTypes[i], wizard, specs[i], existing[i])
+		if err != nil {
+			if n > 0 {
+				in.rollbackWizardObject(svc, objectTypes[order[0]], existing[order[0]])
+			}
+			return result, err
+		}
+		applied[i] = details
+	}
+	if applied[0].DestinationRule != nil {
+		result.DestinationRule = *applied[0].DestinationRule
+	}
+	if applied[1].VirtualService != nil {
+		result.VirtualService = *applied[1].VirtualService
+	}
+	return result, nil
+}
+
+// rollbackWizardObject deletes an object created by a wizard, or restores it as it was before the wizard.
+// The wizard error is returned to the user, so rollback errors are only logged.
+func (in *IstioConfigService) rollbackWizardObject(svc *wizardService, objectType string, before kubernetes.IstioObject) {
+	api := GetIstioAPI(objectType)
+	var err error
+	if before == nil {
+		err = in.DeleteIstioConfigDetail(api, svc.namespace, objectType, svc.name)
+	} else {
+		err = in.restoreIstioObject(api, svc.namespace, objectType, before)
+	}
+	if err != nil {
+		log.Errorf("Error rolling back %s [%s/%s] applied by the wizard: %v", objectType, svc.namespace, svc.name, err)
+	}
+}
+
+// removesSubsets returns true when a subset of the current DestinationRule spec is not in the new one
+func removesSubsets(current, spec map[string]interface{}) bool {
+	names := map[string]bool{}
+	for _, name := range subsetNames(spec) {
+		names[name] = true
+	}
+	for _, name := range subsetNames(current) {
+		if !names[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func subsetNames(spec map[string]interface{}) []string {
+	names := []string{}
+	subsets, _ := spec["subsets"].([]interface{})
+	for _, subset := range subsets {
+		if s, ok := subset.(map[string]interface{}); ok {
+			if name, ok := s["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// applyWizardObject creates the object of a wizard or, when it exists, updates it to the given spec and labels
+// keeping the labels and annotations set by others
+func (in *IstioConfigService) applyWizardObject(svc *wizardService, objectType, wizard string, spec map[string]interface{}, current kubernetes.IstioObject) (models.IstioConfigDetails, error) {
+	api := GetIstioAPI(objectType)
+	if current == nil {
+		body, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":      svc.name,
+				"namespace": svc.namespace,
+				"labels":    wizardLabels(wizard),
+			},
+			"spec": spec,
+		})
+		if err != nil {
+			return models.IstioConfigDetails{}, err
+		}
+		return in.CreateIstioConfigDetail(api, svc.namespace, objectType, body)
+	}
+
+	object := current.DeepCopyIstioObject()
+	meta := object.GetObjectMeta()
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	for k, v := range wizardLabels(wizard) {
+		meta.Labels[k] = v
+	}
+	object.SetObjectMeta(meta)
+	object.SetSpec(spec)
+
+	from, err := toGenericJSON(reviewableIstioObject(objectType, current))
+	if err != nil {
+		return models.IstioConfigDetails{}, err
+	}
+	to, err := toGenericJSON(reviewableIstioObject(objectType, object))
+	if err != nil {
+		return models.IstioConfigDetails{}, err
+	}
+	patch, err := json.Marshal(util.CreateMergePatch(from, to))
+	if err != nil {
+		return models.IstioConfigDetails{}, err
+	}
+	return in.UpdateIstioConfigDetail(api, svc.namespace, objectType, svc.name, string(patch))
+}
+
+// getWizardService returns a service with the versions of its pods, as found in the version label
+func (in *IstioConfigService) getWizardService(namespace, service string) (*wizardService, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	svc, err := in.k8s.GetService(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	conf := config.Get()
+	ws := &wizardService{
+		namespace: namespace,
+		name:      service,
+		host:      fmt.Sprintf("%s.%s.%s", service, namespace, conf.ExternalServices.Istio.IstioIdentityDomain),
+		versions:  []string{},
+	}
+
+	labelsSelector := labels.Set(svc.Spec.Selector).String()
+	// If service doesn't have any selector, we can't know which are the pods and their versions
+	if labelsSelector == "" {
+		return ws, nil
+	}
+	var pods []core_v1.Pod
+	// Namespace access is checked above
+	if IsNamespaceCached(namespace) {
+		pods, err = kialiCache.GetPods(namespace, labelsSelector)
+	} else {
+		pods, err = in.k8s.GetPods(namespace, labelsSelector)
+	}
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	for _, pod := range pods {
+		if version, ok := pod.Labels[conf.IstioLabels.VersionLabelName]; ok && !found[version] {
+			found[version] = true
+			ws.versions = append(ws.versions, version)
+		}
+	}
+	sort.Strings(ws.versions)
+	return ws, nil
+}
+
+// checkVersion returns a BadRequest error when the version is not found in the pods of the service
+func (ws *wizardService) checkVersion(version string) error {
+	for _, v := range ws.versions {
+		if v == version {
+			return nil
+		}
+	}
+	return errors2.NewBadRequest(fmt.Sprintf("version [%s] not found in service [%s]", version, ws.name))
+}
+
+// destination returns the destination of a version of the service, the whole service when empty
+func (ws *wizardService) destination(version string) map[string]interface{} {
+	destination := map[string]interface{}{"host": ws.host}
+	if version != "" {
+		destination["subset"] = version
+	}
+	return destination
+}
+
+// destinationRuleSpec returns the spec of a DestinationRule with a subset per version
+func (ws *wizardService) destinationRuleSpec() map[string]interface{} {
+	spec := map[string]interface{}{"host": ws.host}
+	if len(ws.versions) > 0 {
+		subsets := make([]interface{}, 0, len(ws.versions))
+		versionLabel := config.Get().IstioLabels.VersionLabelName
+		for _, v := range ws.versions {
+			subsets = append(subsets, map[string]interface{}{
+				"name":   v,
+				"labels": map[string]interface{}{versionLabel: v},
+			})
+		}
+		spec["subsets"] = subsets
+	}
+	return spec
+}
+
+// virtualServiceSpec returns the spec of a VirtualService of the service with the given HTTP routes
+func (ws *wizardService) virtualServiceSpec(http ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"hosts": []interface{}{ws.host},
+		"http":  http,
+	}
+}
+
+// requestRoutingMatch returns the match of the requests of a rule. Cookies are matched in the cookie header.
+func requestRoutingMatch(rule models.RequestRoutingRule) (map[string]interface{}, error) {
+	if (rule.Header == "") == (rule.Cookie == "") {
+		return nil, errors2.NewBadRequest("either header or cookie must be set in a rule")
+	}
+	if rule.Value == "" {
+		return nil, errors2.NewBadRequest("value not set in a rule")
+	}
+	if rule.Regex {
+		if _, err := regexp.Compile(rule.Value); err != nil {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("invalid regex [%s]: %v", rule.Value, err))
+		}
+	}
+
+	header, match := rule.Header, "exact"
+	value := rule.Value
+	if rule.Regex {
+		match = "regex"
+	}
+	if rule.Cookie != "" {
+		cookieValue := regexp.QuoteMeta(rule.Value)
+		if rule.Regex {
+			cookieValue = rule.Value
+		}
+		header, match = "cookie", "regex"
+		value = fmt.Sprintf("^(.*?;)?(%s=%s)(;.*)?$", regexp.QuoteMeta(rule.Cookie), cookieValue)
+	}
+	return map[string]interface{}{
+		"headers": map[string]interface{}{
+			header: map[string]interface{}{match: value},
+		},
+	}, nil
+}
+
+// faultInjectionSpec returns the fault of the HTTP routes
+func faultInjectionSpec(fault models.FaultInjection) (map[string]interface{}, error) {
+	if fault.Delay == nil && fault.Abort == nil {
+		return nil, errors2.NewBadRequest("delay or abort must be set")
+	}
+	spec := map[string]interface{}{}
+	if d := fault.Delay; d != nil {
+		if err := checkWizardPercentage(d.Percentage); err != nil {
+			return nil, err
+		}
+		if err := checkWizardDuration("fixedDelay", d.FixedDelay); err != nil {
+			return nil, err
+		}
+		spec["delay"] = map[string]interface{}{
+			"percentage": map[string]interface{}{"value": d.Percentage},
+			"fixedDelay": d.FixedDelay,
+		}
+	}
+	if a := fault.Abort; a != nil {
+		if err := checkWizardPercentage(a.Percentage); err != nil {
+			return nil, err
+		}
+		if a.HttpStatus < 100 || a.HttpStatus > 599 {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("invalid HTTP status: %d", a.HttpStatus))
+		}
+		spec["abort"] = map[string]interface{}{
+			"percentage": map[string]interface{}{"value": a.Percentage},
+			"httpStatus": a.HttpStatus,
+		}
+	}
+	return spec, nil
+}
+
+// circuitBreakerPolicy returns the traffic policy of the DestinationRule, with the fields set
+func circuitBreakerPolicy(breaker models.CircuitBreaker) (map[string]interface{}, error) {
+	policy := map[string]interface{}{}
+	connectionPool := map[string]interface{}{}
+	if breaker.MaxConnections > 0 {
+		connectionPool["tcp"] = map[string]interface{}{"maxConnections": breaker.MaxConnections}
+	}
+	http := map[string]interface{}{}
+	if breaker.HTTP1MaxPendingRequests > 0 {
+		http["http1MaxPendingRequests"] = breaker.HTTP1MaxPendingRequests
+	}
+	if breaker.MaxRequestsPerConnection > 0 {
+		http["maxRequestsPerConnection"] = breaker.MaxRequestsPerConnection
+	}
+	if len(http) > 0 {
+		connectionPool["http"] = http
+	}
+	if len(connectionPool) > 0 {
+		policy["connectionPool"] = connectionPool
+	}
+
+	outlierDetection := map[string]interface{}{}
+	if breaker.Consecutive5xxErrors > 0 {
+		outlierDetection["consecutive5xxErrors"] = breaker.Consecutive5xxErrors
+	}
+	for _, d := range []struct{ field, value string }{{"interval", breaker.Interval}, {"baseEjectionTime", breaker.BaseEjectionTime}} {
+		if d.value == "" {
+			continue
+		}
+		if err := checkWizardDuration(d.field, d.value); err != nil {
+			return nil, err
+		}
+		outlierDetection[d.field] = d.value
+	}
+	if breaker.MaxEjectionPercent != 0 {
+		if breaker.MaxEjectionPercent < 0 || breaker.MaxEjectionPercent > 100 {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("maxEjectionPercent out of range: %d", breaker.MaxEjectionPercent))
+		}
+		outlierDetection["maxEjectionPercent"] = breaker.MaxEjectionPercent
+	}
+	if len(outlierDetection) > 0 {
+		policy["outlierDetection"] = outlierDetection
+	}
+
+	if len(policy) == 0 {
+		return nil, errors2.NewBadRequest("connection pool or outlier detection settings must be set")
+	}
+	return policy, nil
+}
+
+func checkWizardPercentage(percentage float64) error {
+	if percentage <= 0 || percentage > 100 {
+		return errors2.NewBadRequest(fmt.Sprintf("percentage out of range: %v", percentage))
+	}
+	return nil
+}
+
+func checkWizardDuration(field, value string) error {
+	if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+		return errors2.NewBadRequest(fmt.Sprintf("invalid %s duration [%s]", field, value))
+	}
+	return nil
+}
+
+// isWizardObject returns true when the object was generated by a traffic wizard
+func isWizardObject(object kubernetes.IstioObject) bool {
+	objectLabels := object.GetObjectMeta().Labels
+	_, found := objectLabels[KialiWizardLabel]
+	return found && objectLabels[managedByLabel] == managedByKiali
+}
+
+func wizardLabels(wizard string) map[string]string {
+	return map[string]string{
+		KialiWizardLabel: wizard,
+		managedByLabel:   managedByKiali,
+	}
+}
@@ -0,0 +1,242 @@
+package business
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestApplyWeightedRouting(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockWizardService()
+	k8s.On("GetIstioObject", "bookinfo", mock.AnythingOfType("string"), "reviews").Return((*kubernetes.GenericIstioObject)(nil), errors.NewNotFound(schema.GroupResource{}, "reviews"))
+	created := map[string]map[string]interface{}{}
+	k8s.On("CreateIstioObject", mock.Anything, "bookinfo", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		var object map[string]interface{}
+		assert.NoError(json.Unmarshal([]byte(args.String(3)), &object))
+		created[args.String(2)] = object
+	}).Return(fakeWizardObject(nil), nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	result, err := layer.IstioConfig.ApplyWeightedRouting("bookinfo", "reviews", models.WeightedRouting{
+		Weights: []models.VersionWeight{{Version: "v1", Weight: 80}, {Version: "v2", Weight: 20}},
+	})
+	assert.NoError(err)
+	assert.Equal(models.WeightedRoutingWizard, result.Wizard)
+
+	host := "reviews.bookinfo.svc.cluster.local"
+	dr := created[kubernetes.DestinationRules]
+	assert.Equal(map[string]interface{}{KialiWizardLabel: models.WeightedRoutingWizard, managedByLabel: managedByKiali}, dr["metadata"].(map[string]interface{})["labels"])
+	assert.Equal(map[string]interface{}{
+		"host": host,
+		"subsets": []interface{}{
+			map[string]interface{}{"name": "v1", "labels": map[string]interface{}{"version": "v1"}},
+			map[string]interface{}{"name": "v2", "labels": map[string]interface{}{"version": "v2"}},
+		},
+	}, dr["spec"])
+	vs := created[kubernetes.VirtualServices]
+	assert.Equal(map[string]interface{}{
+		"hosts": []interface{}{host},
+		"http": []interface{}{map[string]interface{}{
+			"route": []interface{}{
+				map[string]interface{}{"destination": map[string]interface{}{"host": host, "subset": "v1"}, "weight": float64(80)},
+				map[string]interface{}{"destination": map[string]interface{}{"host": host, "subset": "v2"}, "weight": float64(20)},
+			},
+		}},
+	}, vs["spec"])
+
+	_, err = layer.IstioConfig.ApplyWeightedRouting("bookinfo", "reviews", models.WeightedRouting{
+		Weights: []models.VersionWeight{{Version: "v1", Weight: 80}},
+	})
+	assert.True(errors.IsBadRequest(err))
+	_, err = layer.IstioConfig.ApplyWeightedRouting("bookinfo", "reviews", models.WeightedRouting{
+		Weights: []models.VersionWeight{{Version: "v3", Weight: 100}},
+	})
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestApplyTrafficWizardUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockWizardService()
+	labels := map[string]string{KialiWizardLabel: models.WeightedRoutingWizard, managedByLabel: managedByKiali, "team": "reviews"}
+	existing := fakeWizardObject(labels)
+	existing.Spec = map[string]interface{}{"host": "reviews.bookinfo.svc.cluster.local"}
+	k8s.On("GetIstioObject", "bookinfo", mock.AnythingOfType("string"), "reviews").Return(existing, nil)
+	patches := map[string]string{}
+	k8s.On("UpdateIstioObject", mock.Anything, "bookinfo", mock.AnythingOfType("string"), "reviews", mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		patches[args.String(2)] = args.String(4)
+	}).Return(existing, nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	_, err := layer.IstioConfig.ApplyCircuitBreaker("bookinfo", "reviews", models.CircuitBreaker{MaxConnections: 10, Consecutive5xxErrors: 3, BaseEjectionTime: "30s"})
+	assert.NoError(err)
+	// The labels set by others are kept, the spec is replaced
+	assert.JSONEq(`{
+		"metadata": {"labels": {"kiali_wizard": "circuit_breaker"}},
+		"spec": {
+			"subsets": [{"name": "v1", "labels": {"version": "v1"}}, {"name": "v2", "labels": {"version": "v2"}}],
+			"trafficPolicy": {
+				"connectionPool": {"tcp": {"maxConnections": 10}},
+				"outlierDetection": {"consecutive5xxErrors": 3, "baseEjectionTime": "30s"}
+			}
+		}
+	}`, patches[kubernetes.DestinationRules])
+	assert.JSONEq(`{
+		"metadata": {"labels": {"kiali_wizard": "circuit_breaker"}},
+		"spec": {
+			"host": null,
+			"hosts": ["reviews.bookinfo.svc.cluster.local"],
+			"http": [{"route": [{"destination": {"host": "reviews.bookinfo.svc.cluster.local"}}]}]
+		}
+	}`, patches[kubernetes.VirtualServices])
+}
+
+func TestApplyTrafficWizardRollback(t *testing.T) {
+	assert := assert.New(t)
+
+	labels := map[string]string{KialiWizardLabel: models.WeightedRoutingWizard, managedByLabel: managedByKiali}
+	before := fakeWizardObject(labels)
+	before.Spec = map[string]interface{}{"host": "reviews", "subsets": []interface{}{map[string]interface{}{"name": "v1"}}}
+	after := fakeWizardObject(labels)
+	after.Spec = map[string]interface{}{"host": "reviews", "subsets": []interface{}{map[string]interface{}{"name": "v1"}, map[string]interface{}{"name": "v2"}}}
+
+	k8s := mockWizardService()
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.DestinationRules, "reviews").Return(before, nil).Once()
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.DestinationRules, "reviews").Return(after, nil)
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.VirtualServices, "reviews").Return(fakeWizardObject(labels), nil)
+	updates := []string{}
+	k8s.On("UpdateIstioObject", mock.Anything, "bookinfo", kubernetes.DestinationRules, "reviews", mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		updates = append(updates, args.String(4))
+	}).Return(after, nil)
+	k8s.On("UpdateIstioObject", mock.Anything, "bookinfo", kubernetes.VirtualServices, "reviews", mock.AnythingOfType("string")).Return(fakeWizardObject(nil), errors.NewBadRequest("invalid"))
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// The DestinationRule updated is restored when the VirtualService fails
+	layer := NewWithBackends(k8s, nil, nil)
+	_, err := layer.IstioConfig.ApplyWeightedRouting("bookinfo", "reviews", models.WeightedRouting{
+		Weights: []models.VersionWeight{{Version: "v1", Weight: 80}, {Version: "v2", Weight: 20}},
+	})
+	assert.True(errors.IsBadRequest(err))
+	assert.Len(updates, 2)
+	assert.JSONEq(`{"spec": {"subsets": [{"name": "v1"}]}}`, updates[1])
+
+	// The VirtualService is applied first when subsets are removed, so it doesn't route to missing subsets
+	before.Spec["subsets"] = []interface{}{map[string]interface{}{"name": "v1"}, map[string]interface{}{"name": "v3"}}
+	k8s = mockWizardService()
+	k8s.On("GetIstioObject", "bookinfo", mock.AnythingOfType("string"), "reviews").Return(before, nil)
+	applied := []string{}
+	k8s.On("UpdateIstioObject", mock.Anything, "bookinfo", mock.AnythingOfType("string"), "reviews", mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		applied = append(applied, args.String(2))
+	}).Return(before, nil)
+	layer = NewWithBackends(k8s, nil, nil)
+	_, err = layer.IstioConfig.ApplyWeightedRouting("bookinfo", "reviews", models.WeightedRouting{
+		Weights: []models.VersionWeight{{Version: "v1", Weight: 80}, {Version: "v2", Weight: 20}},
+	})
+	assert.NoError(err)
+	assert.Equal([]string{kubernetes.VirtualServices, kubernetes.DestinationRules}, applied)
+}
+
+func TestApplyTrafficWizardConflict(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockWizardService()
+	k8s.On("GetIstioObject", "bookinfo", kubernetes.DestinationRules, "reviews").Return(fakeWizardObject(nil), nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	_, err := layer.IstioConfig.ApplyTrafficMirroring("bookinfo", "reviews", models.TrafficMirroring{Version: "v1", MirrorVersion: "v2"})
+	assert.True(errors.IsConflict(err))
+	k8s.AssertNotCalled(t, "UpdateIstioObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRequestRoutingMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	match, err := requestRoutingMatch(models.RequestRoutingRule{Header: "end-user", Value: "jason", Version: "v2"})
+	assert.NoError(err)
+	assert.Equal(map[string]interface{}{"headers": map[string]interface{}{"end-user": map[string]interface{}{"exact": "jason"}}}, match)
+
+	match, err = requestRoutingMatch(models.RequestRoutingRule{Cookie: "user", Value: "j.son", Version: "v2"})
+	assert.NoError(err)
+	assert.Equal(map[string]interface{}{"headers": map[string]interface{}{"cookie": map[string]interface{}{"regex": `^(.*?;)?(user=j\.son)(;.*)?$`}}}, match)
+
+	_, err = requestRoutingMatch(models.RequestRoutingRule{Header: "end-user", Cookie: "user", Value: "jason"})
+	assert.True(errors.IsBadRequest(err))
+	_, err = requestRoutingMatch(models.RequestRoutingRule{Header: "end-user", Value: "(", Regex: true})
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestDeleteTrafficWizard(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	vs := fakeWizardObject(map[string]string{KialiWizardLabel: models.FaultInjectionWizard, managedByLabel: managedByKiali})
+	vs.Spec = map[string]interface{}{
+		"hosts": []interface{}{"reviews.bookinfo.svc.cluster.local"},
+		"http":  []interface{}{map[string]interface{}{"route": []interface{}{map[string]interface{}{"destination": map[string]interface{}{"host": "reviews.bookinfo.svc.cluster.local"}}}}},
+	}
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.VirtualServices, wizardSelector).Return([]kubernetes.IstioObject{vs}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", kubernetes.DestinationRules, wizardSelector).Return([]kubernetes.IstioObject{}, nil)
+	var deleted []string
+	k8s.On("DeleteIstioObject", mock.Anything, "bookinfo", mock.AnythingOfType("string"), "reviews").Run(func(args mock.Arguments) {
+		deleted = append(deleted, args.String(2))
+	}).Return(nil)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	layer := NewWithBackends(k8s, nil, nil)
+	assert.NoError(layer.IstioConfig.DeleteTrafficWizard("bookinfo", "reviews"))
+	assert.Equal([]string{kubernetes.VirtualServices}, deleted)
+
+	err := layer.IstioConfig.DeleteTrafficWizard("bookinfo", "ratings")
+	assert.True(errors.IsNotFound(err))
+}
+
+// mockWizardService returns a mock with the reviews service and pods of versions v1 and v2
+func mockWizardService() *kubetest.K8SClientMock {
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(kubetest.FakeNamespace("bookinfo"), nil)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+		Spec:       core_v1.ServiceSpec{Selector: map[string]string{"app": "reviews"}},
+	}, nil)
+	pods := []core_v1.Pod{}
+	for _, version := range []string{"v2", "v1", "v2"} {
+		pods = append(pods, core_v1.Pod{ObjectMeta: meta_v1.ObjectMeta{Labels: map[string]string{"app": "reviews", "version": version}}})
+	}
+	k8s.On("GetPods", "bookinfo", "app=reviews").Return(pods, nil)
+	return k8s
+}
+
+func fakeWizardObject(labels map[string]string) *kubernetes.GenericIstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo", Labels: labels},
+		Spec:       map[string]interface{}{},
+	}
+}
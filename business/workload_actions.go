@@ -0,0 +1,213 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// restartedAtAnnotation is the annotation of the pod template bumped by kubectl rollout restart
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// Workload types supporting each lifecycle operation
+var (
+	restartableWorkloadTypes = []string{kubernetes.DeploymentType, kubernetes.DeploymentConfigType, kubernetes.StatefulSetType, kubernetes.DaemonSetType}
+	scalableWorkloadTypes    = []string{kubernetes.DeploymentType, kubernetes.DeploymentConfigType, kubernetes.StatefulSetType, kubernetes.ReplicaSetType, kubernetes.ReplicationControllerType, kubernetes.RolloutType}
+	pausableWorkloadTypes    = []string{kubernetes.DeploymentType, kubernetes.DeploymentConfigType, kubernetes.RolloutType}
+)
+
+// RestartWorkload triggers a rolling restart of the pods of a workload, changing an annotation of its pod template
+// the same way kubectl rollout restart does
+func (in *WorkloadService) RestartWorkload(namespace, workloadName, workloadType string) (*models.Workload, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "RestartWorkload")
+	defer promtimer.ObserveNow(&err)
+
+	if workloadType, err = in.workloadTypeFor(namespace, workloadName, workloadType, "restarted", restartableWorkloadTypes); err != nil {
+		return nil, err
+	}
+	// As kubectl, a paused Deployment is not restarted: the change would wait for the rollout to be resumed
+	if workloadType == kubernetes.DeploymentType {
+		dep, e := in.k8s.GetDeployment(namespace, workloadName)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		if dep.Spec.Paused {
+			err = errors.NewBadRequest(fmt.Sprintf("Deployment [%s] is paused, it must be resumed before being restarted", workloadName))
+			return nil, err
+		}
+	}
+
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: util.Clock.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+	var workload *models.Workload
+	workload, err = in.patchWorkload(namespace, workloadName, workloadType, patch)
+	return workload, err
+}
+
+// ScaleWorkload sets the number of desired replicas of a workload
+func (in *WorkloadService) ScaleWorkload(namespace, workloadName, workloadType string, replicas int32) (*models.Workload, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "ScaleWorkload")
+	defer promtimer.ObserveNow(&err)
+
+	if replicas < 0 {
+		err = errors.NewBadRequest(fmt.Sprintf("invalid replicas [%d], it must not be negative", replicas))
+		return nil, err
+	}
+	if workloadType, err = in.workloadTypeFor(namespace, workloadName, workloadType, "scaled", scalableWorkloadTypes); err != nil {
+		return nil, err
+	}
+
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+		},
+	}
+	var workload *models.Workload
+	workload, err = in.patchWorkload(namespace, workloadName, workloadType, patch)
+	return workload, err
+}
+
+// PauseWorkload pauses, or resumes, the rollout of the changes of a Deployment, a DeploymentConfig or an Argo Rollout
+func (in *WorkloadService) PauseWorkload(namespace, workloadName, workloadType string, paused bool) (*models.Workload, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "PauseWorkload")
+	defer promtimer.ObserveNow(&err)
+
+	action := "paused"
+	if !paused {
+		action = "resumed"
+	}
+	if workloadType, err = in.workloadTypeFor(namespace, workloadName, workloadType, action, pausableWorkloadTypes); err != nil {
+		return nil, err
+	}
+
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"paused": paused,
+		},
+	}
+	var workload *models.Workload
+	workload, err = in.patchWorkload(namespace, workloadName, workloadType, patch)
+	return workload, err
+}
+
+// GetWorkloadRollout returns the progress of the rollout of a workload and the history of its revisions
+func (in *WorkloadService) GetWorkloadRollout(namespace, workloadName, workloadType string) (*models.WorkloadRollout, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetWorkloadRollout")
+	defer promtimer.ObserveNow(&err)
+
+	if workloadType, err = in.workloadTypeFor(namespace, workloadName, workloadType, "rolled out", restartableWorkloadTypes); err != nil {
+		return nil, err
+	}
+
+	// The rollout is read from the API instead of the cache, as it changes during the rollout
+	rollout := &models.WorkloadRollout{}
+	switch workloadType {
+	case kubernetes.DeploymentType:
+		dep, e := in.k8s.GetDeployment(namespace, workloadName)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		rs, e := in.k8s.GetReplicaSets(namespace)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		rollout.ParseDeployment(dep, rs)
+	case kubernetes.DeploymentConfigType:
+		dc, e := in.k8s.GetDeploymentConfig(namespace, workloadName)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		rcs, e := in.k8s.GetReplicationControllers(namespace)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		rollout.ParseDeploymentConfig(dc, rcs)
+	case kubernetes.StatefulSetType:
+		ss, e := in.k8s.GetStatefulSet(namespace, workloadName)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		crs, e := in.k8s.GetControllerRevisions(namespace)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		rollout.ParseStatefulSet(ss, crs)
+	case kubernetes.DaemonSetType:
+		ds, e := in.k8s.GetDaemonSet(namespace, workloadName)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		crs, e := in.k8s.GetControllerRevisions(namespace)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		rollout.ParseDaemonSet(ds, crs)
+	}
+	return rollout, nil
+}
+
+// workloadTypeFor checks the access to the namespace and returns the type of the workload, fetching it when the type
+// is not given. A workload whose type is not one of the supported types is a bad request.
+func (in *WorkloadService) workloadTypeFor(namespace, workloadName, workloadType, action string, supportedTypes []string) (string, error) {
+	if workloadType == "" {
+		workload, err := fetchWorkload(in.businessLayer, namespace, workloadName, workloadType)
+		if err != nil {
+			return "", err
+		}
+		workloadType = workload.Type
+	} else if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return "", err
+	}
+	for _, t := range supportedTypes {
+		if t == workloadType && isWorkloadIncluded(t) {
+			return workloadType, nil
+		}
+	}
+	return "", errors.NewBadRequest(fmt.Sprintf("workload [%s] of type [%s] cannot be %s", workloadName, workloadType, action))
+}
+
+// patchWorkload applies a merge patch to a workload, then fetches the whole workload
+func (in *WorkloadService) patchWorkload(namespace, workloadName, workloadType string, patch interface{}) (*models.Workload, error) {
+	jsonPatch, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	if err = in.k8s.UpdateWorkload(namespace, workloadName, workloadType, string(jsonPatch)); err != nil {
+		return nil, err
+	}
+
+	// Cache is stopped after a Create/Update/Delete operation to force a refresh
+	if kialiCache != nil {
+		kialiCache.RefreshNamespace(namespace)
+	}
+	return in.GetWorkload(namespace, workloadName, workloadType, true)
+}
@@ -0,0 +1,188 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	osapps_v1 "github.com/openshift/api/apps/v1"
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func mockDeploymentWorkload(dep *apps_v1.Deployment) *kubetest.K8SClientMock {
+	notfound := errors.NewNotFound(schema.GroupResource{Group: "test-group", Resource: "test-resource"}, "not found")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", "Namespace", "details-v1").Return(dep, nil)
+	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetDaemonSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.DaemonSet{}, notfound)
+	k8s.On("IsArgoRolloutsApi").Return(false)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsSyncedWithDeployments(), nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetServices", mock.AnythingOfType("string"), mock.Anything).Return([]core_v1.Service{}, nil)
+	return k8s
+}
+
+func TestRestartWorkload(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockDeploymentWorkload(&FakeDepSyncedWithRS()[0])
+	patch := `{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":"2020-06-01T10:00:00Z"}}}}}`
+	k8s.On("UpdateWorkload", "Namespace", "details-v1", "Deployment", patch).Return(nil, nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
+	config.Set(config.NewConfig())
+	util.Clock = util.ClockMock{Time: time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)}
+
+	svc := setupWorkloadService(k8s)
+	workload, err := svc.RestartWorkload("Namespace", "details-v1", "")
+
+	assert.NoError(err)
+	assert.Equal("details-v1", workload.Name)
+	k8s.AssertCalled(t, "UpdateWorkload", "Namespace", "details-v1", "Deployment", patch)
+}
+
+func TestRestartPausedDeployment(t *testing.T) {
+	assert := assert.New(t)
+
+	dep := FakeDepSyncedWithRS()[0]
+	dep.Spec.Paused = true
+	k8s := mockDeploymentWorkload(&dep)
+	config.Set(config.NewConfig())
+
+	svc := setupWorkloadService(k8s)
+	_, err := svc.RestartWorkload("Namespace", "details-v1", "Deployment")
+
+	assert.True(errors.IsBadRequest(err))
+	k8s.AssertNotCalled(t, "UpdateWorkload", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScaleWorkload(t *testing.T) {
+	assert := assert.New(t)
+
+	k8s := mockDeploymentWorkload(&FakeDepSyncedWithRS()[0])
+	k8s.On("UpdateWorkload", "Namespace", "details-v1", "Deployment", `{"spec":{"replicas":3}}`).Return(nil, nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
+	config.Set(config.NewConfig())
+
+	svc := setupWorkloadService(k8s)
+	_, err := svc.ScaleWorkload("Namespace", "details-v1", "Deployment", 3)
+	assert.NoError(err)
+	k8s.AssertCalled(t, "UpdateWorkload", "Namespace", "details-v1", "Deployment", `{"spec":{"replicas":3}}`)
+
+	_, err = svc.ScaleWorkload("Namespace", "details-v1", "Deployment", -1)
+	assert.True(errors.IsBadRequest(err))
+
+	// Pods are not scaled, and StatefulSets have no pausable rollout
+	_, err = svc.ScaleWorkload("Namespace", "details-v1", "Pod", 3)
+	assert.True(errors.IsBadRequest(err))
+	_, err = svc.PauseWorkload("Namespace", "details-v1", "StatefulSet", true)
+	assert.True(errors.IsBadRequest(err))
+}
+
+func TestGetDeploymentRollout(t *testing.T) {
+	assert := assert.New(t)
+
+	replicas := int32(2)
+	dep := FakeDepSyncedWithRS()[0]
+	dep.Annotations = map[string]string{"deployment.kubernetes.io/revision": "2"}
+	dep.Spec.Replicas = &replicas
+	dep.Status = apps_v1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 2, ReadyReplicas: 2, AvailableReplicas: 2}
+	owner := []meta_v1.OwnerReference{{Kind: "Deployment", Name: "details-v1"}}
+	replicaSet := func(name, revision, image string, replicas int32) apps_v1.ReplicaSet {
+		return apps_v1.ReplicaSet{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:            name,
+				OwnerReferences: owner,
+				Annotations:     map[string]string{"deployment.kubernetes.io/revision": revision},
+			},
+			Spec: apps_v1.ReplicaSetSpec{
+				Template: core_v1.PodTemplateSpec{Spec: core_v1.PodSpec{Containers: []core_v1.Container{{Image: image}}}},
+			},
+			Status: apps_v1.ReplicaSetStatus{Replicas: replicas},
+		}
+	}
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "bookinfo").Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", "bookinfo", "details-v1").Return(&dep, nil)
+	k8s.On("GetReplicaSets", "bookinfo").Return([]apps_v1.ReplicaSet{
+		replicaSet("details-v1-1", "1", "details:1.0", 1),
+		replicaSet("details-v1-2", "2", "details:2.0", 2),
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1-1"}},
+	}, nil)
+	config.Set(config.NewConfig())
+
+	svc := setupWorkloadService(k8s)
+	rollout, err := svc.GetWorkloadRollout("bookinfo", "details-v1", "Deployment")
+
+	assert.NoError(err)
+	assert.Equal(models.RolloutProgressing, rollout.Status)
+	assert.Equal("1 old replicas are pending termination", rollout.Message)
+	assert.Equal("2", rollout.Revision)
+	assert.Len(rollout.History, 2)
+	assert.Equal("2", rollout.History[0].Revision)
+	assert.True(rollout.History[0].Current)
+	assert.Equal([]string{"details:2.0"}, rollout.History[0].Images)
+	assert.Equal("details-v1-1", rollout.History[1].Name)
+	assert.False(rollout.History[1].Current)
+}
+
+func TestGetStatefulSetRollout(t *testing.T) {
+	assert := assert.New(t)
+
+	replicas := int32(2)
+	ss := &apps_v1.StatefulSet{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "ratings-db"},
+		Spec:       apps_v1.StatefulSetSpec{Replicas: &replicas},
+		Status:     apps_v1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 1, CurrentRevision: "ratings-db-a", UpdateRevision: "ratings-db-b"},
+	}
+	owner := []meta_v1.OwnerReference{{Kind: "StatefulSet", Name: "ratings-db"}}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "bookinfo").Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetStatefulSet", "bookinfo", "ratings-db").Return(ss, nil)
+	k8s.On("GetControllerRevisions", "bookinfo").Return([]apps_v1.ControllerRevision{
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "ratings-db-a", OwnerReferences: owner},
+			Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"db","image":"mysql:5"}]}}}}`)},
+			Revision:   1,
+		},
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "ratings-db-b", OwnerReferences: owner},
+			Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"db","image":"mysql:8"}]}}}}`)},
+			Revision:   2,
+		},
+	}, nil)
+	config.Set(config.NewConfig())
+
+	svc := setupWorkloadService(k8s)
+	rollout, err := svc.GetWorkloadRollout("bookinfo", "ratings-db", "StatefulSet")
+
+	assert.NoError(err)
+	assert.Equal(models.RolloutProgressing, rollout.Status)
+	assert.Equal("Waiting for the replicas to be updated to revision ratings-db-b", rollout.Message)
+	assert.Equal("2", rollout.Revision)
+	assert.Len(rollout.History, 2)
+	assert.Equal([]string{"mysql:8"}, rollout.History[0].Images)
+	assert.True(rollout.History[0].Current)
+}
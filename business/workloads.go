@@ -44,11 +44,22 @@ type LogEntry struct {
 	Severity      string `json:"severity,omitempty"`
 	Timestamp     string `json:"timestamp,omitempty"`
 	TimestampUnix int64  `json:"timestampUnix,omitempty"`
+
+	// Pod and Container of the entry, when the logs of several containers are aggregated
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+
+	// Fields parsed from the message, i.e. the code of an access log
+	Fields map[string]string `json:"fields,omitempty"`
+
+	// time is the timestamp with the precision of the log line, to merge the logs of several containers
+	time time.Time
 }
 
 // LogOptions holds query parameter values
 type LogOptions struct {
 	Duration *time.Duration
+	Filter   LogFilter
 	core_v1.PodLogOptions
 }
 
@@ -103,8 +114,10 @@ func (in *WorkloadService) GetWorkload(namespace string, workloadName string, wo
 	}
 
 	var runtimes []models.Runtime
+	var events models.Events
+	var resources *models.WorkloadResources
 	wg := sync.WaitGroup{}
-	wg.Add(1)
+	wg.Add(3)
 	go func() {
 		defer wg.Done()
 		conf := config.Get()
@@ -113,6 +126,17 @@ func (in *WorkloadService) GetWorkload(namespace string, workloadName string, wo
 		runtimes = NewDashboardsService().GetCustomDashboardRefs(namespace, app, version, workload.Pods)
 	}()
 
+	go func() {
+		defer wg.Done()
+		objects := workloadEventObjects(workload, fetchReplicaSetsForEvents(in.businessLayer, namespace))
+		events = parseEvents(fetchObjectEvents(in.businessLayer, namespace, objects), objects)
+	}()
+
+	go func() {
+		defer wg.Done()
+		resources = fetchContainerResources(in.prom, namespace, workload.Pods)
+	}()
+
 	if includeServices {
 		var services []core_v1.Service
 		var err error
@@ -133,6 +157,8 @@ func (in *WorkloadService) GetWorkload(namespace string, workloadName string, wo
 
 	wg.Wait()
 	workload.Runtimes = runtimes
+	workload.Events = events
+	workload.Resources = resources
 
 	return workload, nil
 }
@@ -157,6 +183,19 @@ func (in *WorkloadService) UpdateWorkload(namespace string, workloadName string,
 	return in.GetWorkload(namespace, workloadName, workloadType, includeServices)
 }
 
+// GetWorkloadPods returns the current pods of a workload
+func (in *WorkloadService) GetWorkloadPods(namespace, workloadName string) (models.Pods, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetWorkloadPods")
+	defer promtimer.ObserveNow(&err)
+
+	workload, err := fetchWorkload(in.businessLayer, namespace, workloadName, "")
+	if err != nil {
+		return nil, err
+	}
+	return workload.Pods, nil
+}
+
 func (in *WorkloadService) GetPods(namespace string, labelSelector string) (models.Pods, error) {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "WorkloadService", "GetPods")
@@ -192,6 +231,8 @@ func (in *WorkloadService) GetPod(namespace, name string) (*models.Pod, error) {
 	}
 	pod := models.Pod{}
 	pod.Parse(p)
+	objects := podEventObjects(name)
+	pod.Events = parseEvents(fetchObjectEvents(in.businessLayer, namespace, objects), objects)
 	return &pod, nil
 }
 
@@ -247,6 +288,11 @@ func (in *WorkloadService) getParsedLogs(namespace, name string, opts *LogOption
 		k8sOpts.TailLines = nil
 	}
 
+	parseEntry, err := opts.Filter.entryParser()
+	if err != nil {
+		return nil, err
+	}
+
 	podLog, err := in.k8s.GetPodLogs(namespace, name, &k8sOpts)
 
 	if err != nil {
@@ -263,59 +309,28 @@ func (in *WorkloadService) getParsedLogs(namespace, name string, opts *LogOption
 	}
 
 	for _, line := range lines {
-		entry := LogEntry{
-			Message:       "",
-			Timestamp:     "",
-			TimestampUnix: 0,
-			Severity:      "INFO",
-		}
-
-		splitted := strings.SplitN(line, " ", 2)
-		if len(splitted) != 2 {
-			log.Debugf("Skipping unexpected log line [%s]", line)
+		entry, parsed, ok := parseLogLine(line)
+		if !ok {
 			continue
 		}
 
-		// k8s promises RFC3339 or RFC3339Nano timestamp, ensure RFC3339
-		splittedTimestamp := strings.Split(splitted[0], ".")
-		if len(splittedTimestamp) == 1 {
-			entry.Timestamp = splittedTimestamp[0]
-		} else {
-			entry.Timestamp = fmt.Sprintf("%sZ", splittedTimestamp[0])
-		}
-
-		entry.Message = strings.TrimSpace(splitted[1])
-		if entry.Message == "" {
-			log.Debugf("Skipping empty log line [%s]", line)
-			continue
+		if startTime == nil {
+			startTime = &parsed
 		}
 
-		parsed, err := time.Parse(time.RFC3339, entry.Timestamp)
-		if err == nil {
-			if startTime == nil {
-				startTime = &parsed
+		if isBounded {
+			if endTime == nil {
+				end := parsed.Add(*opts.Duration)
+				endTime = &end
 			}
 
-			if isBounded {
-				if endTime == nil {
-					end := parsed.Add(*opts.Duration)
-					endTime = &end
-				}
-
-				if parsed.After(*endTime) {
-					break
-				}
+			if parsed.After(*endTime) {
+				break
 			}
-
-			entry.TimestampUnix = parsed.Unix()
-		} else {
-			log.Debugf("Failed to parse log timestamp (skipping) [%s], %s", entry.Timestamp, err.Error())
-			continue
 		}
 
-		severity := severityRegexp.FindString(line)
-		if severity != "" {
-			entry.Severity = strings.ToUpper(severity)
+		if !parseEntry(&entry) {
+			continue
 		}
 
 		entries = append(entries, entry)
@@ -332,6 +347,53 @@ func (in *WorkloadService) getParsedLogs(namespace, name string, opts *LogOption
 	return &message, err
 }
 
+// parseLogLine parses a log line prefixed by its timestamp. It returns false for the lines to skip.
+func parseLogLine(line string) (LogEntry, time.Time, bool) {
+	entry := LogEntry{
+		Message:       "",
+		Timestamp:     "",
+		TimestampUnix: 0,
+		Severity:      "INFO",
+	}
+
+	splitted := strings.SplitN(line, " ", 2)
+	if len(splitted) != 2 {
+		log.Debugf("Skipping unexpected log line [%s]", line)
+		return entry, time.Time{}, false
+	}
+
+	// k8s promises RFC3339 or RFC3339Nano timestamp, ensure RFC3339
+	splittedTimestamp := strings.Split(splitted[0], ".")
+	if len(splittedTimestamp) == 1 {
+		entry.Timestamp = splittedTimestamp[0]
+	} else {
+		entry.Timestamp = fmt.Sprintf("%sZ", splittedTimestamp[0])
+	}
+
+	entry.Message = strings.TrimSpace(splitted[1])
+	if entry.Message == "" {
+		log.Debugf("Skipping empty log line [%s]", line)
+		return entry, time.Time{}, false
+	}
+
+	parsed, err := time.Parse(time.RFC3339, entry.Timestamp)
+	if err != nil {
+		log.Debugf("Failed to parse log timestamp (skipping) [%s], %s", entry.Timestamp, err.Error())
+		return entry, time.Time{}, false
+	}
+	entry.TimestampUnix = parsed.Unix()
+	if entry.time, err = time.Parse(time.RFC3339Nano, splitted[0]); err != nil {
+		entry.time = parsed
+	}
+
+	severity := severityRegexp.FindString(line)
+	if severity != "" {
+		entry.Severity = strings.ToUpper(severity)
+	}
+
+	return entry, parsed, true
+}
+
 // GetPodLogs returns pod logs given the provided options
 func (in *WorkloadService) GetPodLogs(namespace, name string, opts *LogOptions) (*PodLog, error) {
 	return in.getParsedLogs(namespace, name, opts)
@@ -344,6 +406,8 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 	var repset []apps_v1.ReplicaSet
 	var depcon []osapps_v1.DeploymentConfig
 	var fulset []apps_v1.StatefulSet
+	var daeset []apps_v1.DaemonSet
+	var rollouts []kubernetes.ArgoRollout
 	var jbs []batch_v1.Job
 	var conjbs []batch_v1beta1.CronJob
 
@@ -356,8 +420,8 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 	}
 
 	wg := sync.WaitGroup{}
-	wg.Add(8)
-	errChan := make(chan error, 8)
+	wg.Add(10)
+	errChan := make(chan error, 10)
 
 	go func() {
 		defer wg.Done()
@@ -447,6 +511,35 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 		}
 	}()
 
+	go func() {
+		defer wg.Done()
+		var err error
+		if isWorkloadIncluded(kubernetes.DaemonSetType) {
+			if IsNamespaceCached(namespace) {
+				daeset, err = kialiCache.GetDaemonSets(namespace)
+			} else {
+				daeset, err = layer.k8s.GetDaemonSets(namespace)
+			}
+			if err != nil {
+				log.Errorf("Error fetching DaemonSets per namespace %s: %s", namespace, err)
+				errChan <- err
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		var err error
+		if layer.k8s.IsArgoRolloutsApi() && isWorkloadIncluded(kubernetes.RolloutType) {
+			rollouts, err = layer.k8s.GetArgoRollouts(namespace)
+			if err != nil {
+				// The API may be installed without the role allowing to list Rollouts, their pods are still shown
+				log.Errorf("Error fetching Rollouts per namespace %s, the workloads continue without them: %s", namespace, err)
+				rollouts = []kubernetes.ArgoRollout{}
+			}
+		}
+	}()
+
 	go func() {
 		defer wg.Done()
 		var err error
@@ -651,6 +744,24 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 			controllers[fs.Name] = "StatefulSet"
 		}
 	}
+	for _, ds := range daeset {
+		selectorCheck := true
+		if selector != nil {
+			selectorCheck = selector.Matches(labels.Set(ds.Spec.Template.Labels))
+		}
+		if _, exist := controllers[ds.Name]; !exist && selectorCheck {
+			controllers[ds.Name] = "DaemonSet"
+		}
+	}
+	for _, ro := range rollouts {
+		selectorCheck := true
+		if selector != nil {
+			selectorCheck = selector.Matches(labels.Set(ro.Spec.Template.Labels))
+		}
+		if _, exist := controllers[ro.Name]; !exist && selectorCheck {
+			controllers[ro.Name] = "Rollout"
+		}
+	}
 
 	// Build workloads from controllers
 	var cnames []string
@@ -757,6 +868,47 @@ func fetchWorkloads(layer *Layer, namespace string, labelSelector string) (model
 				log.Errorf("Workload %s is not found as StatefulSet", cname)
 				cnFound = false
 			}
+		case "DaemonSet":
+			found := false
+			iFound := -1
+			for i, ds := range daeset {
+				if ds.Name == cname {
+					found = true
+					iFound = i
+					break
+				}
+			}
+			if found {
+				selector := labels.Set(daeset[iFound].Spec.Template.Labels).AsSelector()
+				w.SetPods(kubernetes.FilterPodsForSelector(selector, pods))
+				w.ParseDaemonSet(&daeset[iFound])
+			} else {
+				// Excluded DaemonSets, or not yet listed, are resolved from the pods they own
+				cPods := kubernetes.FilterPodsForController(cname, ctype, pods)
+				w.SetPods(cPods)
+				w.ParsePods(cname, ctype, cPods)
+			}
+		case "Rollout":
+			found := false
+			iFound := -1
+			for i, ro := range rollouts {
+				if ro.Name == cname {
+					found = true
+					iFound = i
+					break
+				}
+			}
+			if found {
+				// Pods of the stable and the canary revisions share the labels of the template
+				selector := labels.Set(rollouts[iFound].Spec.Template.Labels).AsSelector()
+				w.SetPods(kubernetes.FilterPodsForSelector(selector, pods))
+				w.ParseArgoRollout(&rollouts[iFound], repset)
+			} else {
+				// Excluded Rollouts, or not listed, are resolved from the pods of their ReplicaSets
+				cPods := filterPodsForRollout(cname, repset, pods)
+				w.SetPods(cPods)
+				w.ParsePods(cname, ctype, cPods)
+			}
 		case "Pod":
 			found := false
 			iFound := -1
@@ -842,6 +994,8 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 	var repset []apps_v1.ReplicaSet
 	var depcon *osapps_v1.DeploymentConfig
 	var fulset *apps_v1.StatefulSet
+	var daeset *apps_v1.DaemonSet
+	var rollout *kubernetes.ArgoRollout
 	var jbs []batch_v1.Job
 	var conjbs []batch_v1beta1.CronJob
 
@@ -857,8 +1011,8 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 	}
 
 	wg := sync.WaitGroup{}
-	wg.Add(8)
-	errChan := make(chan error, 8)
+	wg.Add(10)
+	errChan := make(chan error, 10)
 
 	// Pods are always fetched for all workload types
 	go func() {
@@ -904,7 +1058,8 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 	go func() {
 		defer wg.Done()
 		// Check if workloadType is passed
-		if workloadType != "" && workloadType != kubernetes.ReplicaSetType {
+		// ReplicaSets are also the revisions of a Rollout
+		if workloadType != "" && workloadType != kubernetes.ReplicaSetType && workloadType != kubernetes.RolloutType {
 			return
 		}
 		var err error
@@ -971,6 +1126,44 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 		}
 	}()
 
+	go func() {
+		defer wg.Done()
+		// Check if workloadType is passed
+		if workloadType != "" && workloadType != kubernetes.DaemonSetType {
+			return
+		}
+		var err error
+		if isWorkloadIncluded(kubernetes.DaemonSetType) {
+			if IsNamespaceCached(namespace) {
+				daeset, err = kialiCache.GetDaemonSet(namespace, workloadName)
+			} else {
+				daeset, err = layer.k8s.GetDaemonSet(namespace, workloadName)
+			}
+			if err != nil {
+				daeset = nil
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		// Check if workloadType is passed
+		if workloadType != "" && workloadType != kubernetes.RolloutType {
+			return
+		}
+		var err error
+		if layer.k8s.IsArgoRolloutsApi() && isWorkloadIncluded(kubernetes.RolloutType) {
+			rollout, err = layer.k8s.GetArgoRollout(namespace, workloadName)
+			if err != nil {
+				if !errors.IsNotFound(err) {
+					// The API may be installed without the role allowing to get Rollouts, their pods are still shown
+					log.Errorf("Error fetching Rollout per namespace %s and name %s, the workload continues without it: %s", namespace, workloadName, err)
+				}
+				rollout = nil
+			}
+		}
+	}()
+
 	go func() {
 		defer wg.Done()
 		// Check if workloadType is passed
@@ -1155,6 +1348,16 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 			controllers[fulset.Name] = "StatefulSet"
 		}
 	}
+	if daeset != nil {
+		if _, exist := controllers[daeset.Name]; !exist {
+			controllers[daeset.Name] = "DaemonSet"
+		}
+	}
+	if rollout != nil {
+		if _, exist := controllers[rollout.Name]; !exist {
+			controllers[rollout.Name] = "Rollout"
+		}
+	}
 
 	// Build workload from controllers
 
@@ -1230,6 +1433,28 @@ func fetchWorkload(layer *Layer, namespace string, workloadName string, workload
 				log.Errorf("Workload %s is not found as StatefulSet", workloadName)
 				cnFound = false
 			}
+		case "DaemonSet":
+			if daeset != nil && daeset.Name == workloadName {
+				selector := labels.Set(daeset.Spec.Template.Labels).AsSelector()
+				w.SetPods(kubernetes.FilterPodsForSelector(selector, pods))
+				w.ParseDaemonSet(daeset)
+			} else {
+				// Excluded DaemonSets, or not yet listed, are resolved from the pods they own
+				cPods := kubernetes.FilterPodsForController(workloadName, ctype, pods)
+				w.SetPods(cPods)
+				w.ParsePods(workloadName, ctype, cPods)
+			}
+		case "Rollout":
+			if rollout != nil && rollout.Name == workloadName {
+				selector := labels.Set(rollout.Spec.Template.Labels).AsSelector()
+				w.SetPods(kubernetes.FilterPodsForSelector(selector, pods))
+				w.ParseArgoRollout(rollout, repset)
+			} else {
+				// Excluded Rollouts, or not listed, are resolved from the pods of their ReplicaSets
+				cPods := filterPodsForRollout(workloadName, repset, pods)
+				w.SetPods(cPods)
+				w.ParsePods(workloadName, ctype, cPods)
+			}
 		case "Pod":
 			found := false
 			iFound := -1
@@ -1324,6 +1549,8 @@ func updateWorkload(layer *Layer, namespace string, workloadName string, workloa
 		kubernetes.ReplicationControllerType,
 		kubernetes.DeploymentConfigType,
 		kubernetes.StatefulSetType,
+		kubernetes.DaemonSetType,
+		kubernetes.RolloutType,
 		kubernetes.JobType,
 		kubernetes.CronJobType,
 		kubernetes.PodType,
@@ -1380,6 +1607,7 @@ func updateWorkload(layer *Layer, namespace string, workloadName string, workloa
 // But Istio only identifies one controller as workload (it doesn't note which one).
 // Kiali can select one on the list of workloads and other in the details and this should be consistent.
 var controllerOrder = map[string]int{
+	"Rollout":               7,
 	"Deployment":            6,
 	"DeploymentConfig":      5,
 	"ReplicaSet":            4,
@@ -1406,6 +1634,19 @@ func controllerPriority(type1, type2 string) string {
 	}
 }
 
+// filterPodsForRollout returns the pods of a Rollout, owned by the ReplicaSets of its revisions
+func filterPodsForRollout(name string, replicaSets []apps_v1.ReplicaSet, pods []core_v1.Pod) []core_v1.Pod {
+	rolloutPods := kubernetes.FilterPodsForController(name, kubernetes.RolloutType, pods)
+	for _, rs := range replicaSets {
+		for _, ref := range rs.OwnerReferences {
+			if ref.Controller != nil && *ref.Controller && ref.Kind == kubernetes.RolloutType && ref.Name == name {
+				rolloutPods = append(rolloutPods, kubernetes.FilterPodsForController(rs.Name, kubernetes.ReplicaSetType, pods)...)
+			}
+		}
+	}
+	return rolloutPods
+}
+
 // GetWorkloadAppName returns the "Application" name (app label) that relates to a workload
 func (in *WorkloadService) GetWorkloadAppName(namespace, workload string) (string, error) {
 	wkd, err := fetchWorkload(in.businessLayer, namespace, workload, "")
@@ -7,6 +7,7 @@ import (
 
 	osapps_v1 "github.com/openshift/api/apps/v1"
 	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	apps_v1 "k8s.io/api/apps/v1"
@@ -19,11 +20,13 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/prometheustest"
 )
 
 func setupWorkloadService(k8s *kubetest.K8SClientMock) WorkloadService {
 	prom := new(prometheustest.PromClientMock)
+	prom.On("GetContainerResources", mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(map[string]model.Vector{}, nil)
 	return WorkloadService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
 }
 
@@ -41,6 +44,8 @@ func TestGetWorkloadListFromDeployments(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Pod{}, nil)
@@ -83,6 +88,8 @@ func TestGetWorkloadListFromReplicaSets(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakeReplicaSets(), nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Pod{}, nil)
@@ -123,6 +130,8 @@ func TestGetWorkloadListFromReplicationControllers(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakeReplicationControllers(), nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Pod{}, nil)
@@ -164,6 +173,8 @@ func TestGetWorkloadListFromDeploymentConfigs(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Pod{}, nil)
@@ -205,6 +216,8 @@ func TestGetWorkloadListFromStatefulSets(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakeStatefulSets(), nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Pod{}, nil)
@@ -246,6 +259,8 @@ func TestGetWorkloadListFromDepRCPod(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakeRSSyncedWithPods(), nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsSyncedWithDeployments(), nil)
@@ -278,6 +293,8 @@ func TestGetWorkloadListFromPod(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsNoController(), nil)
@@ -310,6 +327,8 @@ func TestGetWorkloadListFromPods(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsFromDaemonSet(), nil)
@@ -328,6 +347,209 @@ func TestGetWorkloadListFromPods(t *testing.T) {
 	assert.Equal(true, workloads[0].VersionLabel)
 }
 
+func TestGetWorkloadListFromDaemonSets(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployments", mock.AnythingOfType("string")).Return([]apps_v1.Deployment{}, nil)
+	k8s.On("GetDeploymentConfigs", mock.AnythingOfType("string")).Return([]osapps_v1.DeploymentConfig{}, nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSets", mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string")).Return(FakeDaemonSets(), nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
+	k8s.On("GetJobs", mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsFromDaemonSet(), nil)
+
+	svc := setupWorkloadService(k8s)
+
+	workloadList, _ := svc.GetWorkloadList("Namespace")
+	workloads := workloadList.Workloads
+
+	assert.Equal(1, len(workloads))
+	assert.Equal("daemon-controller", workloads[0].Name)
+	assert.Equal("DaemonSet", workloads[0].Type)
+	assert.Equal(true, workloads[0].AppLabel)
+	assert.Equal(1, workloads[0].PodCount)
+}
+
+func TestGetWorkloadFromDaemonSet(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDaemonSet", "Namespace", "daemon-controller").Return(&FakeDaemonSets()[0], nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsFromDaemonSet(), nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
+
+	svc := setupWorkloadService(k8s)
+
+	workload, err := svc.GetWorkload("Namespace", "daemon-controller", "DaemonSet", false)
+
+	assert.NoError(err)
+	assert.Equal("DaemonSet", workload.Type)
+	assert.Equal(int32(3), workload.DesiredReplicas)
+	assert.Equal(int32(3), workload.CurrentReplicas)
+	assert.Equal(int32(2), workload.AvailableReplicas)
+	assert.Equal(true, *workload.IstioInjectionAnnotation)
+	assert.Equal(1, len(workload.Pods))
+}
+
+func TestGetWorkloadListFromArgoRollouts(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployments", mock.AnythingOfType("string")).Return([]apps_v1.Deployment{}, nil)
+	k8s.On("GetDeploymentConfigs", mock.AnythingOfType("string")).Return([]osapps_v1.DeploymentConfig{}, nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string")).Return(FakeArgoRolloutReplicaSets(), nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSets", mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(true)
+	k8s.On("GetArgoRollouts", mock.AnythingOfType("string")).Return(FakeArgoRollouts(), nil)
+	k8s.On("GetJobs", mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsFromArgoRollout(), nil)
+
+	svc := setupWorkloadService(k8s)
+
+	workloadList, _ := svc.GetWorkloadList("Namespace")
+	workloads := workloadList.Workloads
+
+	// The ReplicaSets of the revisions are not listed as workloads
+	assert.Equal(1, len(workloads))
+	assert.Equal("reviews", workloads[0].Name)
+	assert.Equal("Rollout", workloads[0].Type)
+	assert.Equal(2, workloads[0].PodCount)
+}
+
+func TestGetWorkloadListFromArgoRolloutsNotListed(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployments", mock.AnythingOfType("string")).Return([]apps_v1.Deployment{}, nil)
+	k8s.On("GetDeploymentConfigs", mock.AnythingOfType("string")).Return([]osapps_v1.DeploymentConfig{}, nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string")).Return(FakeArgoRolloutReplicaSets(), nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSets", mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
+	k8s.On("GetJobs", mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsFromArgoRollout(), nil)
+
+	svc := setupWorkloadService(k8s)
+
+	workloadList, err := svc.GetWorkloadList("Namespace")
+	workloads := workloadList.Workloads
+
+	// Without the Rollouts API, the Rollout is still a workload with the pods of its ReplicaSets
+	assert.NoError(err)
+	assert.Equal(1, len(workloads))
+	assert.Equal("reviews", workloads[0].Name)
+	assert.Equal("Rollout", workloads[0].Type)
+	assert.Equal(2, workloads[0].PodCount)
+}
+
+func TestGetWorkloadListFromArgoRolloutsForbidden(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployments", mock.AnythingOfType("string")).Return([]apps_v1.Deployment{}, nil)
+	k8s.On("GetDeploymentConfigs", mock.AnythingOfType("string")).Return([]osapps_v1.DeploymentConfig{}, nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string")).Return(FakeArgoRolloutReplicaSets(), nil)
+	k8s.On("GetReplicationControllers", mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
+	k8s.On("GetStatefulSets", mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(true)
+	forbidden := errors.NewForbidden(schema.GroupResource{Group: "argoproj.io", Resource: "rollouts"}, "", fmt.Errorf("no role"))
+	k8s.On("GetArgoRollouts", mock.AnythingOfType("string")).Return([]kubernetes.ArgoRollout{}, forbidden)
+	k8s.On("GetArgoRollout", "Namespace", "reviews").Return((*kubernetes.ArgoRollout)(nil), forbidden)
+	k8s.On("GetJobs", mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
+	k8s.On("GetCronJobs", mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsFromArgoRollout(), nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
+
+	svc := setupWorkloadService(k8s)
+
+	// A role without the Rollouts doesn't fail the workloads
+	workloadList, err := svc.GetWorkloadList("Namespace")
+	assert.NoError(err)
+	assert.Equal(1, len(workloadList.Workloads))
+	assert.Equal(2, workloadList.Workloads[0].PodCount)
+
+	workload, err := svc.GetWorkload("Namespace", "reviews", "Rollout", false)
+	assert.NoError(err)
+	assert.Equal("Rollout", workload.Type)
+	assert.Equal(2, len(workload.Pods))
+	assert.Nil(workload.ArgoRollout)
+}
+
+func TestGetWorkloadFromArgoRollout(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string")).Return(FakeArgoRolloutReplicaSets(), nil)
+	k8s.On("IsArgoRolloutsApi").Return(true)
+	k8s.On("GetArgoRollout", "Namespace", "reviews").Return(&FakeArgoRollouts()[0], nil)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsFromArgoRollout(), nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
+
+	svc := setupWorkloadService(k8s)
+
+	workload, err := svc.GetWorkload("Namespace", "reviews", "Rollout", false)
+
+	assert.NoError(err)
+	assert.Equal("Rollout", workload.Type)
+	assert.Equal(int32(4), workload.DesiredReplicas)
+	assert.Equal(2, len(workload.Pods))
+
+	rollout := workload.ArgoRollout
+	assert.NotNil(rollout)
+	assert.Equal("canary", rollout.Strategy)
+	assert.Equal("Paused", rollout.Phase)
+	assert.Equal(int32(2), *rollout.CurrentStep)
+	assert.Equal([]string{"setWeight 20", "pause", "setWeight 50"}, rollout.Steps)
+	assert.Equal(int32(20), rollout.CanaryWeight)
+	assert.Equal("reviews-5c4b", rollout.StableReplicaSet)
+	assert.Equal(int32(3), rollout.StableReplicas)
+	assert.Equal("reviews-7d9f", rollout.CanaryReplicaSet)
+	assert.Equal(int32(1), rollout.CanaryReplicas)
+	assert.Equal([]string{"reviews-stable", "reviews-canary"}, rollout.Services())
+}
+
 func TestGetWorkloadFromDeployment(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
@@ -348,9 +570,12 @@ func TestGetWorkloadFromDeployment(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetDaemonSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.DaemonSet{}, notfound)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsSyncedWithDeployments(), nil)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
 
 	svc := setupWorkloadService(k8s)
 
@@ -362,6 +587,49 @@ func TestGetWorkloadFromDeployment(t *testing.T) {
 	assert.Equal(true, workload.VersionLabel)
 }
 
+func TestGetWorkloadContainerResources(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pods := FakePodsSyncedWithDeployments()
+	dep := FakeDepSyncedWithRS()[0]
+	dep.Spec.Template.Labels = pods[0].Labels
+	notfound := errors.NewNotFound(schema.GroupResource{Group: "test-group", Resource: "test-resource"}, "not found")
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployment", "Namespace", "details-v1").Return(&dep, nil)
+	k8s.On("GetReplicaSets", mock.AnythingOfType("string")).Return(FakeRSSyncedWithPods(), nil)
+	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(pods, nil)
+	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
+	prom := new(prometheustest.PromClientMock)
+	sample := func(container string, value float64) *model.Sample {
+		return &model.Sample{Metric: model.Metric{"container": model.LabelValue(container)}, Value: model.SampleValue(value)}
+	}
+	prom.MockContainerResources("Namespace", map[string]model.Vector{
+		prometheus.ContainerCPUUsage:     {sample("details", 0.05), sample("istio-proxy", 0.2)},
+		prometheus.ContainerCPULimits:    {sample("istio-proxy", 0.2)},
+		prometheus.ContainerCPUThrottled: {sample("istio-proxy", 0.4)},
+	})
+	svc := WorkloadService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	workload, err := svc.GetWorkload("Namespace", "details-v1", "Deployment", false)
+
+	assert.NoError(err)
+	prom.AssertCalled(t, "GetContainerResources", "Namespace", []string{"details-v1-3618568057-dnkjp"}, "1m", mock.AnythingOfType("time.Time"))
+	assert.Len(workload.Resources.Containers, 1)
+	assert.Equal("details", workload.Resources.Containers[0].Name)
+	assert.Equal("istio-proxy", workload.Resources.Sidecar.Name)
+	assert.Equal(0.4, *workload.Resources.Sidecar.CPUThrottled)
+
+	// No resources without Prometheus client
+	svc = WorkloadService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+	workload, err = svc.GetWorkload("Namespace", "details-v1", "Deployment", false)
+	assert.NoError(err)
+	assert.Nil(workload.Resources)
+}
+
 func TestGetWorkloadFromPods(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
@@ -381,9 +649,12 @@ func TestGetWorkloadFromPods(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.ReplicaSet{}, nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.StatefulSet{}, notfound)
+	k8s.On("GetDaemonSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.DaemonSet{}, notfound)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsFromDaemonSet(), nil)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
 	svc := setupWorkloadService(k8s)
 
 	workload, _ := svc.GetWorkload("Namespace", "daemon-controller", "", false)
@@ -421,6 +692,7 @@ func TestGetPod(t *testing.T) {
 	k8s := new(kubetest.K8SClientMock)
 	k8s.On("GetPod", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodSyncedWithDeployments(), nil)
 	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
 
 	svc := setupWorkloadService(k8s)
 
@@ -564,16 +836,20 @@ func TestDuplicatedControllers(t *testing.T) {
 	k8s.On("GetReplicaSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakeDuplicatedReplicaSets(), nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakeDuplicatedStatefulSets(), nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodsSyncedWithDuplicated(), nil)
 	k8s.On("GetPod", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakePodSyncedWithDeployments(), nil)
 	k8s.On("GetPodLogs", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).Return(FakePodLogsSyncedWithDeployments(), nil)
+	k8s.On("GetObjectEvents", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.Event{}, nil)
 
 	notfound := fmt.Errorf("not found")
 	k8s.On("GetDeployment", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&FakeDuplicatedDeployments()[0], nil)
 	k8s.On("GetDeploymentConfig", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&osapps_v1.DeploymentConfig{}, notfound)
 	k8s.On("GetStatefulSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&FakeDuplicatedStatefulSets()[0], nil)
+	k8s.On("GetDaemonSet", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(&apps_v1.DaemonSet{}, notfound)
 
 	svc := setupWorkloadService(k8s)
 
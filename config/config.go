@@ -234,9 +234,11 @@ type KubernetesConfig struct {
 	// namespace cache defined by previous CacheDuration parameter
 	CacheTokenNamespaceDuration int `yaml:"cache_token_namespace_duration,omitempty"`
 	// List of controllers that won't be used for Workload calculation
-	// Kiali queries Deployment,ReplicaSet,ReplicationController,DeploymentConfig,StatefulSet,Job and CronJob controllers
-	// Deployment and ReplicaSet will be always queried, but ReplicationController,DeploymentConfig,StatefulSet,Job and CronJobs
-	// can be skipped from Kiali workloads query if they are present in this list
+	// Kiali queries Deployment,ReplicaSet,ReplicationController,DeploymentConfig,StatefulSet,DaemonSet,Rollout,Job and CronJob controllers
+	// Deployment and ReplicaSet will be always queried, but ReplicationController,DeploymentConfig,StatefulSet,DaemonSet,Rollout,Job and CronJobs
+	// can be skipped from Kiali workloads query if they are present in this list. Argo Rollouts are only queried when its API is installed,
+	// and the Kiali role needs the get and list verbs on the argoproj.io rollouts resource (the patch verb for the workload actions).
+	// Without them, Rollouts are shown from the pods of their ReplicaSets.
 	ExcludeWorkloads []string `yaml:"excluded_workloads,omitempty"`
 	QPS              float32  `yaml:"qps,omitempty"`
 }
@@ -312,7 +314,75 @@ type Rate struct {
 
 // HealthConfig
 type HealthConfig struct {
-	Rate []Rate `yaml:"rate,omitempty" json:"rate"`
+	// NamespaceEvents adds the warning events of the workloads to the health of the namespaces. It is disabled by default,
+	// as all the events of the namespace are listed at each refresh of the health.
+	NamespaceEvents bool   `yaml:"namespace_events,omitempty" json:"namespaceEvents"`
+	Rate            []Rate `yaml:"rate,omitempty" json:"rate"`
+}
+
+// CustomValidationRule defines an organization specific check over the objects of an Istio type.
+// Condition is evaluated on every value found in Path and the check is reported when it is not satisfied.
+// i.e. path: "spec/servers[*]/port/number", condition: "!= 80"
+type CustomValidationRule struct {
+	// Code of the check, used to suppress it as any other check
+	Code string `yaml:"code,omitempty" json:"code"`
+	// Condition is "exists", "absent" or a comparison like "== value", "!= value", "=~ regex", "!~ regex", "> number"...
+	Condition string `yaml:"condition" json:"condition"`
+	Message   string `yaml:"message" json:"message"`
+	// ObjectType is the Istio type (plural) of the objects validated, i.e. virtualservices
+	ObjectType string `yaml:"object_type" json:"objectType"`
+	// Path in the object, where [*] iterates over the items of a list, i.e. spec/http[*]/timeout
+	Path     string `yaml:"path" json:"path"`
+	Severity string `yaml:"severity,omitempty" json:"severity"`
+}
+
+// SidecarTrafficValidationConfig enables the validation of the Sidecars egress hosts against the outbound
+// traffic observed in Prometheus during the Lookback duration (i.e. 1h). It is disabled by default, as it adds
+// Prometheus queries to the validations of every namespace with Sidecars defining egress hosts.
+type SidecarTrafficValidationConfig struct {
+	Enabled  bool   `yaml:"enabled" json:"enabled"`
+	Lookback string `yaml:"lookback,omitempty" json:"lookback"`
+}
+
+// ValidationsConfig defines the check codes (i.e. KIA1104) suppressed from the Istio config validations
+// and the custom rules validated in addition to the built-in checks. Custom rules are parsed once at startup,
+// where an invalid rule stops Kiali.
+// Suppressed checks are still reported apart, but they don't affect the validity of the objects.
+type ValidationsConfig struct {
+	CustomRules    []CustomValidationRule         `yaml:"custom_rules,omitempty" json:"customRules"`
+	SidecarTraffic SidecarTrafficValidationConfig `yaml:"sidecar_traffic,omitempty" json:"sidecarTraffic"`
+	// Suppress contains the codes suppressed for every namespace
+	Suppress []string `yaml:"suppress,omitempty" json:"suppress"`
+	// SuppressByNamespace contains the codes suppressed per namespace name
+	SuppressByNamespace map[string][]string `yaml:"suppress_by_namespace,omitempty" json:"suppressByNamespace"`
+}
+
+// Store types of the Istio config history
+const (
+	IstioConfigHistoryConfigMapStore = "configmap"
+	IstioConfigHistoryMemoryStore    = "memory"
+)
+
+// IstioConfigHistoryConfig defines where the revisions of the Istio objects written through Kiali are stored.
+// The configmap store keeps a ConfigMap per namespace, while the memory store is lost on restart.
+// Only the last MaxRevisions revisions of each object are kept. The configmap store also drops the oldest revisions
+// of an object when its ConfigMap would exceed MaxConfigMapBytes, as ConfigMaps are limited to 1 MiB.
+// The history is disabled by default, as the configmap store needs Kiali to be allowed to write ConfigMaps: Enabled
+// must be set to get the audit trail of the changes done through Kiali, and nothing is recorded while it's disabled.
+// The default store is memory, so the audit trail only survives restarts with the configmap store.
+type IstioConfigHistoryConfig struct {
+	ConfigMapName     string `yaml:"config_map_name,omitempty"`
+	Enabled           bool   `yaml:"enabled"`
+	MaxConfigMapBytes int    `yaml:"max_config_map_bytes,omitempty"`
+	MaxRevisions      int    `yaml:"max_revisions,omitempty"`
+	Store             string `yaml:"store,omitempty"`
+}
+
+// LogParsingConfig defines the keys of the level and of the message of the JSON application logs.
+// The first key found in an entry is used.
+type LogParsingConfig struct {
+	LevelKeys   []string `yaml:"level_keys,omitempty"`
+	MessageKeys []string `yaml:"message_keys,omitempty"`
 }
 
 // Config defines full YAML configuration.
@@ -328,12 +398,15 @@ type Config struct {
 	InCluster                bool                     `yaml:"in_cluster,omitempty"`
 	InstallationTag          string                   `yaml:"installation_tag,omitempty"`
 	IstioComponentNamespaces IstioComponentNamespaces `yaml:"istio_component_namespaces,omitempty"`
+	IstioConfigHistory       IstioConfigHistoryConfig `yaml:"istio_config_history,omitempty"`
 	IstioLabels              IstioLabels              `yaml:"istio_labels,omitempty"`
 	IstioNamespace           string                   `yaml:"istio_namespace,omitempty"` // default component namespace
 	KialiFeatureFlags        KialiFeatureFlags        `yaml:"kiali_feature_flags,omitempty"`
 	KubernetesConfig         KubernetesConfig         `yaml:"kubernetes_config,omitempty"`
+	LogParsing               LogParsingConfig         `yaml:"log_parsing,omitempty"`
 	LoginToken               LoginToken               `yaml:"login_token,omitempty"`
 	Server                   Server                   `yaml:",omitempty"`
+	Validations              ValidationsConfig        `yaml:"validations,omitempty"`
 }
 
 // NewConfig creates a default Config struct
@@ -440,6 +513,13 @@ func NewConfig() (c *Config) {
 				WhiteListIstioSystem: []string{"jaeger-query", "istio-ingressgateway"},
 			},
 		},
+		IstioConfigHistory: IstioConfigHistoryConfig{
+			ConfigMapName:     "kiali-istio-config-history",
+			Enabled:           false,
+			MaxConfigMapBytes: 900 * 1024,
+			MaxRevisions:      20,
+			Store:             IstioConfigHistoryMemoryStore,
+		},
 		IstioLabels: IstioLabels{
 			AppLabelName:       "app",
 			InjectionLabelName: "istio-injection",
@@ -458,6 +538,10 @@ func NewConfig() (c *Config) {
 			ExcludeWorkloads:            []string{"CronJob", "DeploymentConfig", "Job", "ReplicationController"},
 			QPS:                         175,
 		},
+		LogParsing: LogParsingConfig{
+			LevelKeys:   []string{"level", "severity", "lvl"},
+			MessageKeys: []string{"msg", "message"},
+		},
 		LoginToken: LoginToken{
 			ExpirationSeconds: 24 * 3600,
 			SigningKey:        "kiali",
@@ -474,6 +558,12 @@ func NewConfig() (c *Config) {
 			WebHistoryMode:             "browser",
 			WebSchema:                  "",
 		},
+		Validations: ValidationsConfig{
+			SidecarTraffic: SidecarTrafficValidationConfig{
+				Enabled:  false,
+				Lookback: "1h",
+			},
+		},
 	}
 
 	return
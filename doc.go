@@ -34,7 +34,7 @@ type AggregateValueParam struct {
 	Name string `json:"aggregateValue"`
 }
 
-// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces errorTraces
+// swagger:parameters appMetrics appDetails appLogs graphApp graphAppVersion appDashboard appSpans appTraces errorTraces
 type AppParam struct {
 	// The app name (label value).
 	//
@@ -52,7 +52,7 @@ type AppVersionParam struct {
 	Name string `json:"version"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters podLogs podLogsStream workloadLogs appLogs
 type ContainerParam struct {
 	// The pod container name. Optional for single-container pod. Otherwise required.
 	//
@@ -61,7 +61,7 @@ type ContainerParam struct {
 	Name string `json:"container"`
 }
 
-// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments
+// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs podLogsStream workloadLogs workloadRestart workloadScale workloadPause workloadResume workloadRollout workloadEvents serviceEvents podEvents appLogs podConfigDump podProxyLogging podProxyLoggingUpdate namespaceValidations namespaceValidationsDryRun sidecarGenerate sidecarGenerateCreate workloadSidecarGenerate workloadSidecarGenerateCreate authorizationPolicyGenerate authorizationPolicyGenerateCreate istioConfigRevisions istioConfigRevisionsDiff istioConfigRevert istioConfigBatchApply serviceTrafficWizard serviceTrafficWizardDelete serviceChaosExperimentStart serviceChaosExperimentStop istioConfigReferences serviceReferences workloadReferences istioConfigUnused getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments
 type NamespaceParam struct {
 	// The namespace name.
 	//
@@ -79,7 +79,7 @@ type NameParam struct {
 	Name string `json:"name"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigRevisions istioConfigRevisionsDiff istioConfigRevert istioConfigReferences
 type ObjectNameParam struct {
 	// The Istio object name.
 	//
@@ -88,7 +88,7 @@ type ObjectNameParam struct {
 	Name string `json:"object"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype istioConfigRevisions istioConfigRevisionsDiff istioConfigRevert istioConfigReferences
 type ObjectTypeParam struct {
 	// The Istio object type.
 	//
@@ -98,7 +98,16 @@ type ObjectTypeParam struct {
 	Name string `json:"object_type"`
 }
 
-// swagger:parameters podDetails podLogs
+// swagger:parameters meshValidations
+type ObjectTypesParam struct {
+	// Comma separated list of Istio object types (plural) to filter by, i.e. virtualservices,gateways.
+	//
+	// in: query
+	// required: false
+	Name string `json:"objects"`
+}
+
+// swagger:parameters podDetails podEvents podLogs podLogsStream podConfigDump podProxyLogging podProxyLoggingUpdate
 type PodParam struct {
 	// The pod name.
 	//
@@ -107,7 +116,7 @@ type PodParam struct {
 	Name string `json:"pod"`
 }
 
-// swagger:parameters serviceDetails serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces
+// swagger:parameters serviceDetails serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces serviceTrafficWizard serviceTrafficWizardDelete serviceChaosExperimentStart serviceChaosExperimentStop serviceReferences serviceEvents
 type ServiceParam struct {
 	// The service name.
 	//
@@ -116,7 +125,43 @@ type ServiceParam struct {
 	Name string `json:"service"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters podConfigDump
+type EnvoyConfigSectionsParam struct {
+	// Comma separated list of the sections returned: bootstrap, clusters, listeners or routes. Default is all of them.
+	//
+	// in: query
+	// required: false
+	Name string `json:"sections"`
+}
+
+// swagger:parameters podConfigDump
+type EnvoyListenerPortParam struct {
+	// Port of the listeners returned.
+	//
+	// in: query
+	// required: false
+	Name string `json:"port"`
+}
+
+// swagger:parameters podConfigDump
+type EnvoyClusterServiceParam struct {
+	// Service FQDN of the clusters returned.
+	//
+	// in: query
+	// required: false
+	Name string `json:"service"`
+}
+
+// swagger:parameters podConfigDump
+type EnvoyRouteConfigParam struct {
+	// Name of the route configs returned.
+	//
+	// in: query
+	// required: false
+	Name string `json:"route"`
+}
+
+// swagger:parameters podLogs podLogsStream workloadLogs appLogs
 type SinceTimeParam struct {
 	// The start time for fetching logs. UNIX time in seconds. Default is all logs.
 	//
@@ -125,7 +170,7 @@ type SinceTimeParam struct {
 	Name string `json:"sinceTime"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters podLogs workloadLogs appLogs
 type DurationLogParam struct {
 	// Query time-range duration (Golang string duration). Duration starts on
 	// `sinceTime` if set, or the time for the first log message if not set.
@@ -135,6 +180,192 @@ type DurationLogParam struct {
 	Name string `json:"duration"`
 }
 
+// swagger:parameters workloadLogs appLogs
+type TailLinesLogParam struct {
+	// Number of entries returned, the last ones of the merged logs. Each container log is limited to the same number.
+	//
+	// in: query
+	// required: false
+	Name string `json:"tailLines"`
+}
+
+// swagger:parameters podLogs podLogsStream workloadLogs appLogs
+type LogFilterParam struct {
+	// Text contained in the message of the entries returned.
+	//
+	// in: query
+	// required: false
+	Name string `json:"filter"`
+}
+
+// swagger:parameters podLogs podLogsStream workloadLogs appLogs
+type LogFilterRegexParam struct {
+	// When true, the filter is a regular expression matched by the message of the entries returned.
+	//
+	// in: query
+	// required: false
+	Name bool `json:"regex"`
+}
+
+// swagger:parameters podLogs podLogsStream workloadLogs appLogs
+type LogFieldConditionParam struct {
+	// Condition on a field parsed from the entries returned, i.e. code>=500 for the access logs of the proxies.
+	// Operators are =, !=, >, >=, < and <=; the entries must meet all the conditions.
+	//
+	// in: query
+	// required: false
+	Name []string `json:"field"`
+}
+
+// swagger:parameters podLogsStream
+type MaxDurationLogStreamParam struct {
+	// Time after which the logs stop being followed (Golang string duration). Default and maximum is 1h, streams over
+	// HTTP/2 end after 25s.
+	//
+	// in: query
+	// required: false
+	Name string `json:"maxDuration"`
+}
+
+// swagger:parameters meshValidations
+type SeverityParam struct {
+	// Comma separated list of check severities to filter by: error, warning.
+	//
+	// in: query
+	// required: false
+	Name string `json:"severity"`
+}
+
+// swagger:parameters namespaceValidationsDryRun
+type IstioConfigChangeParam struct {
+	// The proposed change of an Istio object.
+	//
+	// in: body
+	// required: true
+	Body models.IstioConfigChange
+}
+
+// swagger:parameters sidecarGenerate sidecarGenerateCreate workloadSidecarGenerate workloadSidecarGenerateCreate authorizationPolicyGenerate authorizationPolicyGenerateCreate
+type GeneratorLookbackParam struct {
+	// The duration of the observed traffic, i.e. 30m, 1h, 1d.
+	//
+	// in: query
+	// required: false
+	// default: 1h
+	Name string `json:"lookback"`
+}
+
+// swagger:parameters istioConfigUnused
+type UnusedConfigLookbackParam struct {
+	// The duration of the observed traffic, i.e. 1d, 7d, 30d.
+	//
+	// in: query
+	// required: false
+	// default: 7d
+	Name string `json:"lookback"`
+}
+
+// swagger:parameters authorizationPolicyGenerate authorizationPolicyGenerateCreate
+type SourceScopeParam struct {
+	// The identity of the allowed sources: principal or namespace.
+	//
+	// in: query
+	// required: false
+	// default: principal
+	Name string `json:"sourceScope"`
+}
+
+// swagger:parameters istioConfigRevisionsDiff
+type RevisionFromParam struct {
+	// The revision compared from. By default, the object before the revision compared to.
+	//
+	// in: query
+	// required: false
+	Name string `json:"from"`
+}
+
+// swagger:parameters istioConfigRevisionsDiff
+type RevisionToParam struct {
+	// The revision compared to. By default, the last revision.
+	//
+	// in: query
+	// required: false
+	Name string `json:"to"`
+}
+
+// swagger:parameters istioConfigRevert
+type RevisionParam struct {
+	// The revision to restore.
+	//
+	// in: path
+	// required: true
+	Name string `json:"revision"`
+}
+
+// swagger:parameters istioConfigBatchApply
+type IstioConfigBatchParam struct {
+	// The Istio objects to apply, as a multi-document YAML or a JSON list.
+	//
+	// in: body
+	// required: true
+	Body string
+}
+
+// swagger:parameters istioConfigBatchApply
+type IstioConfigBatchDryRunParam struct {
+	// Only validate the objects, without applying them.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name bool `json:"dryRun"`
+}
+
+// swagger:parameters serviceTrafficWizard
+type TrafficWizardParam struct {
+	// The traffic wizard: weighted_routing, request_routing, fault_injection, request_timeouts, circuit_breaker or traffic_mirroring.
+	//
+	// in: path
+	// required: true
+	Name string `json:"wizard"`
+}
+
+// swagger:parameters serviceTrafficWizard
+type TrafficWizardBodyParam struct {
+	// The parameters of the wizard, as weightedRouting, requestRouting, faultInjection, requestTimeouts, circuitBreaker or trafficMirroring.
+	//
+	// in: body
+	// required: true
+	Body string
+}
+
+// swagger:parameters serviceChaosExperimentStart
+type ChaosExperimentParam struct {
+	// The faults injected and the duration of the experiment.
+	//
+	// in: body
+	// required: true
+	Body models.ChaosExperimentRequest
+}
+
+// swagger:parameters podProxyLoggingUpdate
+type EnvoyLogLevelParam struct {
+	// The log level, the logger changed or all of them, and the optional duration after which the levels are reset.
+	//
+	// in: body
+	// required: true
+	Body models.EnvoyLogLevelRequest
+}
+
+// swagger:parameters workloadScale
+type WorkloadScaleParam struct {
+	// The number of desired replicas.
+	//
+	// in: body
+	// required: true
+	Body models.WorkloadScaleRequest
+}
+
 // swagger:parameters traceDetails
 type TraceIDParam struct {
 	// The trace ID.
@@ -153,7 +384,7 @@ type DashboardParam struct {
 	Name string `json:"dashboard"`
 }
 
-// swagger:parameters workloadDetails workloadUpdate workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces
+// swagger:parameters workloadDetails workloadUpdate workloadLogs workloadRestart workloadScale workloadPause workloadResume workloadRollout workloadEvents workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces workloadSidecarGenerate workloadSidecarGenerateCreate authorizationPolicyGenerate authorizationPolicyGenerateCreate workloadReferences
 type WorkloadParam struct {
 	// The workload name.
 	//
@@ -293,6 +524,9 @@ type DurationParam struct {
 // swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics
 type FiltersParam struct {
 	// List of metrics to fetch. Fetch all metrics when empty. List entries are Kiali internal metric names.
+	// For workloads, the CPU and memory metrics of the containers are only fetched when listed: container_cpu_usage,
+	// container_cpu_requests, container_cpu_limits, container_cpu_throttled, container_memory_usage, container_memory_requests,
+	// container_memory_limits.
 	//
 	// in: query
 	// required: false
@@ -410,6 +644,20 @@ type BadRequestError struct {
 	} `json:"body"`
 }
 
+// A ForbiddenError is the error message that means the user is not allowed to do what was requested
+//
+// swagger:response forbiddenError
+type ForbiddenError struct {
+	// in: body
+	Body struct {
+		// HTTP status code
+		// example: 403
+		// default: 403
+		Code    int32 `json:"code"`
+		Message error `json:"message"`
+	} `json:"body"`
+}
+
 // A NotFoundError is the error message that is generated when server could not find what was requested.
 //
 // swagger:response notFoundError
@@ -438,6 +686,20 @@ type NotAcceptableError struct {
 	} `json:"body"`
 }
 
+// A Conflict is the error message that means the object to create already exists
+//
+// swagger:response conflictError
+type ConflictError struct {
+	// in: body
+	Body struct {
+		// HTTP status code
+		// example: 409
+		// default: 409
+		Code    int32 `json:"code"`
+		Message error `json:"message"`
+	} `json:"body"`
+}
+
 // A Internal is the error message that means something has gone wrong
 //
 // swagger:response internalError
@@ -662,6 +924,118 @@ type NamespaceValidationSummaryResponse struct {
 	Body models.IstioValidationSummary
 }
 
+// Return the validations of all the namespaces of the mesh
+// swagger:response meshValidationsResponse
+type MeshValidationsResponse struct {
+	// in:body
+	Body models.MeshValidations
+}
+
+// Return the validations introduced, resolved and unchanged by a proposed change
+// swagger:response dryRunValidationsResponse
+type DryRunValidationsResponse struct {
+	// in:body
+	Body models.DryRunValidations
+}
+
+// Return the revisions of an Istio object written through Kiali
+// swagger:response istioConfigRevisionsResponse
+type IstioConfigRevisionsResponse struct {
+	// in:body
+	Body []models.IstioConfigRevision
+}
+
+// Return the differences of an Istio object between two revisions
+// swagger:response istioConfigRevisionDiffResponse
+type IstioConfigRevisionDiffResponse struct {
+	// in:body
+	Body models.IstioConfigRevisionDiff
+}
+
+// Return the validations of a batch of Istio objects and how they are applied
+// swagger:response istioConfigBatchResponse
+type IstioConfigBatchResponse struct {
+	// in:body
+	Body models.IstioConfigBatchResult
+}
+
+// Return a fault injection experiment running on a service
+// swagger:response chaosExperimentResponse
+type ChaosExperimentResponse struct {
+	// in:body
+	Body models.ChaosExperiment
+}
+
+// Return the Istio objects referencing an object
+// swagger:response istioReferencesResponse
+type IstioReferencesResponse struct {
+	// in:body
+	Body models.IstioReferences
+}
+
+// Return the entries of the logs of a pod container, each one as the data of a "log" server-sent event
+// swagger:response podLogsStreamResponse
+type PodLogsStreamResponse struct {
+	// in:body
+	Body business.LogEntry
+}
+
+// Return the logs of the containers of several pods, merged in time order
+// swagger:response aggregatedLogResponse
+type AggregatedLogResponse struct {
+	// in:body
+	Body business.AggregatedLog
+}
+
+// Return the progress of the rollout of a workload and the history of its revisions
+// swagger:response workloadRolloutResponse
+type WorkloadRolloutResponse struct {
+	// in:body
+	Body models.WorkloadRollout
+}
+
+// Return the recent events of a workload, a service or a pod
+// swagger:response eventsResponse
+type EventsResponse struct {
+	// in:body
+	Body models.Events
+}
+
+// Return the configuration received by the Envoy proxy of a pod
+// swagger:response envoyConfigDumpResponse
+type EnvoyConfigDumpResponse struct {
+	// in:body
+	Body models.EnvoyConfigDump
+}
+
+// Return the log level of each logger of the Envoy proxy of a pod
+// swagger:response envoyLogLevelsResponse
+type EnvoyLogLevelsResponse struct {
+	// in:body
+	Body models.EnvoyLogLevels
+}
+
+// Return the Istio objects of a namespace with no effect
+// swagger:response unusedIstioConfigResponse
+type UnusedIstioConfigResponse struct {
+	// in:body
+	Body models.UnusedIstioConfigReport
+}
+
+// Return the VirtualService and DestinationRule generated by a traffic wizard
+// swagger:response trafficWizardResponse
+type TrafficWizardResponse struct {
+	// in:body
+	Body models.TrafficWizardResult
+}
+
+// Return an AuthorizationPolicy generated from the observed traffic and its diff from the existing one
+// swagger:response authorizationPolicyProposalResponse
+type AuthorizationPolicyProposalResponse struct {
+	// in:body
+	Body models.AuthorizationPolicyProposal
+}
+
 //////////////////
 // SWAGGER MODELS
 //////////////////
@@ -49,3 +49,31 @@ func AppDetails(w http.ResponseWriter, r *http.Request) {
 
 	RespondWithJSON(w, http.StatusOK, appDetails)
 }
+
+// AppLogs is the API handler to fetch the logs of all the containers of the pods of an app, merged in time order
+func AppLogs(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	app := params["app"]
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "App Logs initialization error: "+err.Error())
+		return
+	}
+
+	opts, err := logOptionsCriteria(business, r.URL.Query(), r.URL.Query().Get("duration"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	logs, err := business.App.GetAppLogs(namespace, app, opts)
+	if err != nil {
+		handleLogsError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, logs)
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/kiali/kiali/util"
 )
 
 type responseError struct {
@@ -34,6 +36,39 @@ func RespondWithJSONIndent(w http.ResponseWriter, code int, payload interface{})
 	_, _ = w.Write(response)
 }
 
+// RespondWithYAML writes the payload as YAML, using the JSON names of its fields. Null values are omitted.
+func RespondWithYAML(w http.ResponseWriter, code int, payload interface{}) {
+	response, err := util.MarshalYAML(payload)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(code)
+	_, _ = w.Write(response)
+}
+
+// RespondWithYAMLDocuments writes the documents as a multi-document YAML, using the JSON names of their fields
+func RespondWithYAMLDocuments(w http.ResponseWriter, code int, documents []map[string]interface{}) {
+	response := []byte{}
+	for i, document := range documents {
+		b, err := util.MarshalYAML(document)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if i > 0 {
+			response = append(response, "---\n"...)
+		}
+		response = append(response, b...)
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(code)
+	_, _ = w.Write(response)
+}
+
 func RespondWithError(w http.ResponseWriter, code int, message string) {
 	RespondWithJSON(w, code, responseError{Error: message})
 }
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/models"
+)
+
+// ServiceChaosExperiment returns the fault injection experiment running on a service, with the health of its requests
+func ServiceChaosExperiment(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	p := serviceHealthParams{}
+	p.extract(r)
+	rateInterval, err := adjustRateInterval(business, p.Namespace, p.RateInterval, p.QueryTime)
+	if err != nil {
+		handleErrorResponse(w, err, "Adjust rate interval error: "+err.Error())
+		return
+	}
+
+	experiment, err := business.IstioConfig.GetChaosExperiment(p.Namespace, p.Service, rateInterval, p.QueryTime)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, experiment)
+}
+
+// ServiceChaosExperimentStart injects faults in the requests of a service for a duration
+func ServiceChaosExperimentStart(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	var request models.ChaosExperimentRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Chaos experiment request could not be parsed: "+err.Error())
+		return
+	}
+
+	experiment, err := business.IstioConfig.StartChaosExperiment(namespace, service, request)
+	if err != nil {
+		handleWizardError(w, err)
+		return
+	}
+
+	audit(r, "CHAOS EXPERIMENT on Namespace: "+namespace+" Service: "+service+" Duration: "+request.Duration)
+	RespondWithJSON(w, http.StatusOK, experiment)
+}
+
+// ServiceChaosExperimentStop ends the experiment running on a service before its expiry
+func ServiceChaosExperimentStop(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	if err = business.IstioConfig.StopChaosExperiment(namespace, service); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	audit(r, "STOP CHAOS EXPERIMENT on Namespace: "+namespace+" Service: "+service)
+	RespondWithCode(w, http.StatusOK)
+}
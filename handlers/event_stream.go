@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+// flushedStreamMaxDuration is the longest time events are flushed to a response, before the write timeout of the
+// server (30s) cuts the stream
+const flushedStreamMaxDuration = 25 * time.Second
+
+// eventStream sends server-sent events to a client. When possible the connection is hijacked, so the stream is not
+// cut by the write timeout of the server; otherwise (i.e. HTTP/2) the events are flushed to the response, and the
+// stream must end before the write timeout. The client can then open a new stream to continue.
+type eventStream struct {
+	writer io.Writer
+	flush  func() error
+	conn   net.Conn
+	timer  *time.Timer
+
+	// Done is closed when the client disconnects
+	Done <-chan struct{}
+	// Timeout fires when the stream must end before the write timeout of the server, it's nil when the connection
+	// is hijacked
+	Timeout <-chan time.Time
+}
+
+func newEventStream(w http.ResponseWriter, r *http.Request) (*eventStream, error) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if hijacker, ok := w.(http.Hijacker); ok {
+		header := w.Header()
+		conn, rw, err := hijacker.Hijack()
+		if err != nil {
+			return nil, err
+		}
+		// Clear the deadlines set by the server for the request
+		if err = conn.SetDeadline(time.Time{}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		header.Set("Connection", "close")
+		if _, err = rw.WriteString("HTTP/1.1 200 OK\r\n"); err == nil {
+			if err = header.Write(rw); err == nil {
+				_, err = rw.WriteString("\r\n")
+			}
+		}
+		if err == nil {
+			err = rw.Flush()
+		}
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		done := make(chan struct{})
+		go func(reader *bufio.Reader) {
+			// The client sends nothing after the request: the read only returns when the connection is closed
+			_, _ = io.Copy(ioutil.Discard, reader)
+			close(done)
+		}(rw.Reader)
+		return &eventStream{writer: rw, flush: rw.Flush, conn: conn, Done: done}, nil
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("streaming is not supported by the connection")
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	timer := time.NewTimer(flushedStreamMaxDuration)
+	return &eventStream{
+		writer: w,
+		flush: func() error {
+			flusher.Flush()
+			return nil
+		},
+		timer:   timer,
+		Done:    r.Context().Done(),
+		Timeout: timer.C,
+	}, nil
+}
+
+// Send writes an event with its data in JSON, then flushes it to the client
+func (s *eventStream) Send(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(s.writer, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// Close ends the stream. The client may reconnect unless it was told about the end of the stream by an event.
+func (s *eventStream) Close() {
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStreamFlushed(t *testing.T) {
+	var hijacked, timeout bool
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, err := newEventStream(w, r)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		defer stream.Close()
+		hijacked = stream.conn != nil
+		timeout = stream.Timeout != nil
+		_ = stream.Send("log", map[string]string{"message": "first"})
+		_ = stream.Send("end", map[string]string{})
+	}))
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, "HTTP/2.0", resp.Proto)
+	assert.Equal(t, 200, resp.StatusCode, string(actual))
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "event: log\ndata: {\"message\":\"first\"}\n\nevent: end\ndata: {}\n\n", string(actual))
+	// HTTP/2 connections can't be hijacked, the stream ends before the write timeout
+	assert.False(t, hijacked)
+	assert.True(t, timeout)
+}
+
+func TestEventStreamHijacked(t *testing.T) {
+	var hijacked, timeout bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, err := newEventStream(w, r)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		defer stream.Close()
+		hijacked = stream.conn != nil
+		timeout = stream.Timeout != nil
+		_ = stream.Send("end", map[string]string{})
+	}))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, "HTTP/1.1", resp.Proto)
+	assert.Equal(t, "event: end\ndata: {}\n\n", string(actual))
+	assert.True(t, hijacked)
+	assert.False(t, timeout)
+}
@@ -201,7 +201,7 @@ func (p *appHealthParams) extract(r *http.Request) {
 
 // serviceHealthParams holds the path and query parameters for ServiceHealth
 //
-// swagger:parameters serviceHealth
+// swagger:parameters serviceHealth serviceChaosExperiment
 type serviceHealthParams struct {
 	baseHealthParams
 	// The target service
@@ -34,6 +34,7 @@ func TestNamespaceAppHealth(t *testing.T) {
 
 	k8s.MockServices("ns", []string{"reviews", "httpbin"})
 	k8s.On("GetPods", "ns", mock.AnythingOfType("string")).Return(kubetest.FakePodList(), nil)
+	k8s.On("IsArgoRolloutsApi").Return(true)
 	k8s.MockEmptyWorkloads("ns")
 
 	// Test 17s on rate interval to check that rate interval is adjusted correctly.
@@ -86,6 +87,7 @@ func TestAppHealth(t *testing.T) {
 	url := ts.URL + "/api/namespaces/ns/apps/reviews/health"
 
 	k8s.On("GetPods", "ns", "app=reviews").Return(kubetest.FakePodList(), nil)
+	k8s.On("IsArgoRolloutsApi").Return(true)
 	k8s.MockEmptyWorkloads("ns")
 
 	// Test 17s on rate interval to check that rate interval is adjusted correctly.
@@ -1,19 +1,33 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
 )
 
+// defaultGeneratorLookback is the default duration of the traffic used to generate Istio config
+const defaultGeneratorLookback = "1h"
+
+// defaultUnusedConfigLookback is the default duration of the traffic used to find unused Istio config
+const defaultUnusedConfigLookback = "7d"
+
 func IstioConfigList(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	namespace := params["namespace"]
@@ -83,6 +97,15 @@ func IstioConfigList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if acceptsYAML(r) {
+		manifests, err := business.IstioConfig.IstioConfigListManifests(istioConfig)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		RespondWithYAMLDocuments(w, http.StatusOK, manifests)
+		return
+	}
 	RespondWithJSON(w, http.StatusOK, istioConfig)
 }
 
@@ -141,6 +164,15 @@ func IstioConfigDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if acceptsYAML(r) {
+		manifest, err := business.IstioConfig.IstioConfigDetailsManifest(istioConfigDetails)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		RespondWithYAML(w, http.StatusOK, manifest)
+		return
+	}
 	RespondWithJSON(w, http.StatusOK, istioConfigDetails)
 }
 
@@ -195,6 +227,24 @@ func IstioConfigUpdate(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		RespondWithError(w, http.StatusBadRequest, "Update request with bad update patch: "+err.Error())
 	}
+
+	// YAML bodies are full objects, merged with the current object as kubectl apply does
+	if isYAMLContent(r) {
+		manifests, err := util.ParseManifests(body)
+		if err != nil || len(manifests) != 1 {
+			RespondWithError(w, http.StatusBadRequest, "Update request must contain a single YAML object")
+			return
+		}
+		updatedConfigDetails, err := business.IstioConfig.ReplaceIstioConfigDetail(api, namespace, objectType, object, manifests[0])
+		if err != nil {
+			handleRevisionError(w, err)
+			return
+		}
+		audit(r, "UPDATE on Namespace: "+namespace+" Type: "+objectType+" Name: "+object+" Object: "+string(body))
+		RespondWithJSON(w, http.StatusOK, updatedConfigDetails)
+		return
+	}
+
 	jsonPatch := string(body)
 	updatedConfigDetails, err := business.IstioConfig.UpdateIstioConfigDetail(api, namespace, objectType, object, jsonPatch)
 
@@ -231,6 +281,24 @@ func IstioConfigCreate(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Create request could not be read: "+err.Error())
 	}
 
+	// YAML bodies may hold several objects, created together
+	if isYAMLContent(r) {
+		manifests, err := util.ParseManifests(body)
+		if err != nil || len(manifests) == 0 {
+			RespondWithError(w, http.StatusBadRequest, "Create request must contain YAML objects")
+			return
+		}
+		createdConfigDetails, err := business.IstioConfig.CreateIstioConfigDetails(api, namespace, objectType, manifests)
+		if err != nil {
+			handleRevisionError(w, err)
+			return
+		}
+		audit(r, "CREATE on Namespace: "+namespace+" Type: "+objectType+" Object: "+string(body))
+		// An array is returned even for a single object, as the batch endpoints do
+		RespondWithJSON(w, http.StatusOK, createdConfigDetails)
+		return
+	}
+
 	createdConfigDetails, err := business.IstioConfig.CreateIstioConfigDetail(api, namespace, objectType, body)
 	if err != nil {
 		handleErrorResponse(w, err)
@@ -269,3 +337,362 @@ func IstioConfigPermissions(w http.ResponseWriter, r *http.Request) {
 	}
 	RespondWithJSON(w, http.StatusOK, istioConfigPermissions)
 }
+
+// SidecarGenerate returns, as YAML, a Sidecar limited to the services called by a namespace or workload
+func SidecarGenerate(w http.ResponseWriter, r *http.Request) {
+	_, sidecar, ok := generateSidecar(w, r)
+	if !ok {
+		return
+	}
+	RespondWithYAML(w, http.StatusOK, sidecar)
+}
+
+// SidecarGenerateCreate creates the Sidecar limited to the services called by a namespace or workload
+func SidecarGenerateCreate(w http.ResponseWriter, r *http.Request) {
+	layer, sidecar, ok := generateSidecar(w, r)
+	if !ok {
+		return
+	}
+	namespace := sidecar.GetObjectMeta().Namespace
+
+	// Kind and apiVersion are added on create
+	sidecar.SetTypeMeta(meta_v1.TypeMeta{})
+	body, err := json.Marshal(sidecar)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api := business.GetIstioAPI(kubernetes.Sidecars)
+	createdConfigDetails, err := layer.IstioConfig.CreateIstioConfigDetail(api, namespace, kubernetes.Sidecars, body)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	audit(r, "CREATE on Namespace: "+namespace+" Type: "+kubernetes.Sidecars+" Object: "+string(body))
+	RespondWithJSON(w, http.StatusOK, createdConfigDetails)
+}
+
+func generateSidecar(w http.ResponseWriter, r *http.Request) (*business.Layer, kubernetes.IstioObject, bool) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	workload := params["workload"]
+
+	lookback, ok := parseLookback(w, r, defaultGeneratorLookback)
+	if !ok {
+		return nil, nil, false
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return nil, nil, false
+	}
+
+	sidecar, err := layer.IstioConfig.GenerateSidecar(namespace, workload, lookback, time.Now())
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return nil, nil, false
+	}
+	return layer, sidecar, true
+}
+
+// AuthorizationPolicyGenerate returns an ALLOW AuthorizationPolicy limited to the traffic observed into a workload,
+// with the diff from the existing policy of the same name
+func AuthorizationPolicyGenerate(w http.ResponseWriter, r *http.Request) {
+	_, proposal, ok := generateAuthorizationPolicy(w, r)
+	if !ok {
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, proposal)
+}
+
+// AuthorizationPolicyGenerateCreate creates the ALLOW AuthorizationPolicy limited to the traffic observed into a workload
+func AuthorizationPolicyGenerateCreate(w http.ResponseWriter, r *http.Request) {
+	layer, proposal, ok := generateAuthorizationPolicy(w, r)
+	if !ok {
+		return
+	}
+	if proposal.Existing != nil {
+		RespondWithError(w, http.StatusConflict, "AuthorizationPolicy ["+proposal.Policy.Metadata.Name+"] already exists")
+		return
+	}
+	namespace := proposal.Policy.Metadata.Namespace
+
+	// Kind and apiVersion are added on create
+	proposal.Policy.TypeMeta = meta_v1.TypeMeta{}
+	body, err := json.Marshal(proposal.Policy)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	api := business.GetIstioAPI(kubernetes.AuthorizationPolicies)
+	createdConfigDetails, err := layer.IstioConfig.CreateIstioConfigDetail(api, namespace, kubernetes.AuthorizationPolicies, body)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	audit(r, "CREATE on Namespace: "+namespace+" Type: "+kubernetes.AuthorizationPolicies+" Object: "+string(body))
+	RespondWithJSON(w, http.StatusOK, createdConfigDetails)
+}
+
+func generateAuthorizationPolicy(w http.ResponseWriter, r *http.Request) (*business.Layer, models.AuthorizationPolicyProposal, bool) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	workload := params["workload"]
+
+	lookback, ok := parseLookback(w, r, defaultGeneratorLookback)
+	if !ok {
+		return nil, models.AuthorizationPolicyProposal{}, false
+	}
+	sourceScope := r.URL.Query().Get("sourceScope")
+	if sourceScope == "" {
+		sourceScope = business.PrincipalSourceScope
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return nil, models.AuthorizationPolicyProposal{}, false
+	}
+
+	proposal, err := layer.IstioConfig.GenerateAuthorizationPolicy(namespace, workload, lookback, sourceScope, time.Now())
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return nil, models.AuthorizationPolicyProposal{}, false
+	}
+	return layer, proposal, true
+}
+
+// parseLookback returns the lookback duration of the traffic used to generate or analyze Istio config
+func parseLookback(w http.ResponseWriter, r *http.Request, defaultLookback string) (string, bool) {
+	lookback := r.URL.Query().Get("lookback")
+	if lookback == "" {
+		return defaultLookback, true
+	}
+	if _, err := model.ParseDuration(lookback); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid lookback duration ["+lookback+"]: "+err.Error())
+		return "", false
+	}
+	return lookback, true
+}
+
+func IstioConfigRevisions(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+
+	if !checkObjectType(objectType) {
+		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
+		return
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	revisions, err := layer.IstioConfig.GetIstioConfigRevisions(namespace, objectType, object)
+	if err != nil {
+		handleRevisionError(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, revisions)
+}
+
+func IstioConfigRevisionsDiff(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+
+	if !checkObjectType(objectType) {
+		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
+		return
+	}
+	from, ok := parseRevision(w, r.URL.Query().Get("from"))
+	if !ok {
+		return
+	}
+	to, ok := parseRevision(w, r.URL.Query().Get("to"))
+	if !ok {
+		return
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	diff, err := layer.IstioConfig.DiffIstioConfigRevisions(namespace, objectType, object, from, to)
+	if err != nil {
+		handleRevisionError(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, diff)
+}
+
+func IstioConfigRevert(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+
+	if !checkObjectType(objectType) {
+		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
+		return
+	}
+	revision, ok := parseRevision(w, params["revision"])
+	if !ok {
+		return
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	revertedConfigDetails, err := layer.IstioConfig.RevertIstioConfig(namespace, objectType, object, revision)
+	if err != nil {
+		handleRevisionError(w, err)
+		return
+	}
+
+	audit(r, "REVERT on Namespace: "+namespace+" Type: "+objectType+" Name: "+object+" Revision: "+params["revision"])
+	RespondWithJSON(w, http.StatusOK, revertedConfigDetails)
+}
+
+// parseRevision returns the number of a revision, 0 when not set
+func parseRevision(w http.ResponseWriter, revision string) (int, bool) {
+	if revision == "" {
+		return 0, true
+	}
+	number, err := strconv.Atoi(revision)
+	if err != nil || number < 0 {
+		RespondWithError(w, http.StatusBadRequest, "Invalid revision ["+revision+"]")
+		return 0, false
+	}
+	return number, true
+}
+
+func handleRevisionError(w http.ResponseWriter, err error) {
+	if errors.IsBadRequest(err) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	} else {
+		handleErrorResponse(w, err)
+	}
+}
+
+func IstioConfigBatchApply(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Batch request could not be read: "+err.Error())
+		return
+	}
+
+	result, err := layer.IstioConfig.ApplyIstioConfigBatch(namespace, body, dryRun)
+	if err != nil && result.Error == "" {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+
+	for _, o := range result.Objects {
+		if o.Applied {
+			audit(r, "BATCH "+strings.ToUpper(o.Operation)+" on Namespace: "+namespace+" Type: "+o.ObjectType+" Name: "+o.Name)
+		}
+	}
+	switch {
+	case err != nil:
+		// The objects applied are rolled back, the result tells the objects that failed
+		code := http.StatusInternalServerError
+		if statusError, isStatus := err.(*errors.StatusError); isStatus && statusError.ErrStatus.Code >= http.StatusBadRequest {
+			code = int(statusError.ErrStatus.Code)
+		}
+		RespondWithJSON(w, code, result)
+	case !result.Valid:
+		RespondWithJSON(w, http.StatusUnprocessableEntity, result)
+	default:
+		RespondWithJSON(w, http.StatusOK, result)
+	}
+}
+
+// IstioReferences returns the Istio objects referencing a service, workload, Gateway or DestinationRule
+func IstioReferences(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType, name := params["object_type"], params["object"]
+	if service, found := params["service"]; found {
+		objectType, name = business.ServiceReferences, service
+	} else if workload, found := params["workload"]; found {
+		objectType, name = business.WorkloadReferences, workload
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	references, err := layer.IstioConfig.GetIstioReferences(namespace, objectType, name)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, references)
+}
+
+// IstioConfigUnused returns the Istio objects of a namespace with no effect, as found in the registry and in the
+// traffic observed during the lookback duration
+func IstioConfigUnused(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+
+	lookback, ok := parseLookback(w, r, defaultUnusedConfigLookback)
+	if !ok {
+		return
+	}
+
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	report, err := layer.Validations.GetUnusedIstioConfig(namespace, lookback, time.Now())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, report)
+}
@@ -80,9 +80,34 @@ func getWorkloadMetrics(w http.ResponseWriter, r *http.Request, promSupplier pro
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+
+	// Container metrics are only fetched when requested by the filters, as they are queried by the pods of the workload
+	if len(params.ContainerFilters()) > 0 {
+		containerMetrics, err := getWorkloadContainerMetrics(r, metricsService, params)
+		if err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		for name, series := range containerMetrics {
+			metrics[name] = series
+		}
+	}
 	RespondWithJSON(w, http.StatusOK, metrics)
 }
 
+// getWorkloadContainerMetrics returns the container metrics of the current pods of a workload
+func getWorkloadContainerMetrics(r *http.Request, metricsService *business.MetricsService, params models.IstioMetricsQuery) (models.MetricsMap, error) {
+	layer, err := getBusiness(r)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := layer.Workload.GetWorkloadPods(params.Namespace, params.Workload)
+	if err != nil {
+		return nil, err
+	}
+	return metricsService.GetContainerMetrics(params, pods)
+}
+
 // ServiceMetrics is the API handler to fetch metrics to be displayed, related to a single service
 func ServiceMetrics(w http.ResponseWriter, r *http.Request) {
 	getServiceMetrics(w, r, defaultPromClientSupplier)
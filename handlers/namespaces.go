@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
@@ -55,6 +58,95 @@ func NamespaceValidationSummary(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, validationSummary)
 }
 
+// MeshValidations is the API handler to fetch the validations of all the namespaces of the mesh, with a
+// summary per namespace. Validations can be filtered by object types and by severities of the checks.
+func MeshValidations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	objectTypes := make([]string, 0)
+	if objects := strings.ToLower(query.Get("objects")); len(objects) > 0 {
+		objectTypes = strings.Split(objects, ",")
+	}
+
+	severities := make([]models.SeverityLevel, 0)
+	if severity := strings.ToLower(query.Get("severity")); len(severity) > 0 {
+		for _, s := range strings.Split(severity, ",") {
+			switch level := models.SeverityLevel(s); level {
+			case models.ErrorSeverity, models.WarningSeverity:
+				severities = append(severities, level)
+			default:
+				RespondWithError(w, http.StatusBadRequest, "Invalid severity: "+s)
+				return
+			}
+		}
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	namespaceValidations, err := business.Validations.GetMeshValidations()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	meshValidations := models.MeshValidations{
+		Summaries:   make(map[string]models.IstioValidationSummary, len(namespaceValidations)),
+		Validations: make(models.NamespaceValidations, len(namespaceValidations)),
+	}
+	for namespace, validations := range namespaceValidations {
+		if len(objectTypes) > 0 {
+			validations = validations.FilterByTypes(objectTypes)
+		}
+		// Summaries count all the checks of the selected types, so severities are filtered afterwards
+		meshValidations.Summaries[namespace] = validations.SummarizeValidation(namespace)
+		if len(severities) > 0 {
+			validations = validations.FilterBySeverities(severities)
+		}
+		meshValidations.Validations[namespace] = validations
+	}
+
+	RespondWithJSON(w, http.StatusOK, meshValidations)
+}
+
+// NamespaceValidationsDryRun is the API handler to validate a namespace as if a proposed change of an
+// Istio object was applied. The change is applied in memory only, the cluster is not modified.
+func NamespaceValidationsDryRun(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+
+	var change models.IstioConfigChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Dry run request could not be read: "+err.Error())
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dryRunValidations, err := business.Validations.DryRunValidations(namespace, change)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else if errors.IsAlreadyExists(err) {
+			RespondWithError(w, http.StatusConflict, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, dryRunValidations)
+}
+
 // NamespaceUpdate is the API to perform a patch on a Namespace configuration
 func NamespaceUpdate(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -32,6 +32,27 @@ func ServiceList(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, serviceList)
 }
 
+// ServiceEvents is the API handler to fetch the recent events of a service and its endpoints
+func ServiceEvents(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespace := params["namespace"]
+	service := params["service"]
+
+	events, err := business.Svc.GetServiceEvents(namespace, service)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, events)
+}
+
 // ServiceDetails is the API handler to fetch full details of an specific service
 func ServiceDetails(w http.ResponseWriter, r *http.Request) {
 	// Get business layer
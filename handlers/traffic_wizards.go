@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/models"
+)
+
+// ServiceTrafficWizard creates or updates the VirtualService and DestinationRule of a service for a traffic wizard
+func ServiceTrafficWizard(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+	wizard := params["wizard"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	var result models.TrafficWizardResult
+	var decodeErr error
+	decoder := json.NewDecoder(r.Body)
+	switch wizard {
+	case models.WeightedRoutingWizard:
+		var routing models.WeightedRouting
+		if decodeErr = decoder.Decode(&routing); decodeErr == nil {
+			result, err = business.IstioConfig.ApplyWeightedRouting(namespace, service, routing)
+		}
+	case models.RequestRoutingWizard:
+		var routing models.RequestRouting
+		if decodeErr = decoder.Decode(&routing); decodeErr == nil {
+			result, err = business.IstioConfig.ApplyRequestRouting(namespace, service, routing)
+		}
+	case models.FaultInjectionWizard:
+		var fault models.FaultInjection
+		if decodeErr = decoder.Decode(&fault); decodeErr == nil {
+			result, err = business.IstioConfig.ApplyFaultInjection(namespace, service, fault)
+		}
+	case models.RequestTimeoutsWizard:
+		var timeouts models.RequestTimeouts
+		if decodeErr = decoder.Decode(&timeouts); decodeErr == nil {
+			result, err = business.IstioConfig.ApplyRequestTimeouts(namespace, service, timeouts)
+		}
+	case models.CircuitBreakerWizard:
+		var breaker models.CircuitBreaker
+		if decodeErr = decoder.Decode(&breaker); decodeErr == nil {
+			result, err = business.IstioConfig.ApplyCircuitBreaker(namespace, service, breaker)
+		}
+	case models.TrafficMirroringWizard:
+		var mirroring models.TrafficMirroring
+		if decodeErr = decoder.Decode(&mirroring); decodeErr == nil {
+			result, err = business.IstioConfig.ApplyTrafficMirroring(namespace, service, mirroring)
+		}
+	default:
+		RespondWithError(w, http.StatusBadRequest, "Traffic wizard not managed: "+wizard)
+		return
+	}
+	if decodeErr != nil {
+		RespondWithError(w, http.StatusBadRequest, "Traffic wizard request could not be parsed: "+decodeErr.Error())
+		return
+	}
+	if err != nil {
+		handleWizardError(w, err)
+		return
+	}
+
+	audit(r, "WIZARD "+wizard+" on Namespace: "+namespace+" Service: "+service)
+	RespondWithJSON(w, http.StatusOK, result)
+}
+
+// ServiceTrafficWizardDelete deletes the VirtualServices and DestinationRules generated by the wizards for a service
+func ServiceTrafficWizardDelete(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	if err = business.IstioConfig.DeleteTrafficWizard(namespace, service); err != nil {
+		handleWizardError(w, err)
+		return
+	}
+
+	audit(r, "DELETE WIZARD on Namespace: "+namespace+" Service: "+service)
+	RespondWithCode(w, http.StatusOK)
+}
+
+func handleWizardError(w http.ResponseWriter, err error) {
+	if errors.IsBadRequest(err) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	} else if errors.IsConflict(err) {
+		RespondWithError(w, http.StatusConflict, err.Error())
+	} else if errors.IsForbidden(err) {
+		RespondWithError(w, http.StatusForbidden, err.Error())
+	} else {
+		handleErrorResponse(w, err)
+	}
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"errors"
+	"mime"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/log"
@@ -87,5 +90,49 @@ func getBusiness(r *http.Request) (*business.Layer, error) {
 		return nil, err
 	}
 
-	return business.Get(token)
+	layer, err := business.Get(token)
+	if err != nil {
+		return nil, err
+	}
+	// Changes are recorded with the user authenticated in the request
+	layer.SetUser(r.Header.Get("Kiali-User"))
+	return layer, nil
+}
+
+// yamlMediaTypes are the media types of YAML documents
+var yamlMediaTypes = map[string]bool{
+	"application/yaml":   true,
+	"application/x-yaml": true,
+	"text/yaml":          true,
+	"text/x-yaml":        true,
+}
+
+// isYAMLContent returns true when the body of the request is YAML
+func isYAMLContent(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && yamlMediaTypes[mediaType]
+}
+
+// acceptsYAML returns true when the Accept header of the request prefers YAML to JSON.
+// JSON is preferred on equal quality, as it is the default.
+func acceptsYAML(r *http.Request) bool {
+	yamlQuality, jsonQuality := 0.0, 0.0
+	for _, mediaRange := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, found := params["q"]; found {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if yamlMediaTypes[mediaType] && quality > yamlQuality {
+			yamlQuality = quality
+		} else if mediaType == "application/json" && quality > jsonQuality {
+			jsonQuality = quality
+		}
+	}
+	return yamlQuality > jsonQuality
 }
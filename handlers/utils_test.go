@@ -93,3 +93,26 @@ func TestCreateMetricsServiceForSeveralNamespaces(t *testing.T) {
 	assert.Nil(info["nsNil"].info)
 	assert.Equal("no privileges", info["nsNil"].err.Error())
 }
+
+func TestAcceptsYAML(t *testing.T) {
+	assert := assert.New(t)
+
+	accepts := func(accept string) bool {
+		r := httptest.NewRequest("GET", "/api/namespaces/bookinfo/istio", nil)
+		r.Header.Set("Accept", accept)
+		return acceptsYAML(r)
+	}
+	assert.False(accepts(""))
+	assert.False(accepts("*/*"))
+	assert.False(accepts("application/json"))
+	assert.True(accepts("application/yaml"))
+	assert.True(accepts("text/yaml, application/json;q=0.9"))
+	assert.False(accepts("application/yaml;q=0.5, application/json"))
+	assert.False(accepts("application/json, application/yaml"))
+
+	r := httptest.NewRequest("POST", "/api/namespaces/bookinfo/istio/virtualservices", nil)
+	r.Header.Set("Content-Type", "application/x-yaml; charset=utf-8")
+	assert.True(isYAMLContent(r))
+	r.Header.Set("Content-Type", "application/json")
+	assert.False(isYAMLContent(r))
+}
@@ -1,10 +1,19 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/models"
 )
 
 // WorkloadList is the API handler to fetch all the workloads to be displayed, related to a single namespace
@@ -85,6 +94,118 @@ func WorkloadUpdate(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, workloadDetails)
 }
 
+// WorkloadRestart is the API handler to trigger a rolling restart of the pods of a workload
+func WorkloadRestart(w http.ResponseWriter, r *http.Request) {
+	workloadAction(w, r, "RESTART", func(layer *business.Layer, namespace, workload, workloadType string) (*models.Workload, error) {
+		return layer.Workload.RestartWorkload(namespace, workload, workloadType)
+	})
+}
+
+// WorkloadScale is the API handler to set the number of replicas of a workload
+func WorkloadScale(w http.ResponseWriter, r *http.Request) {
+	request := models.WorkloadScaleRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Scale request with bad body: "+err.Error())
+		return
+	}
+	if request.Replicas == nil {
+		RespondWithError(w, http.StatusBadRequest, "Scale request without replicas")
+		return
+	}
+	workloadAction(w, r, "SCALE to "+strconv.Itoa(int(*request.Replicas))+" replicas", func(layer *business.Layer, namespace, workload, workloadType string) (*models.Workload, error) {
+		return layer.Workload.ScaleWorkload(namespace, workload, workloadType, *request.Replicas)
+	})
+}
+
+// WorkloadPause is the API handler to pause the rollout of a workload
+func WorkloadPause(w http.ResponseWriter, r *http.Request) {
+	workloadAction(w, r, "PAUSE", func(layer *business.Layer, namespace, workload, workloadType string) (*models.Workload, error) {
+		return layer.Workload.PauseWorkload(namespace, workload, workloadType, true)
+	})
+}
+
+// WorkloadResume is the API handler to resume the paused rollout of a workload
+func WorkloadResume(w http.ResponseWriter, r *http.Request) {
+	workloadAction(w, r, "RESUME", func(layer *business.Layer, namespace, workload, workloadType string) (*models.Workload, error) {
+		return layer.Workload.PauseWorkload(namespace, workload, workloadType, false)
+	})
+}
+
+// workloadAction runs a lifecycle operation on the workload of the request, then responds with the workload details
+func workloadAction(w http.ResponseWriter, r *http.Request, action string, run func(layer *business.Layer, namespace, workload, workloadType string) (*models.Workload, error)) {
+	params := mux.Vars(r)
+	workloadType := r.URL.Query().Get("type")
+
+	// Get business layer
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+	namespace := params["namespace"]
+	workload := params["workload"]
+
+	workloadDetails, err := run(layer, namespace, workload, workloadType)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	audit(r, action+" on Namespace: "+namespace+" Workload name: "+workload+" Type: "+workloadDetails.Type)
+	RespondWithJSON(w, http.StatusOK, workloadDetails)
+}
+
+// WorkloadRollout is the API handler to fetch the progress and the history of the rollout of a workload
+func WorkloadRollout(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	workloadType := r.URL.Query().Get("type")
+
+	// Get business layer
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+	namespace := params["namespace"]
+	workload := params["workload"]
+
+	rollout, err := layer.Workload.GetWorkloadRollout(namespace, workload, workloadType)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// WorkloadEvents is the API handler to fetch the recent events of a workload, its ReplicaSets and its pods
+func WorkloadEvents(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	workloadType := r.URL.Query().Get("type")
+
+	// Get business layer
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+	namespace := params["namespace"]
+	workload := params["workload"]
+
+	events, err := layer.Workload.GetWorkloadEvents(namespace, workload, workloadType)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, events)
+}
+
 // PodDetails is the API handler to fetch all details to be displayed, related to a single pod
 func PodDetails(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -108,6 +229,27 @@ func PodDetails(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, podDetails)
 }
 
+// PodEvents is the API handler to fetch the recent events of a pod
+func PodEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Pods initialization error: "+err.Error())
+		return
+	}
+	namespace := vars["namespace"]
+	pod := vars["pod"]
+
+	events, err := business.Workload.GetPodEvents(namespace, pod)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, events)
+}
+
 // PodLogs is the API handler to fetch logs for a single pod container
 func PodLogs(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -123,11 +265,7 @@ func PodLogs(w http.ResponseWriter, r *http.Request) {
 	pod := vars["pod"]
 
 	// Get log options
-	opts, err := business.Workload.BuildLogOptionsCriteria(
-		queryParams.Get("container"),
-		queryParams.Get("duration"),
-		queryParams.Get("sinceTime"),
-		queryParams.Get("tailLines"))
+	opts, err := logOptionsCriteria(business, queryParams, queryParams.Get("duration"))
 
 	if err != nil {
 		handleErrorResponse(w, err)
@@ -137,9 +275,232 @@ func PodLogs(w http.ResponseWriter, r *http.Request) {
 	// Fetch pod logs
 	podLogs, err := business.Workload.GetPodLogs(namespace, pod, opts)
 	if err != nil {
-		handleErrorResponse(w, err)
+		handleLogsError(w, err)
 		return
 	}
 
 	RespondWithJSON(w, http.StatusOK, podLogs)
 }
+
+// WorkloadLogs is the API handler to fetch the logs of all the containers of the pods of a workload, merged in time order
+func WorkloadLogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	workload := vars["workload"]
+
+	// Get business layer
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workload Logs initialization error: "+err.Error())
+		return
+	}
+
+	opts, err := logOptionsCriteria(layer, r.URL.Query(), r.URL.Query().Get("duration"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	logs, err := layer.Workload.GetWorkloadLogs(namespace, workload, r.URL.Query().Get("type"), opts)
+	if err != nil {
+		handleLogsError(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, logs)
+}
+
+// logOptionsCriteria reads the log options of the query, with the filter of the entries
+func logOptionsCriteria(layer *business.Layer, queryParams url.Values, duration string) (*business.LogOptions, error) {
+	opts, err := layer.Workload.BuildLogOptionsCriteria(
+		queryParams.Get("container"),
+		duration,
+		queryParams.Get("sinceTime"),
+		queryParams.Get("tailLines"))
+	if err != nil {
+		return nil, err
+	}
+	opts.Filter = business.LogFilter{
+		Text:   queryParams.Get("filter"),
+		Regex:  queryParams.Get("regex") == "true",
+		Fields: queryParams["field"],
+	}
+	return opts, nil
+}
+
+func handleLogsError(w http.ResponseWriter, err error) {
+	if errors.IsBadRequest(err) {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+	} else {
+		handleErrorResponse(w, err)
+	}
+}
+
+// PodLogsStream is the API handler to follow the logs of a single pod container, sent as server-sent events:
+// a "log" event for each entry and an "end" event when the stream ends, before the client disconnects. Over HTTP/2
+// the stream ends before the write timeout of the server, whatever the maxDuration.
+func PodLogsStream(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+
+	// Get business layer
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Pod Logs initialization error: "+err.Error())
+		return
+	}
+	namespace := vars["namespace"]
+	pod := vars["pod"]
+
+	// Get log options, the logs are followed from the sinceTime or the tail lines
+	opts, err := logOptionsCriteria(layer, queryParams, "")
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	maxDuration := business.MaxLogStreamDuration
+	if param := queryParams.Get("maxDuration"); param != "" {
+		if maxDuration, err = time.ParseDuration(param); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid maxDuration ["+param+"]: "+err.Error())
+			return
+		}
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	entries, err := layer.Workload.StreamPodLogs(namespace, pod, opts, maxDuration, stop)
+	if err != nil {
+		handleLogsError(w, err)
+		return
+	}
+
+	stream, err := newEventStream(w, r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Pod Logs stream error: "+err.Error())
+		return
+	}
+	defer stream.Close()
+
+	for {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				_ = stream.Send("end", map[string]string{})
+				return
+			}
+			if err = stream.Send("log", entry); err != nil {
+				return
+			}
+		case <-stream.Timeout:
+			_ = stream.Send("end", map[string]string{})
+			return
+		case <-stream.Done:
+			return
+		}
+	}
+}
+
+// PodConfigDump is the API handler to fetch the configuration received by the Envoy proxy of a pod
+func PodConfigDump(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	queryParams := r.URL.Query()
+	namespace := vars["namespace"]
+	pod := vars["pod"]
+
+	criteria := business.EnvoyConfigCriteria{
+		ClusterService: queryParams.Get("service"),
+		RouteConfig:    queryParams.Get("route"),
+	}
+	if sections := queryParams.Get("sections"); sections != "" {
+		criteria.Sections = strings.Split(strings.ToLower(sections), ",")
+	}
+	if port := queryParams.Get("port"); port != "" {
+		var err error
+		if criteria.ListenerPort, err = strconv.Atoi(port); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid port ["+port+"]: "+err.Error())
+			return
+		}
+	}
+
+	// Get business layer
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Pods initialization error: "+err.Error())
+		return
+	}
+
+	configDump, err := layer.Workload.GetConfigDump(namespace, pod, criteria)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else if errors.IsForbidden(err) {
+			RespondWithError(w, http.StatusForbidden, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, configDump)
+}
+
+// PodProxyLogging is the API handler to fetch the log level of each logger of the Envoy proxy of a pod
+func PodProxyLogging(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	pod := vars["pod"]
+
+	// Get business layer
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Pods initialization error: "+err.Error())
+		return
+	}
+
+	levels, err := layer.Workload.GetProxyLogLevels(namespace, pod)
+	if err != nil {
+		if errors.IsForbidden(err) {
+			RespondWithError(w, http.StatusForbidden, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, levels)
+}
+
+// PodProxyLoggingUpdate is the API handler to change the log level of the Envoy proxy of a pod
+func PodProxyLoggingUpdate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	pod := vars["pod"]
+
+	// Get business layer
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Pods initialization error: "+err.Error())
+		return
+	}
+
+	var request models.EnvoyLogLevelRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Log level request could not be parsed: "+err.Error())
+		return
+	}
+
+	levels, err := layer.Workload.SetProxyLogLevel(namespace, pod, request)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else if errors.IsForbidden(err) {
+			RespondWithError(w, http.StatusForbidden, err.Error())
+		} else {
+			handleErrorResponse(w, err)
+		}
+		return
+	}
+
+	audit(r, "PROXY LOG LEVEL on Namespace: "+namespace+" Pod: "+pod+" Logger: "+request.Logger+" Level: "+request.Level+" Reset after: "+request.ResetAfter)
+	RespondWithJSON(w, http.StatusOK, levels)
+}
@@ -60,6 +60,8 @@ func TestWorkloadsEndpoint(t *testing.T) {
 	k8s.On("GetDeploymentConfigs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]osapps_v1.DeploymentConfig{}, nil)
 	k8s.On("GetReplicationControllers", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]core_v1.ReplicationController{}, nil)
 	k8s.On("GetStatefulSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.StatefulSet{}, nil)
+	k8s.On("GetDaemonSets", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]apps_v1.DaemonSet{}, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
 	k8s.On("GetJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1.Job{}, nil)
 	k8s.On("GetCronJobs", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return([]batch_v1beta1.CronJob{}, nil)
 	k8s.On("GetPods", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(business.FakePodsSyncedWithDeployments(), nil)
@@ -172,6 +174,59 @@ func TestWorkloadMetricsWithParams(t *testing.T) {
 	assert.NotZero(t, gaugeSentinel)
 }
 
+func TestWorkloadMetricsContainers(t *testing.T) {
+	ts, api, k8s := setupWorkloadMetricsEndpoint(t)
+	defer ts.Close()
+
+	pods := business.FakePodsSyncedWithDeployments()
+	dep := business.FakeDepSyncedWithRS()[0]
+	dep.Spec.Template.Labels = pods[0].Labels
+	k8s.On("GetDeployment", "ns", "details-v1").Return(&dep, nil)
+	k8s.On("GetReplicaSets", "ns").Return(business.FakeRSSyncedWithPods(), nil)
+	k8s.On("GetPods", "ns", mock.AnythingOfType("string")).Return(pods, nil)
+	k8s.On("IsArgoRolloutsApi").Return(false)
+	k8s.MockEmptyWorkload("ns", "details-v1")
+
+	req, err := http.NewRequest("GET", ts.URL+"/api/namespaces/ns/workloads/details-v1/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := req.URL.Query()
+	q.Add("filters[]", "request_count")
+	q.Add("filters[]", "container_cpu_usage")
+	q.Add("filters[]", "container_memory_limits")
+	req.URL.RawQuery = q.Encode()
+
+	var containerSentinel uint32
+	api.SpyArgumentsAndReturnEmpty(func(args mock.Arguments) {
+		query := args[1].(string)
+		if strings.Contains(query, "istio_requests_total") {
+			return
+		}
+		assert.Contains(t, query, `namespace="ns",pod=~"details-v1-3618568057-dnkjp",container!="",container!="POD"`)
+		assert.Contains(t, query, " by (container)")
+		if strings.Contains(query, "container_cpu_usage_seconds_total") {
+			assert.Contains(t, query, "[1m]")
+		} else {
+			assert.Contains(t, query, `kube_pod_container_resource_limits{`)
+			assert.Contains(t, query, `resource="memory"`)
+		}
+		atomic.AddUint32(&containerSentinel, 1)
+	})
+
+	httpclient := &http.Client{}
+	resp, err := httpclient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, 200, resp.StatusCode, string(actual))
+	assert.Contains(t, string(actual), "container_cpu_usage")
+	assert.Contains(t, string(actual), "request_count")
+	assert.Equal(t, uint32(2), containerSentinel)
+}
+
 func TestWorkloadMetricsBadQueryTime(t *testing.T) {
 	ts, api, _ := setupWorkloadMetricsEndpoint(t)
 	defer ts.Close()
@@ -337,3 +392,41 @@ func setupWorkloadMetricsEndpoint(t *testing.T) (*httptest.Server, *prometheuste
 
 	return ts, api, k8s
 }
+
+func TestPodLogsStream(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	k8s := kubetest.NewK8SClientMock()
+	k8s.On("StreamPodLogs", "ns", "details-v1", mock.Anything).Return(ioutil.NopCloser(strings.NewReader(
+		"2018-01-02T03:34:28+00:00 INFO #1 Log Message\n2018-01-02T04:34:28+00:00 WARN #2 Log Message\n")), nil)
+	mockClientFactory := kubetest.NewK8SClientFactoryMock(k8s)
+	business.SetWithBackends(mockClientFactory, nil)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/{namespace}/pods/{pod}/logs/stream", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "token", "test")
+			PodLogsStream(w, r.WithContext(context))
+		}))
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/pods/details-v1/logs/stream?container=details&maxDuration=1m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, 200, resp.StatusCode, string(actual))
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, 2, strings.Count(string(actual), "event: log\n"))
+	assert.Contains(t, string(actual), `data: {"message":"WARN #2 Log Message","severity":"WARN"`)
+	assert.True(t, strings.HasSuffix(string(actual), "event: end\ndata: {}\n\n"))
+
+	resp, err = http.Get(ts.URL + "/api/namespaces/ns/pods/details-v1/logs/stream?maxDuration=2h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 400, resp.StatusCode)
+}
@@ -25,6 +25,9 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/business/checkers/custom"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
@@ -164,6 +167,21 @@ func validateConfig() error {
 		return err
 	}
 
+	// Custom rules are parsed once, at load
+	if err := custom.LoadRules(config.Get().Validations.CustomRules); err != nil {
+		return err
+	}
+	if sidecarTraffic := config.Get().Validations.SidecarTraffic; sidecarTraffic.Enabled {
+		if _, err := model.ParseDuration(sidecarTraffic.Lookback); err != nil {
+			return fmt.Errorf("invalid sidecar traffic validations lookback [%v]: %v", sidecarTraffic.Lookback, err)
+		}
+	}
+	if history := config.Get().IstioConfigHistory; history.Enabled {
+		if history.Store != config.IstioConfigHistoryConfigMapStore && history.Store != config.IstioConfigHistoryMemoryStore {
+			return fmt.Errorf("istio config history store [%v] not supported, it must be one of: %v, %v", history.Store, config.IstioConfigHistoryConfigMapStore, config.IstioConfigHistoryMemoryStore)
+		}
+	}
+
 	return nil
 }
 
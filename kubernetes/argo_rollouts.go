@@ -0,0 +1,149 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ArgoRolloutPodHashLabel is the label set by Argo Rollouts on the ReplicaSets and the pods of each revision
+const ArgoRolloutPodHashLabel = "rollouts-pod-template-hash"
+
+type ArgoRolloutsClientInterface interface {
+	GetArgoRollout(namespace, name string) (*ArgoRollout, error)
+	GetArgoRollouts(namespace string) ([]ArgoRollout, error)
+	IsArgoRolloutsApi() bool
+}
+
+// Linked with https://github.com/argoproj/argo-rollouts/blob/master/pkg/apis/rollouts/v1alpha1/types.go
+// Only the fields used by Kiali are mapped
+type ArgoRollout struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+	Spec               ArgoRolloutSpec   `json:"spec"`
+	Status             ArgoRolloutStatus `json:"status,omitempty"`
+}
+
+type ArgoRolloutList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata,omitempty"`
+	Items            []ArgoRollout `json:"items"`
+}
+
+type ArgoRolloutSpec struct {
+	Replicas *int32                  `json:"replicas,omitempty"`
+	Selector *meta_v1.LabelSelector  `json:"selector"`
+	Template core_v1.PodTemplateSpec `json:"template"`
+	Paused   bool                    `json:"paused,omitempty"`
+	Strategy struct {
+		BlueGreen *ArgoRolloutBlueGreenStrategy `json:"blueGreen,omitempty"`
+		Canary    *ArgoRolloutCanaryStrategy    `json:"canary,omitempty"`
+	} `json:"strategy"`
+}
+
+type ArgoRolloutBlueGreenStrategy struct {
+	ActiveService  string `json:"activeService"`
+	PreviewService string `json:"previewService,omitempty"`
+}
+
+type ArgoRolloutCanaryStrategy struct {
+	CanaryService string            `json:"canaryService,omitempty"`
+	StableService string            `json:"stableService,omitempty"`
+	Steps         []ArgoRolloutStep `json:"steps,omitempty"`
+}
+
+// ArgoRolloutStep is a step of a canary rollout. Only one of the fields is set.
+type ArgoRolloutStep struct {
+	SetWeight      *int32            `json:"setWeight,omitempty"`
+	Pause          *ArgoRolloutPause `json:"pause,omitempty"`
+	Analysis       *json.RawMessage  `json:"analysis,omitempty"`
+	Experiment     *json.RawMessage  `json:"experiment,omitempty"`
+	SetCanaryScale *json.RawMessage  `json:"setCanaryScale,omitempty"`
+}
+
+// ArgoRolloutPause is a pause step, indefinite when the duration is not set
+type ArgoRolloutPause struct {
+	Duration *intstr.IntOrString `json:"duration,omitempty"`
+}
+
+type ArgoRolloutStatus struct {
+	Abort             bool   `json:"abort,omitempty"`
+	Replicas          int32  `json:"replicas,omitempty"`
+	UpdatedReplicas   int32  `json:"updatedReplicas,omitempty"`
+	ReadyReplicas     int32  `json:"readyReplicas,omitempty"`
+	AvailableReplicas int32  `json:"availableReplicas,omitempty"`
+	CurrentStepIndex  *int32 `json:"currentStepIndex,omitempty"`
+	CurrentPodHash    string `json:"currentPodHash,omitempty"`
+	// Pod template hash of the stable ReplicaSet
+	StableRS string `json:"stableRS,omitempty"`
+	Phase    string `json:"phase,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// IsArgoRolloutsApi checks if the Argo Rollouts CRDs are installed in the cluster
+func (in *K8SClient) IsArgoRolloutsApi() bool {
+	if in.isArgoRolloutsApi == nil {
+		isArgoRolloutsApi := false
+		// Other Argo projects share the API group, i.e. Argo Workflows: the rollouts resource is looked up
+		raw, err := in.k8s.RESTClient().Get().AbsPath("/apis/" + ArgoRolloutsGroupVersion.String()).Do().Raw()
+		if err == nil {
+			resources := meta_v1.APIResourceList{}
+			if err = json.Unmarshal(raw, &resources); err == nil {
+				for _, r := range resources.APIResources {
+					if r.Name == "rollouts" {
+						isArgoRolloutsApi = true
+						break
+					}
+				}
+			}
+		}
+		in.isArgoRolloutsApi = &isArgoRolloutsApi
+	}
+	return *in.isArgoRolloutsApi
+}
+
+func (in *K8SClient) GetArgoRollout(namespace, name string) (*ArgoRollout, error) {
+	if !in.IsArgoRolloutsApi() {
+		return nil, NewNotFound(name, ArgoRolloutsGroupVersion.Group, RolloutType)
+	}
+	raw, err := in.k8s.RESTClient().Get().AbsPath(argoRolloutsPath(namespace, name)).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	rollout := &ArgoRollout{}
+	if err = json.Unmarshal(raw, rollout); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+func (in *K8SClient) GetArgoRollouts(namespace string) ([]ArgoRollout, error) {
+	if !in.IsArgoRolloutsApi() {
+		return []ArgoRollout{}, nil
+	}
+	raw, err := in.k8s.RESTClient().Get().AbsPath(argoRolloutsPath(namespace, "")).DoRaw()
+	if err != nil {
+		return []ArgoRollout{}, err
+	}
+	list := &ArgoRolloutList{}
+	if err = json.Unmarshal(raw, list); err != nil {
+		return []ArgoRollout{}, err
+	}
+	return list.Items, nil
+}
+
+func (in *K8SClient) patchArgoRollout(namespace, name string, bytePatch []byte) error {
+	return in.k8s.RESTClient().Patch(types.MergePatchType).AbsPath(argoRolloutsPath(namespace, name)).Body(bytePatch).Do().Error()
+}
+
+func argoRolloutsPath(namespace, name string) string {
+	path := fmt.Sprintf("/apis/%s/namespaces/%s/rollouts", ArgoRolloutsGroupVersion.String(), namespace)
+	if name != "" {
+		path += "/" + name
+	}
+	return path
+}
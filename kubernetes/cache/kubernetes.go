@@ -20,6 +20,8 @@ type (
 		GetDeployment(namespace, name string) (*apps_v1.Deployment, error)
 		GetStatefulSets(namespace string) ([]apps_v1.StatefulSet, error)
 		GetStatefulSet(namespace, name string) (*apps_v1.StatefulSet, error)
+		GetDaemonSets(namespace string) ([]apps_v1.DaemonSet, error)
+		GetDaemonSet(namespace, name string) (*apps_v1.DaemonSet, error)
 		GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error)
 		GetPods(namespace, labelSelector string) ([]core_v1.Pod, error)
 		GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet, error)
@@ -30,6 +32,7 @@ func (c *kialiCacheImpl) createKubernetesInformers(namespace string, informer *t
 	sharedInformers := informers.NewSharedInformerFactoryWithOptions(c.k8sApi, c.refreshDuration, informers.WithNamespace(namespace))
 	(*informer)[kubernetes.DeploymentType] = sharedInformers.Apps().V1().Deployments().Informer()
 	(*informer)[kubernetes.StatefulSetType] = sharedInformers.Apps().V1().StatefulSets().Informer()
+	(*informer)[kubernetes.DaemonSetType] = sharedInformers.Apps().V1().DaemonSets().Informer()
 	(*informer)[kubernetes.ReplicaSetType] = sharedInformers.Apps().V1().ReplicaSets().Informer()
 	(*informer)[kubernetes.ServiceType] = sharedInformers.Core().V1().Services().Informer()
 	(*informer)[kubernetes.PodType] = sharedInformers.Core().V1().Pods().Informer()
@@ -41,6 +44,7 @@ func (c *kialiCacheImpl) isKubernetesSynced(namespace string) bool {
 	if nsCache, exist := c.nsCache[namespace]; exist {
 		isSynced = nsCache[kubernetes.DeploymentType].HasSynced() &&
 			nsCache[kubernetes.StatefulSetType].HasSynced() &&
+			nsCache[kubernetes.DaemonSetType].HasSynced() &&
 			nsCache[kubernetes.ReplicaSetType].HasSynced() &&
 			nsCache[kubernetes.ServiceType].HasSynced() &&
 			nsCache[kubernetes.PodType].HasSynced() &&
@@ -151,6 +155,46 @@ func (c *kialiCacheImpl) GetStatefulSet(namespace, name string) (*apps_v1.Statef
 	return nil, nil
 }
 
+func (c *kialiCacheImpl) GetDaemonSets(namespace string) ([]apps_v1.DaemonSet, error) {
+	if nsCache, ok := c.nsCache[namespace]; ok {
+		ds := nsCache[kubernetes.DaemonSetType].GetStore().List()
+		lenDs := len(ds)
+		if lenDs > 0 {
+			_, ok := ds[0].(*apps_v1.DaemonSet)
+			if !ok {
+				return nil, errors.New("bad DaemonSet type found in cache")
+			}
+			nsDs := make([]apps_v1.DaemonSet, lenDs)
+			for i, d := range ds {
+				nsDs[i] = *(d.(*apps_v1.DaemonSet))
+			}
+			log.Tracef("[Kiali Cache] Get [resource: DaemonSet] for [namespace: %s] = %d", namespace, lenDs)
+			return nsDs, nil
+		}
+	}
+	return []apps_v1.DaemonSet{}, nil
+}
+
+func (c *kialiCacheImpl) GetDaemonSet(namespace, name string) (*apps_v1.DaemonSet, error) {
+	if nsCache, ok := c.nsCache[namespace]; ok {
+		// Cache stores natively items with namespace/name pattern, we can skip the Indexer by name and make a direct call
+		key := namespace + "/" + name
+		obj, exist, err := nsCache[kubernetes.DaemonSetType].GetStore().GetByKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			ds, ok := obj.(*apps_v1.DaemonSet)
+			if !ok {
+				return nil, errors.New("bad DaemonSet type found in cache")
+			}
+			log.Tracef("[Kiali Cache] Get [resource: DaemonSet] for [namespace: %s] [name: %s]", namespace, name)
+			return ds, nil
+		}
+	}
+	return nil, nil
+}
+
 func (c *kialiCacheImpl) GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error) {
 	if nsCache, ok := c.nsCache[namespace]; ok {
 		services := nsCache[kubernetes.ServiceType].GetStore().List()
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"strings"
@@ -46,22 +47,31 @@ type IstioClientInterface interface {
 	GetIstioObjects(namespace, resourceType, labelSelector string) ([]IstioObject, error)
 	UpdateIstioObject(api, namespace, resourceType, name, jsonPatch string) (IstioObject, error)
 	GetProxyStatus() ([]*ProxyStatus, error)
+	GetConfigDump(namespace, podName string) (*ConfigDump, error)
+	SetProxyLogLevel(namespace, podName, logger, level string) (map[string]string, error)
 }
 
 type K8SClientInterface interface {
+	CreateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error)
 	GetConfigMap(namespace, configName string) (*core_v1.ConfigMap, error)
+	GetControllerRevisions(namespace string) ([]apps_v1.ControllerRevision, error)
 	GetCronJobs(namespace string) ([]batch_v1beta1.CronJob, error)
+	GetDaemonSet(namespace string, daemonsetName string) (*apps_v1.DaemonSet, error)
+	GetDaemonSets(namespace string) ([]apps_v1.DaemonSet, error)
 	GetDeployment(namespace string, deploymentName string) (*apps_v1.Deployment, error)
 	GetDeployments(namespace string) ([]apps_v1.Deployment, error)
 	GetDeploymentsByLabel(namespace string, labelSelector string) ([]apps_v1.Deployment, error)
 	GetDeploymentConfig(namespace string, deploymentconfigName string) (*osapps_v1.DeploymentConfig, error)
 	GetDeploymentConfigs(namespace string) ([]osapps_v1.DeploymentConfig, error)
 	GetEndpoints(namespace string, serviceName string) (*core_v1.Endpoints, error)
+	GetEvents(namespace string) ([]core_v1.Event, error)
 	GetJobs(namespace string) ([]batch_v1.Job, error)
 	GetNamespace(namespace string) (*core_v1.Namespace, error)
 	GetNamespaces(labelSelector string) ([]core_v1.Namespace, error)
+	GetObjectEvents(namespace, kind, name string) ([]core_v1.Event, error)
 	GetPod(namespace, name string) (*core_v1.Pod, error)
 	GetPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (*PodLogs, error)
+	StreamPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (io.ReadCloser, error)
 	GetPods(namespace, labelSelector string) ([]core_v1.Pod, error)
 	GetReplicationControllers(namespace string) ([]core_v1.ReplicationController, error)
 	GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet, error)
@@ -70,6 +80,7 @@ type K8SClientInterface interface {
 	GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error)
 	GetStatefulSet(namespace string, statefulsetName string) (*apps_v1.StatefulSet, error)
 	GetStatefulSets(namespace string) ([]apps_v1.StatefulSet, error)
+	UpdateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error)
 	UpdateNamespace(namespace string, jsonPatch string) (*core_v1.Namespace, error)
 	UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) error
 }
@@ -90,6 +101,7 @@ type ClientInterface interface {
 	IstioClientInterface
 	Iter8ClientInterface
 	OSClientInterface
+	ArgoRolloutsClientInterface
 }
 
 // K8SClient is the client struct for Kubernetes and Istio APIs
@@ -97,6 +109,7 @@ type ClientInterface interface {
 type K8SClient struct {
 	ClientInterface
 	token              string
+	restConfig         *rest.Config
 	k8s                *kube.Clientset
 	istioNetworkingApi *rest.RESTClient
 	istioSecurityApi   *rest.RESTClient
@@ -111,6 +124,11 @@ type K8SClient struct {
 	// See iter8.go#IsIter8Api() for more details
 	isIter8Api *bool
 
+	// isArgoRolloutsApi private variable will check if extension Argo Rollouts API is present.
+	// It is represented as a pointer to include the initialization phase.
+	// See argo_rollouts.go#IsArgoRolloutsApi() for more details
+	isArgoRolloutsApi *bool
+
 	// networkingResources private variable will check which resources kiali has access to from networking.istio.io group
 	// It is represented as a pointer to include the initialization phase.
 	// See istio_details_service.go#hasNetworkingResource() for more details.
@@ -215,7 +233,8 @@ func ConfigClient() (*rest.Config, error) {
 // It returns an error on any problem.
 func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 	client := K8SClient{
-		token: config.BearerToken,
+		token:      config.BearerToken,
+		restConfig: config,
 	}
 	log.Debugf("Rest perf config QPS: %f Burst: %d", config.QPS, config.Burst)
 
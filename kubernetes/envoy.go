@@ -0,0 +1,261 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// IstioProxyContainerName is the name of the container of the Istio proxy, in sidecars and gateways
+const IstioProxyContainerName = "istio-proxy"
+
+// ConfigDump is the config_dump of the Envoy admin interface of a proxy, with the status of the cluster endpoints.
+// Only the fields used by Kiali are parsed; each config of the dump fills the fields of its @type.
+type ConfigDump struct {
+	Configs         []EnvoyConfig        `json:"configs"`
+	ClusterStatuses []EnvoyClusterStatus `json:"cluster_statuses"`
+}
+
+// EnvoyConfig is one of the configs of a config_dump: bootstrap, clusters, listeners or routes
+type EnvoyConfig struct {
+	Type string `json:"@type"`
+
+	// BootstrapConfigDump
+	Bootstrap *EnvoyBootstrap `json:"bootstrap,omitempty"`
+
+	// ClustersConfigDump
+	StaticClusters        []EnvoyClusterState `json:"static_clusters,omitempty"`
+	DynamicActiveClusters []EnvoyClusterState `json:"dynamic_active_clusters,omitempty"`
+
+	// ListenersConfigDump. Envoy v2 API lists the dynamic listeners as dynamic_active_listeners.
+	StaticListeners        []EnvoyListenerState   `json:"static_listeners,omitempty"`
+	DynamicListeners       []EnvoyDynamicListener `json:"dynamic_listeners,omitempty"`
+	DynamicActiveListeners []EnvoyListenerState   `json:"dynamic_active_listeners,omitempty"`
+
+	// RoutesConfigDump
+	StaticRouteConfigs  []EnvoyRouteConfigState `json:"static_route_configs,omitempty"`
+	DynamicRouteConfigs []EnvoyRouteConfigState `json:"dynamic_route_configs,omitempty"`
+}
+
+type EnvoyBootstrap struct {
+	Node struct {
+		ID       string                 `json:"id"`
+		Cluster  string                 `json:"cluster"`
+		Metadata map[string]interface{} `json:"metadata"`
+	} `json:"node"`
+}
+
+type EnvoyClusterState struct {
+	VersionInfo string       `json:"version_info"`
+	LastUpdated string       `json:"last_updated"`
+	Cluster     EnvoyCluster `json:"cluster"`
+}
+
+type EnvoyCluster struct {
+	Name             string `json:"name"`
+	Type             string `json:"type"`
+	ConnectTimeout   string `json:"connect_timeout"`
+	EdsClusterConfig *struct {
+		ServiceName string `json:"service_name"`
+	} `json:"eds_cluster_config,omitempty"`
+	Metadata struct {
+		FilterMetadata map[string]map[string]interface{} `json:"filter_metadata"`
+	} `json:"metadata"`
+}
+
+type EnvoyDynamicListener struct {
+	Name        string              `json:"name"`
+	ActiveState *EnvoyListenerState `json:"active_state,omitempty"`
+}
+
+type EnvoyListenerState struct {
+	VersionInfo string        `json:"version_info"`
+	LastUpdated string        `json:"last_updated"`
+	Listener    EnvoyListener `json:"listener"`
+}
+
+type EnvoyListener struct {
+	Name         string             `json:"name"`
+	Address      EnvoyAddress       `json:"address"`
+	FilterChains []EnvoyFilterChain `json:"filter_chains"`
+}
+
+type EnvoyAddress struct {
+	SocketAddress struct {
+		Address   string `json:"address"`
+		PortValue int    `json:"port_value"`
+	} `json:"socket_address"`
+}
+
+type EnvoyFilterChain struct {
+	FilterChainMatch map[string]interface{} `json:"filter_chain_match,omitempty"`
+	Filters          []EnvoyFilter          `json:"filters"`
+}
+
+// EnvoyFilter is a network filter of a listener. Envoy v2 API may use config instead of typed_config.
+type EnvoyFilter struct {
+	Name        string             `json:"name"`
+	TypedConfig *EnvoyFilterConfig `json:"typed_config,omitempty"`
+	Config      *EnvoyFilterConfig `json:"config,omitempty"`
+}
+
+// EnvoyFilterConfig has the destination of the tcp_proxy and http_connection_manager filters
+type EnvoyFilterConfig struct {
+	Cluster string `json:"cluster,omitempty"`
+	Rds     *struct {
+		RouteConfigName string `json:"route_config_name"`
+	} `json:"rds,omitempty"`
+	RouteConfig *EnvoyRouteConfig `json:"route_config,omitempty"`
+}
+
+type EnvoyRouteConfigState struct {
+	VersionInfo string           `json:"version_info"`
+	LastUpdated string           `json:"last_updated"`
+	RouteConfig EnvoyRouteConfig `json:"route_config"`
+}
+
+type EnvoyRouteConfig struct {
+	Name         string             `json:"name"`
+	VirtualHosts []EnvoyVirtualHost `json:"virtual_hosts"`
+}
+
+type EnvoyVirtualHost struct {
+	Name    string       `json:"name"`
+	Domains []string     `json:"domains"`
+	Routes  []EnvoyRoute `json:"routes"`
+}
+
+type EnvoyRoute struct {
+	Name  string                 `json:"name"`
+	Match map[string]interface{} `json:"match"`
+	Route *struct {
+		Cluster          string `json:"cluster"`
+		Timeout          string `json:"timeout"`
+		WeightedClusters *struct {
+			Clusters []struct {
+				Name   string `json:"name"`
+				Weight int    `json:"weight"`
+			} `json:"clusters"`
+		} `json:"weighted_clusters,omitempty"`
+	} `json:"route,omitempty"`
+	Redirect       map[string]interface{} `json:"redirect,omitempty"`
+	DirectResponse map[string]interface{} `json:"direct_response,omitempty"`
+}
+
+// EnvoyClusterStatus is the status of a cluster in the clusters endpoint of the Envoy admin interface
+type EnvoyClusterStatus struct {
+	Name         string `json:"name"`
+	HostStatuses []struct {
+		Address      EnvoyAddress `json:"address"`
+		Weight       int          `json:"weight"`
+		HealthStatus struct {
+			EdsHealthStatus         string `json:"eds_health_status"`
+			FailedActiveHealthCheck bool   `json:"failed_active_health_check"`
+			FailedOutlierCheck      bool   `json:"failed_outlier_check"`
+		} `json:"health_status"`
+	} `json:"host_statuses"`
+}
+
+// GetConfigDump returns the config_dump of the Envoy proxy of a pod, with the endpoints of the clusters and their health.
+// See envoyAdminRequest for the transport and the permissions required.
+func (in *K8SClient) GetConfigDump(namespace, podName string) (*ConfigDump, error) {
+	configDump, err := in.envoyAdminRequest(namespace, podName, "GET", "config_dump", nil)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := in.envoyAdminRequest(namespace, podName, "GET", "clusters", url.Values{"format": []string{"json"}})
+	if err != nil {
+		return nil, err
+	}
+	return getConfigDump(configDump, clusters)
+}
+
+// SetProxyLogLevel changes the level of a logger of the Envoy proxy of a pod, or of all its loggers when logger is empty,
+// through the /logging endpoint of the admin interface. Without level, the loggers are only listed.
+// It returns the level of each logger after the change. See envoyAdminRequest for the transport and the permissions required.
+func (in *K8SClient) SetProxyLogLevel(namespace, podName, logger, level string) (map[string]string, error) {
+	params := url.Values{}
+	if level != "" {
+		if logger == "" {
+			params.Set("level", level)
+		} else {
+			params.Set(logger, level)
+		}
+	}
+	res, err := in.envoyAdminRequest(namespace, podName, "POST", "logging", params)
+	if err != nil {
+		return nil, err
+	}
+	return parseProxyLoggers(res), nil
+}
+
+// envoyAdminRequest sends a request to a path of the Envoy admin interface of a pod and returns the response body.
+// The admin interface only listens on localhost, so it can't be reached through the pods proxy subresource: the request
+// is sent from the istio-proxy container by pilot-agent, through the pods exec subresource. The client must be allowed
+// to create pods/exec in the namespace of the pod.
+func (in *K8SClient) envoyAdminRequest(namespace, podName, method, path string, params url.Values) ([]byte, error) {
+	request := in.k8s.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("pods").
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&core_v1.PodExecOptions{
+			Container: IstioProxyContainerName,
+			Command:   envoyAdminCommand(method, path, params),
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(in.restConfig, "POST", request.URL())
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	if err = executor.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return nil, fmt.Errorf("error requesting /%s of the Envoy admin interface of pod %s: %v %s", path, podName, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// envoyAdminCommand returns the pilot-agent command sending a request to a path of the Envoy admin interface
+func envoyAdminCommand(method, path string, params url.Values) []string {
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	return []string{"pilot-agent", "request", method, path}
+}
+
+func getConfigDump(configDump, clusters []byte) (*ConfigDump, error) {
+	dump := &ConfigDump{}
+	if err := json.Unmarshal(configDump, dump); err != nil {
+		return nil, fmt.Errorf("invalid Envoy config_dump: %v", err)
+	}
+	if err := json.Unmarshal(clusters, dump); err != nil {
+		return nil, fmt.Errorf("invalid Envoy clusters: %v", err)
+	}
+	return dump, nil
+}
+
+// parseProxyLoggers parses the list of the /logging endpoint:
+//
+//	active loggers:
+//	  admin: warning
+//	  rbac: debug
+func parseProxyLoggers(res []byte) map[string]string {
+	loggers := map[string]string{}
+	for _, line := range strings.Split(string(res), "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if len(parts) != 2 || parts[0] == "active loggers" {
+			continue
+		}
+		if level := strings.TrimSpace(parts[1]); level != "" {
+			loggers[parts[0]] = level
+		}
+	}
+	return loggers
+}
@@ -0,0 +1,56 @@
+package kubernetes
+
+import (
+	"io/ioutil"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProxyLoggers(t *testing.T) {
+	assert := assert.New(t)
+
+	loggers := parseProxyLoggers([]byte("active loggers:\n  admin: warning\n  http: debug\n  rbac: warning\n"))
+	assert.Equal(map[string]string{"admin": "warning", "http": "debug", "rbac": "warning"}, loggers)
+	assert.Empty(parseProxyLoggers([]byte("")))
+}
+
+func TestEnvoyAdminCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"pilot-agent", "request", "GET", "config_dump"}, envoyAdminCommand("GET", "config_dump", nil))
+	assert.Equal([]string{"pilot-agent", "request", "POST", "logging?rbac=debug"}, envoyAdminCommand("POST", "logging", url.Values{"rbac": []string{"debug"}}))
+}
+
+// The fixtures are the output of an Istio 1.7 sidecar, trimmed to a cluster, a listener and a route
+func TestGetConfigDump(t *testing.T) {
+	assert := assert.New(t)
+
+	configDump, err := ioutil.ReadFile("testdata/config_dump.json")
+	assert.NoError(err)
+	clusters, err := ioutil.ReadFile("testdata/clusters.json")
+	assert.NoError(err)
+
+	dump, err := getConfigDump(configDump, clusters)
+	assert.NoError(err)
+	assert.Len(dump.Configs, 4)
+	assert.Equal("reviews.bookinfo", dump.Configs[0].Bootstrap.Node.Cluster)
+	assert.Equal("prometheus_stats", dump.Configs[1].StaticClusters[0].Cluster.Name)
+	cluster := dump.Configs[1].DynamicActiveClusters[0].Cluster
+	assert.Equal("outbound|9080||ratings.bookinfo.svc.cluster.local", cluster.Name)
+	assert.Equal("outbound|9080||ratings.bookinfo.svc.cluster.local", cluster.EdsClusterConfig.ServiceName)
+	assert.Contains(cluster.Metadata.FilterMetadata, "istio")
+	listener := dump.Configs[2].DynamicListeners[0].ActiveState.Listener
+	assert.Equal(9080, listener.Address.SocketAddress.PortValue)
+	assert.Equal("9080", listener.FilterChains[0].Filters[0].TypedConfig.Rds.RouteConfigName)
+	route := dump.Configs[3].DynamicRouteConfigs[0].RouteConfig.VirtualHosts[0].Routes[0]
+	assert.Equal("outbound|9080||ratings.bookinfo.svc.cluster.local", route.Route.Cluster)
+
+	assert.Len(dump.ClusterStatuses, 1)
+	assert.Equal("10.244.0.15", dump.ClusterStatuses[0].HostStatuses[0].Address.SocketAddress.Address)
+	assert.Equal("HEALTHY", dump.ClusterStatuses[0].HostStatuses[0].HealthStatus.EdsHealthStatus)
+
+	_, err = getConfigDump([]byte("upstream connect error"), clusters)
+	assert.Error(err)
+}
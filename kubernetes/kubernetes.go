@@ -3,6 +3,8 @@ package kubernetes
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"strings"
 
 	osapps_v1 "github.com/openshift/api/apps/v1"
 	osproject_v1 "github.com/openshift/api/project/v1"
@@ -14,6 +16,7 @@ import (
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -32,6 +35,17 @@ func (in *K8SClient) GetConfigMap(namespace, configName string) (*core_v1.Config
 	return configMap, nil
 }
 
+// CreateConfigMap creates the ConfigMap in the cluster
+func (in *K8SClient) CreateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	return in.k8s.CoreV1().ConfigMaps(namespace).Create(configMap)
+}
+
+// UpdateConfigMap replaces the ConfigMap in the cluster.
+// The update fails with a conflict if the ConfigMap was modified since its resourceVersion.
+func (in *K8SClient) UpdateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	return in.k8s.CoreV1().ConfigMaps(namespace).Update(configMap)
+}
+
 // GetNamespace fetches and returns the specified namespace definition
 // from the cluster
 func (in *K8SClient) GetNamespace(namespace string) (*core_v1.Namespace, error) {
@@ -210,6 +224,28 @@ func (in *K8SClient) GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet, err
 	}
 }
 
+func (in *K8SClient) GetDaemonSet(namespace string, daemonsetName string) (*apps_v1.DaemonSet, error) {
+	return in.k8s.AppsV1().DaemonSets(namespace).Get(daemonsetName, emptyGetOptions)
+}
+
+func (in *K8SClient) GetDaemonSets(namespace string) ([]apps_v1.DaemonSet, error) {
+	if dsList, err := in.k8s.AppsV1().DaemonSets(namespace).List(emptyListOptions); err == nil {
+		return dsList.Items, nil
+	} else {
+		return []apps_v1.DaemonSet{}, err
+	}
+}
+
+// GetControllerRevisions returns the revisions of the StatefulSets (and DaemonSets) of a namespace.
+// The history of a StatefulSet is kept in the ControllerRevisions it owns.
+func (in *K8SClient) GetControllerRevisions(namespace string) ([]apps_v1.ControllerRevision, error) {
+	if crList, err := in.k8s.AppsV1().ControllerRevisions(namespace).List(emptyListOptions); err == nil {
+		return crList.Items, nil
+	} else {
+		return []apps_v1.ControllerRevision{}, err
+	}
+}
+
 func (in *K8SClient) GetStatefulSet(namespace string, statefulsetName string) (*apps_v1.StatefulSet, error) {
 	return in.k8s.AppsV1().StatefulSets(namespace).Get(statefulsetName, emptyGetOptions)
 }
@@ -242,6 +278,27 @@ func (in *K8SClient) GetEndpoints(namespace, serviceName string) (*core_v1.Endpo
 	return in.k8s.CoreV1().Endpoints(namespace).Get(serviceName, emptyGetOptions)
 }
 
+// GetEvents returns the events of a namespace.
+// It returns an error on any problem.
+func (in *K8SClient) GetEvents(namespace string) ([]core_v1.Event, error) {
+	if events, err := in.k8s.CoreV1().Events(namespace).List(meta_v1.ListOptions{}); err == nil {
+		return events.Items, nil
+	} else {
+		return []core_v1.Event{}, err
+	}
+}
+
+// GetObjectEvents returns the events of an object of a namespace, selected by kind and name.
+// It returns an error on any problem.
+func (in *K8SClient) GetObjectEvents(namespace, kind, name string) ([]core_v1.Event, error) {
+	selector := fields.Set{"involvedObject.kind": kind, "involvedObject.name": name}.AsSelector().String()
+	if events, err := in.k8s.CoreV1().Events(namespace).List(meta_v1.ListOptions{FieldSelector: selector}); err == nil {
+		return events.Items, nil
+	} else {
+		return []core_v1.Event{}, err
+	}
+}
+
 // GetPods returns the pods definitions for a given set of labels.
 // An empty labelSelector will fetch all pods found per a namespace.
 // It returns an error on any problem.
@@ -269,9 +326,7 @@ func (in *K8SClient) GetPod(namespace, name string) (*core_v1.Pod, error) {
 // GetPod returns the pod definitions for a given pod name.
 // It returns an error on any problem.
 func (in *K8SClient) GetPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (*PodLogs, error) {
-	req := in.k8s.CoreV1().RESTClient().Get().Namespace(namespace).Name(name).Resource("pods").SubResource("log").VersionedParams(opts, scheme.ParameterCodec)
-
-	readCloser, err := req.Stream()
+	readCloser, err := in.StreamPodLogs(namespace, name, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -286,6 +341,13 @@ func (in *K8SClient) GetPodLogs(namespace, name string, opts *core_v1.PodLogOpti
 	return &PodLogs{Logs: buf.String()}, nil
 }
 
+// StreamPodLogs opens a stream of the logs of a pod container. With the Follow option, the stream stays open for the
+// new lines until it is closed by the caller.
+func (in *K8SClient) StreamPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (io.ReadCloser, error) {
+	req := in.k8s.CoreV1().RESTClient().Get().Namespace(namespace).Name(name).Resource("pods").SubResource("log").VersionedParams(opts, scheme.ParameterCodec)
+	return req.Stream()
+}
+
 func (in *K8SClient) GetCronJobs(namespace string) ([]batch_v1beta1.CronJob, error) {
 	if cjList, err := in.k8s.BatchV1beta1().CronJobs(namespace).List(emptyListOptions); err == nil {
 		return cjList.Items, nil
@@ -308,8 +370,13 @@ func NewNotFound(name, group, resource string) error {
 	return errors.NewNotFound(schema.GroupResource{Group: group, Resource: resource}, name)
 }
 
-// GetSelfSubjectAccessReview provides information on Kiali permissions
+// GetSelfSubjectAccessReview provides information on Kiali permissions.
+// The resourceType may name a subresource, as pods/exec.
 func (in *K8SClient) GetSelfSubjectAccessReview(namespace, api, resourceType string, verbs []string) ([]*auth_v1.SelfSubjectAccessReview, error) {
+	resource, subresource := resourceType, ""
+	if parts := strings.SplitN(resourceType, "/", 2); len(parts) == 2 {
+		resource, subresource = parts[0], parts[1]
+	}
 	calls := len(verbs)
 	ch := make(chan *auth_v1.SelfSubjectAccessReview, calls)
 	errChan := make(chan error)
@@ -318,10 +385,11 @@ func (in *K8SClient) GetSelfSubjectAccessReview(namespace, api, resourceType str
 			res, err := in.k8s.AuthorizationV1().SelfSubjectAccessReviews().Create(&auth_v1.SelfSubjectAccessReview{
 				Spec: auth_v1.SelfSubjectAccessReviewSpec{
 					ResourceAttributes: &auth_v1.ResourceAttributes{
-						Namespace: namespace,
-						Verb:      verb,
-						Group:     api,
-						Resource:  resourceType,
+						Namespace:   namespace,
+						Verb:        verb,
+						Group:       api,
+						Resource:    resource,
+						Subresource: subresource,
 					},
 				},
 			})
@@ -363,6 +431,12 @@ func (in *K8SClient) UpdateWorkload(namespace string, workloadName string, workl
 		}
 	case StatefulSetType:
 		_, err = in.k8s.AppsV1().StatefulSets(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+	case DaemonSetType:
+		_, err = in.k8s.AppsV1().DaemonSets(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
+	case RolloutType:
+		if in.IsArgoRolloutsApi() {
+			err = in.patchArgoRollout(namespace, workloadName, bytePatch)
+		}
 	case JobType:
 		_, err = in.k8s.BatchV1().Jobs(namespace).Patch(workloadName, types.MergePatchType, bytePatch)
 	case CronJobType:
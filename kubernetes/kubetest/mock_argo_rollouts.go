@@ -0,0 +1,18 @@
+package kubetest
+
+import "github.com/kiali/kiali/kubernetes"
+
+func (o *K8SClientMock) GetArgoRollout(namespace, name string) (*kubernetes.ArgoRollout, error) {
+	args := o.Called(namespace, name)
+	return args.Get(0).(*kubernetes.ArgoRollout), args.Error(1)
+}
+
+func (o *K8SClientMock) GetArgoRollouts(namespace string) ([]kubernetes.ArgoRollout, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]kubernetes.ArgoRollout), args.Error(1)
+}
+
+func (o *K8SClientMock) IsArgoRolloutsApi() bool {
+	args := o.Called()
+	return args.Get(0).(bool)
+}
@@ -29,6 +29,16 @@ func (o *K8SClientMock) UpdateIstioObject(api, namespace, resourceType, name, js
 	return args.Get(0).(kubernetes.IstioObject), args.Error(1)
 }
 
+func (o *K8SClientMock) GetConfigDump(namespace, podName string) (*kubernetes.ConfigDump, error) {
+	args := o.Called(namespace, podName)
+	return args.Get(0).(*kubernetes.ConfigDump), args.Error(1)
+}
+
+func (o *K8SClientMock) SetProxyLogLevel(namespace, podName, logger, level string) (map[string]string, error) {
+	args := o.Called(namespace, podName, logger, level)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
 func (o *K8SClientMock) GetProxyStatus() ([]*kubernetes.ProxyStatus, error) {
 	args := o.Called()
 	return args.Get(0).([]*kubernetes.ProxyStatus), args.Error(1)
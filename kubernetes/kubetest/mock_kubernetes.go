@@ -1,6 +1,8 @@
 package kubetest
 
 import (
+	"io"
+
 	apps_v1 "k8s.io/api/apps/v1"
 	auth_v1 "k8s.io/api/authorization/v1"
 	batch_v1 "k8s.io/api/batch/v1"
@@ -10,6 +12,16 @@ import (
 	"github.com/kiali/kiali/kubernetes"
 )
 
+func (o *K8SClientMock) CreateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	args := o.Called(namespace, configMap)
+	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
+}
+
+func (o *K8SClientMock) UpdateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	args := o.Called(namespace, configMap)
+	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
+}
+
 func (o *K8SClientMock) GetConfigMap(namespace, configName string) (*core_v1.ConfigMap, error) {
 	args := o.Called(namespace, configName)
 	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
@@ -20,6 +32,16 @@ func (o *K8SClientMock) GetCronJobs(namespace string) ([]batch_apps_v1.CronJob,
 	return args.Get(0).([]batch_apps_v1.CronJob), args.Error(1)
 }
 
+func (o *K8SClientMock) GetDaemonSet(namespace string, daemonsetName string) (*apps_v1.DaemonSet, error) {
+	args := o.Called(namespace, daemonsetName)
+	return args.Get(0).(*apps_v1.DaemonSet), args.Error(1)
+}
+
+func (o *K8SClientMock) GetDaemonSets(namespace string) ([]apps_v1.DaemonSet, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]apps_v1.DaemonSet), args.Error(1)
+}
+
 func (o *K8SClientMock) GetDeployment(namespace string, deploymentName string) (*apps_v1.Deployment, error) {
 	args := o.Called(namespace, deploymentName)
 	return args.Get(0).(*apps_v1.Deployment), args.Error(1)
@@ -40,6 +62,16 @@ func (o *K8SClientMock) GetEndpoints(namespace string, serviceName string) (*cor
 	return args.Get(0).(*core_v1.Endpoints), args.Error(1)
 }
 
+func (o *K8SClientMock) GetEvents(namespace string) ([]core_v1.Event, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]core_v1.Event), args.Error(1)
+}
+
+func (o *K8SClientMock) GetObjectEvents(namespace, kind, name string) ([]core_v1.Event, error) {
+	args := o.Called(namespace, kind, name)
+	return args.Get(0).([]core_v1.Event), args.Error(1)
+}
+
 func (o *K8SClientMock) GetJobs(namespace string) ([]batch_v1.Job, error) {
 	args := o.Called(namespace)
 	return args.Get(0).([]batch_v1.Job), args.Error(1)
@@ -70,6 +102,16 @@ func (o *K8SClientMock) GetPodLogs(namespace, name string, opts *core_v1.PodLogO
 	return args.Get(0).(*kubernetes.PodLogs), args.Error(1)
 }
 
+func (o *K8SClientMock) StreamPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (io.ReadCloser, error) {
+	args := o.Called(namespace, name, opts)
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (o *K8SClientMock) GetControllerRevisions(namespace string) ([]apps_v1.ControllerRevision, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]apps_v1.ControllerRevision), args.Error(1)
+}
+
 func (o *K8SClientMock) GetReplicationControllers(namespace string) ([]core_v1.ReplicationController, error) {
 	args := o.Called(namespace)
 	return args.Get(0).([]core_v1.ReplicationController), args.Error(1)
@@ -13,12 +13,14 @@ const (
 	// Kubernetes Controllers
 	ConfigMapType             = "ConfigMap"
 	CronJobType               = "CronJob"
+	DaemonSetType             = "DaemonSet"
 	DeploymentType            = "Deployment"
 	DeploymentConfigType      = "DeploymentConfig"
 	JobType                   = "Job"
 	PodType                   = "Pod"
 	ReplicationControllerType = "ReplicationController"
 	ReplicaSetType            = "ReplicaSet"
+	RolloutType               = "Rollout"
 	ServiceType               = "Service"
 	StatefulSetType           = "StatefulSet"
 
@@ -95,6 +97,11 @@ var (
 	}
 	ApiIter8Version = Iter8GroupVersion.Group + "/" + Iter8GroupVersion.Version
 
+	ArgoRolloutsGroupVersion = schema.GroupVersion{
+		Group:   "argoproj.io",
+		Version: "v1alpha1",
+	}
+
 	networkingTypes = []struct {
 		objectKind     string
 		collectionKind string
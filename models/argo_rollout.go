@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+
+	apps_v1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// ArgoRollout has the progress of the rollout of a workload controlled by Argo Rollouts
+type ArgoRollout struct {
+	// canary or blueGreen
+	// required: true
+	// example: canary
+	Strategy string `json:"strategy"`
+
+	// Phase reported by Argo Rollouts: Progressing, Paused, Healthy, Degraded
+	// example: Paused
+	Phase string `json:"phase"`
+
+	// Message of the phase
+	Message string `json:"message,omitempty"`
+
+	// Define if the rollout is paused
+	// required: true
+	Paused bool `json:"paused"`
+
+	// Define if the rollout was aborted and scaled back to the stable ReplicaSet
+	// required: true
+	Aborted bool `json:"aborted"`
+
+	// Index of the current step of a canary rollout. It is the number of steps when all of them are completed.
+	// example: 1
+	CurrentStep *int32 `json:"currentStep,omitempty"`
+
+	// Description of the steps of a canary rollout, i.e. setWeight 20, pause 1h
+	Steps []string `json:"steps"`
+
+	// Weight of the canary, from the last setWeight step completed
+	// example: 20
+	CanaryWeight int32 `json:"canaryWeight"`
+
+	// ReplicaSet of the stable revision
+	StableReplicaSet string `json:"stableReplicaSet,omitempty"`
+
+	// Number of replicas of the stable ReplicaSet
+	StableReplicas int32 `json:"stableReplicas"`
+
+	// ReplicaSet of the revision being rolled out. Empty when the stable revision is the latest.
+	CanaryReplicaSet string `json:"canaryReplicaSet,omitempty"`
+
+	// Number of replicas of the canary ReplicaSet
+	CanaryReplicas int32 `json:"canaryReplicas"`
+
+	// Services selecting the pods of each revision
+	StableService  string `json:"stableService,omitempty"`
+	CanaryService  string `json:"canaryService,omitempty"`
+	ActiveService  string `json:"activeService,omitempty"`
+	PreviewService string `json:"previewService,omitempty"`
+}
+
+// Parse sets the progress of a rollout, identifying the stable and the canary ReplicaSets by their pod template hash
+func (rollout *ArgoRollout) Parse(r *kubernetes.ArgoRollout, replicaSets []apps_v1.ReplicaSet) {
+	rollout.Phase = r.Status.Phase
+	rollout.Message = r.Status.Message
+	rollout.Paused = r.Spec.Paused
+	rollout.Aborted = r.Status.Abort
+	rollout.Steps = []string{}
+
+	if bg := r.Spec.Strategy.BlueGreen; bg != nil {
+		rollout.Strategy = "blueGreen"
+		rollout.ActiveService = bg.ActiveService
+		rollout.PreviewService = bg.PreviewService
+	} else if canary := r.Spec.Strategy.Canary; canary != nil {
+		rollout.Strategy = "canary"
+		rollout.StableService = canary.StableService
+		rollout.CanaryService = canary.CanaryService
+		rollout.CurrentStep = r.Status.CurrentStepIndex
+		completed := len(canary.Steps)
+		if r.Status.CurrentStepIndex != nil && int(*r.Status.CurrentStepIndex) < completed {
+			completed = int(*r.Status.CurrentStepIndex)
+		}
+		for i, step := range canary.Steps {
+			rollout.Steps = append(rollout.Steps, argoRolloutStepDescription(step))
+			if i < completed && step.SetWeight != nil {
+				rollout.CanaryWeight = *step.SetWeight
+			}
+		}
+		if completed == len(canary.Steps) {
+			rollout.CanaryWeight = 100
+		}
+	}
+
+	for _, rs := range replicaSets {
+		if !isOwnedBy(rs.OwnerReferences, kubernetes.RolloutType, r.Name) {
+			continue
+		}
+		hash := rs.Labels[kubernetes.ArgoRolloutPodHashLabel]
+		if hash == "" {
+			continue
+		}
+		if hash == r.Status.StableRS {
+			rollout.StableReplicaSet = rs.Name
+			rollout.StableReplicas = rs.Status.Replicas
+		} else if hash == r.Status.CurrentPodHash {
+			rollout.CanaryReplicaSet = rs.Name
+			rollout.CanaryReplicas = rs.Status.Replicas
+		}
+	}
+	// Without a canary, the stable revision takes all the traffic
+	if rollout.CanaryReplicaSet == "" && rollout.Strategy == "canary" {
+		rollout.CanaryWeight = 0
+	}
+}
+
+// Services returns the names of the services of each revision of the rollout
+func (rollout *ArgoRollout) Services() []string {
+	services := []string{}
+	for _, name := range []string{rollout.StableService, rollout.CanaryService, rollout.ActiveService, rollout.PreviewService} {
+		if name != "" {
+			services = append(services, name)
+		}
+	}
+	return services
+}
+
+func argoRolloutStepDescription(step kubernetes.ArgoRolloutStep) string {
+	switch {
+	case step.SetWeight != nil:
+		return fmt.Sprintf("setWeight %d", *step.SetWeight)
+	case step.Pause != nil && step.Pause.Duration != nil && step.Pause.Duration.Type == intstr.Int:
+		return fmt.Sprintf("pause %ds", step.Pause.Duration.IntVal)
+	case step.Pause != nil && step.Pause.Duration != nil:
+		return "pause " + step.Pause.Duration.StrVal
+	case step.Pause != nil:
+		return "pause"
+	case step.Analysis != nil:
+		return "analysis"
+	case step.Experiment != nil:
+		return "experiment"
+	case step.SetCanaryScale != nil:
+		return "setCanaryScale"
+	default:
+		return "unknown"
+	}
+}
@@ -41,3 +41,19 @@ func (ap *AuthorizationPolicy) Parse(authorizationPolicy kubernetes.IstioObject)
 	ap.Spec.Rules = authorizationPolicy.GetSpec()["rules"]
 	ap.Spec.Action = authorizationPolicy.GetSpec()["action"]
 }
+
+// AuthorizationPolicyProposal authorizationPolicyProposal
+//
+// This is used for returning an AuthorizationPolicy generated from the observed traffic, to be reviewed before its creation
+//
+// swagger:model authorizationPolicyProposal
+type AuthorizationPolicyProposal struct {
+	// The generated ALLOW AuthorizationPolicy
+	Policy AuthorizationPolicy `json:"policy"`
+	// The AuthorizationPolicy with the same name, if it exists
+	Existing *AuthorizationPolicy `json:"existing,omitempty"`
+	// Line based diff from the YAML of the existing policy to the YAML of the generated one
+	Diff string `json:"diff"`
+	// Observed traffic that the generated policy would deny
+	Warnings []string `json:"warnings"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// ChaosExperimentRequest chaosExperimentRequest
+//
+// This is used for starting a fault injection experiment on a service
+//
+// swagger:model chaosExperimentRequest
+type ChaosExperimentRequest struct {
+	// Faults injected in the HTTP routes of the service
+	// required: true
+	Fault FaultInjection `json:"fault"`
+
+	// Duration of the experiment, after which the faults are removed
+	// required: true
+	// example: 10m
+	Duration string `json:"duration"`
+}
+
+// ChaosExperiment chaosExperiment
+//
+// This is used for returning a fault injection experiment running on a service
+//
+// swagger:model chaosExperiment
+type ChaosExperiment struct {
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// example: reviews
+	Service string `json:"service"`
+
+	// VirtualService with the faults, restored when the experiment ends
+	// example: reviews
+	VirtualService string `json:"virtualService"`
+
+	// Fault of the HTTP routes
+	Fault interface{} `json:"fault"`
+
+	StartTime time.Time `json:"startTime"`
+
+	// Time when the faults are removed
+	Expiry time.Time `json:"expiry"`
+
+	// Health of the requests to the service during the experiment
+	Health *ServiceHealth `json:"health,omitempty"`
+
+	// Ratio of the inbound requests to the service failing, in the rate interval
+	// example: 0.1
+	ErrorRatio float64 `json:"errorRatio"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"math"
+	"sort"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/prometheus"
+)
+
+// WorkloadResources has the CPU and memory of the containers of a workload, the application containers apart from the sidecar
+type WorkloadResources struct {
+	// Application containers, by name
+	// required: true
+	Containers []*ContainerResources `json:"containers"`
+
+	// Istio sidecar of the pods, absent without sidecar
+	Sidecar *ContainerResources `json:"sidecar,omitempty"`
+}
+
+// ContainerResources has the CPU and memory usage of a container, summed over the pods of a workload, with its requests and limits.
+// CPU is in cores and memory in bytes. The values not reported to Prometheus are not set, i.e. when the container has no limit.
+type ContainerResources struct {
+	// Name of the container
+	// required: true
+	// example: istio-proxy
+	Name string `json:"name"`
+
+	// example: 0.25
+	CPUUsage *float64 `json:"cpuUsage,omitempty"`
+	// example: 0.2
+	CPURequest *float64 `json:"cpuRequest,omitempty"`
+	// example: 2
+	CPULimit *float64 `json:"cpuLimit,omitempty"`
+
+	// Ratio of the CPU periods where the container was throttled, between 0 and 1
+	// example: 0.05
+	CPUThrottled *float64 `json:"cpuThrottled,omitempty"`
+
+	// example: 52428800
+	MemoryUsage *float64 `json:"memoryUsage,omitempty"`
+	// example: 134217728
+	MemoryRequest *float64 `json:"memoryRequest,omitempty"`
+	// example: 1073741824
+	MemoryLimit *float64 `json:"memoryLimit,omitempty"`
+}
+
+// Parse sets the resources of each container from the container metrics summed by container. The containers in sidecars
+// are the sidecar of the workload.
+func (resources *WorkloadResources) Parse(metrics map[string]model.Vector, sidecars map[string]bool) {
+	byName := map[string]*ContainerResources{}
+	for metric, vector := range metrics {
+		for _, sample := range vector {
+			value := float64(sample.Value)
+			name := string(sample.Metric["container"])
+			if name == "" || math.IsNaN(value) {
+				continue
+			}
+			container, found := byName[name]
+			if !found {
+				container = &ContainerResources{Name: name}
+				byName[name] = container
+			}
+			if field := container.field(metric); field != nil {
+				*field = &value
+			}
+		}
+	}
+	resources.Containers = []*ContainerResources{}
+	for name, container := range byName {
+		if sidecars[name] && resources.Sidecar == nil {
+			resources.Sidecar = container
+		} else {
+			resources.Containers = append(resources.Containers, container)
+		}
+	}
+	sort.Slice(resources.Containers, func(i, j int) bool {
+		return resources.Containers[i].Name < resources.Containers[j].Name
+	})
+}
+
+func (container *ContainerResources) field(metric string) **float64 {
+	switch metric {
+	case prometheus.ContainerCPUUsage:
+		return &container.CPUUsage
+	case prometheus.ContainerCPURequests:
+		return &container.CPURequest
+	case prometheus.ContainerCPULimits:
+		return &container.CPULimit
+	case prometheus.ContainerCPUThrottled:
+		return &container.CPUThrottled
+	case prometheus.ContainerMemoryUsage:
+		return &container.MemoryUsage
+	case prometheus.ContainerMemoryRequests:
+		return &container.MemoryRequest
+	case prometheus.ContainerMemoryLimits:
+		return &container.MemoryLimit
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/prometheus"
+)
+
+func TestWorkloadResourcesParsing(t *testing.T) {
+	assert := assert.New(t)
+	sample := func(container string, value float64) *model.Sample {
+		return &model.Sample{Metric: model.Metric{"container": model.LabelValue(container)}, Value: model.SampleValue(value)}
+	}
+
+	resources := WorkloadResources{}
+	resources.Parse(map[string]model.Vector{
+		prometheus.ContainerCPUUsage:     {sample("reviews", 0.5), sample("istio-proxy", 0.3), sample("zipkin", 0.1)},
+		prometheus.ContainerCPULimits:    {sample("istio-proxy", 2)},
+		prometheus.ContainerCPUThrottled: {sample("reviews", math.NaN()), sample("istio-proxy", 0.25)},
+		prometheus.ContainerMemoryUsage:  {sample("reviews", 104857600), sample("", 209715200)},
+	}, map[string]bool{"istio-proxy": true})
+
+	// Application containers by name, the sidecar apart
+	assert.Len(resources.Containers, 2)
+	assert.Equal("reviews", resources.Containers[0].Name)
+	assert.Equal(0.5, *resources.Containers[0].CPUUsage)
+	assert.Equal(104857600.0, *resources.Containers[0].MemoryUsage)
+	assert.Nil(resources.Containers[0].CPULimit)
+	assert.Nil(resources.Containers[0].CPUThrottled)
+	assert.Equal("zipkin", resources.Containers[1].Name)
+
+	assert.NotNil(resources.Sidecar)
+	assert.Equal("istio-proxy", resources.Sidecar.Name)
+	assert.Equal(0.3, *resources.Sidecar.CPUUsage)
+	assert.Equal(2.0, *resources.Sidecar.CPULimit)
+	assert.Equal(0.25, *resources.Sidecar.CPUThrottled)
+	assert.Nil(resources.Sidecar.MemoryUsage)
+}
@@ -0,0 +1,290 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// Sections of an EnvoyConfigDump
+const (
+	EnvoyBootstrapSection = "bootstrap"
+	EnvoyClustersSection  = "clusters"
+	EnvoyListenersSection = "listeners"
+	EnvoyRoutesSection    = "routes"
+)
+
+// EnvoyConfigDump envoyConfigDump
+//
+// This is used for returning the configuration received by the Envoy proxy of a pod
+//
+// swagger:model envoyConfigDump
+type EnvoyConfigDump struct {
+	Bootstrap *EnvoyBootstrap `json:"bootstrap,omitempty"`
+	Clusters  []EnvoyCluster  `json:"clusters,omitempty"`
+	Listeners []EnvoyListener `json:"listeners,omitempty"`
+	Routes    []EnvoyRoute    `json:"routes,omitempty"`
+}
+
+// EnvoyBootstrap is the identity of the proxy in the mesh
+type EnvoyBootstrap struct {
+	// example: sidecar~10.1.1.5~reviews-v1-545db77b95-7xdq9.bookinfo~bookinfo.svc.cluster.local
+	NodeID string `json:"nodeId"`
+
+	// example: reviews.bookinfo
+	Cluster string `json:"cluster"`
+
+	// example: 1.7.0
+	IstioVersion string `json:"istioVersion"`
+
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// EnvoyCluster is an upstream cluster of the proxy, with its endpoints
+type EnvoyCluster struct {
+	// example: outbound|9080|v1|reviews.bookinfo.svc.cluster.local
+	Name string `json:"name"`
+
+	// inbound or outbound, for the clusters of the Istio services
+	// example: outbound
+	Direction string `json:"direction"`
+
+	// example: reviews.bookinfo.svc.cluster.local
+	Service string `json:"service"`
+
+	// example: 9080
+	Port int `json:"port"`
+
+	// example: v1
+	Subset string `json:"subset"`
+
+	// example: EDS
+	Type string `json:"type"`
+
+	// DestinationRule applied to the cluster, as name.namespace
+	// example: reviews.bookinfo
+	DestinationRule string `json:"destinationRule"`
+
+	Endpoints []EnvoyEndpoint `json:"endpoints"`
+}
+
+// EnvoyEndpoint is a host of a cluster
+type EnvoyEndpoint struct {
+	// example: 10.1.1.5
+	Address string `json:"address"`
+
+	// example: 9080
+	Port int `json:"port"`
+
+	// example: 1
+	Weight int `json:"weight"`
+
+	// HEALTHY, UNHEALTHY, DRAINING... or the failed check: FAILED_OUTLIER_CHECK, FAILED_ACTIVE_HEALTH_CHECK
+	// example: HEALTHY
+	Health string `json:"health"`
+}
+
+// EnvoyListener is a listener of the proxy
+type EnvoyListener struct {
+	// example: 0.0.0.0_9080
+	Name string `json:"name"`
+
+	// example: 0.0.0.0
+	Address string `json:"address"`
+
+	// example: 9080
+	Port int `json:"port"`
+
+	FilterChains []EnvoyFilterChain `json:"filterChains"`
+
+	VersionInfo string `json:"versionInfo,omitempty"`
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+// EnvoyFilterChain is a filter chain of a listener with its destination: a route config or a cluster
+type EnvoyFilterChain struct {
+	Match map[string]interface{} `json:"match,omitempty"`
+
+	// example: 9080
+	RouteConfig string `json:"routeConfig,omitempty"`
+
+	// example: outbound|3306||mysql.bookinfo.svc.cluster.local
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// EnvoyRoute is a route of a virtual host of a route config
+type EnvoyRoute struct {
+	// example: 9080
+	RouteConfig string `json:"routeConfig"`
+
+	// example: reviews.bookinfo.svc.cluster.local:9080
+	VirtualHost string `json:"virtualHost"`
+
+	Domains []string `json:"domains"`
+
+	// example: default
+	Name string `json:"name"`
+
+	Match map[string]interface{} `json:"match"`
+
+	// Clusters of the route, empty for redirects and direct responses
+	Destinations []EnvoyRouteDestination `json:"destinations"`
+
+	// example: 0s
+	Timeout string `json:"timeout,omitempty"`
+
+	VersionInfo string `json:"versionInfo,omitempty"`
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+// EnvoyRouteDestination is a cluster of a route, with its weight for weighted routes
+type EnvoyRouteDestination struct {
+	// example: outbound|9080|v1|reviews.bookinfo.svc.cluster.local
+	Cluster string `json:"cluster"`
+
+	// example: 50
+	Weight int `json:"weight,omitempty"`
+}
+
+// Parse extracts the bootstrap, clusters, listeners and routes of an Envoy config_dump
+func (dump *EnvoyConfigDump) Parse(cd *kubernetes.ConfigDump) {
+	endpoints := make(map[string][]EnvoyEndpoint, len(cd.ClusterStatuses))
+	for _, status := range cd.ClusterStatuses {
+		for _, host := range status.HostStatuses {
+			endpoint := EnvoyEndpoint{
+				Address: host.Address.SocketAddress.Address,
+				Port:    host.Address.SocketAddress.PortValue,
+				Weight:  host.Weight,
+				Health:  host.HealthStatus.EdsHealthStatus,
+			}
+			if host.HealthStatus.FailedOutlierCheck {
+				endpoint.Health = "FAILED_OUTLIER_CHECK"
+			} else if host.HealthStatus.FailedActiveHealthCheck {
+				endpoint.Health = "FAILED_ACTIVE_HEALTH_CHECK"
+			} else if endpoint.Health == "" {
+				endpoint.Health = "HEALTHY"
+			}
+			endpoints[status.Name] = append(endpoints[status.Name], endpoint)
+		}
+	}
+
+	for _, config := range cd.Configs {
+		if config.Bootstrap != nil {
+			dump.Bootstrap = &EnvoyBootstrap{
+				NodeID:   config.Bootstrap.Node.ID,
+				Cluster:  config.Bootstrap.Node.Cluster,
+				Metadata: config.Bootstrap.Node.Metadata,
+			}
+			if version, ok := config.Bootstrap.Node.Metadata["ISTIO_VERSION"].(string); ok {
+				dump.Bootstrap.IstioVersion = version
+			}
+		}
+		for _, c := range append(config.StaticClusters, config.DynamicActiveClusters...) {
+			cluster := EnvoyCluster{}
+			cluster.Parse(c.Cluster)
+			cluster.Endpoints = endpoints[cluster.Name]
+			if cluster.Endpoints == nil {
+				cluster.Endpoints = []EnvoyEndpoint{}
+			}
+			dump.Clusters = append(dump.Clusters, cluster)
+		}
+		listeners := append(config.StaticListeners, config.DynamicActiveListeners...)
+		for _, l := range config.DynamicListeners {
+			// Listeners being warmed or drained have no active state
+			if l.ActiveState != nil {
+				listeners = append(listeners, *l.ActiveState)
+			}
+		}
+		for _, l := range listeners {
+			listener := EnvoyListener{}
+			listener.Parse(l.Listener)
+			listener.VersionInfo, listener.LastUpdated = l.VersionInfo, l.LastUpdated
+			dump.Listeners = append(dump.Listeners, listener)
+		}
+		for _, rc := range append(config.StaticRouteConfigs, config.DynamicRouteConfigs...) {
+			dump.Routes = append(dump.Routes, parseEnvoyRoutes(rc.RouteConfig, rc.VersionInfo, rc.LastUpdated)...)
+		}
+	}
+}
+
+// Parse extracts the Istio service of the cluster from its name (direction|port|subset|service) and metadata
+func (cluster *EnvoyCluster) Parse(c kubernetes.EnvoyCluster) {
+	cluster.Name = c.Name
+	cluster.Type = c.Type
+	if parts := strings.Split(c.Name, "|"); len(parts) == 4 {
+		cluster.Direction = parts[0]
+		cluster.Port, _ = strconv.Atoi(parts[1])
+		// The third part of the inbound clusters is the port name
+		if cluster.Direction == "outbound" {
+			cluster.Subset = parts[2]
+		}
+		cluster.Service = parts[3]
+	}
+	// i.e. /apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destination-rule/reviews
+	if path, ok := c.Metadata.FilterMetadata["istio"]["config"].(string); ok {
+		if parts := strings.Split(path, "/"); len(parts) > 3 && parts[len(parts)-2] == "destination-rule" {
+			cluster.DestinationRule = parts[len(parts)-1] + "." + parts[len(parts)-3]
+		}
+	}
+}
+
+// Parse extracts the address of the listener and the destination of its filter chains
+func (listener *EnvoyListener) Parse(l kubernetes.EnvoyListener) {
+	listener.Name = l.Name
+	listener.Address = l.Address.SocketAddress.Address
+	listener.Port = l.Address.SocketAddress.PortValue
+	listener.FilterChains = make([]EnvoyFilterChain, 0, len(l.FilterChains))
+	for _, fc := range l.FilterChains {
+		chain := EnvoyFilterChain{Match: fc.FilterChainMatch}
+		for _, filter := range fc.Filters {
+			config := filter.TypedConfig
+			if config == nil {
+				config = filter.Config
+			}
+			if config == nil {
+				continue
+			}
+			if config.Cluster != "" {
+				chain.Cluster = config.Cluster
+			}
+			if config.Rds != nil {
+				chain.RouteConfig = config.Rds.RouteConfigName
+			} else if config.RouteConfig != nil {
+				chain.RouteConfig = config.RouteConfig.Name
+			}
+		}
+		listener.FilterChains = append(listener.FilterChains, chain)
+	}
+}
+
+func parseEnvoyRoutes(rc kubernetes.EnvoyRouteConfig, versionInfo, lastUpdated string) []EnvoyRoute {
+	routes := []EnvoyRoute{}
+	for _, vh := range rc.VirtualHosts {
+		for _, r := range vh.Routes {
+			route := EnvoyRoute{
+				RouteConfig:  rc.Name,
+				VirtualHost:  vh.Name,
+				Domains:      vh.Domains,
+				Name:         r.Name,
+				Match:        r.Match,
+				Destinations: []EnvoyRouteDestination{},
+				VersionInfo:  versionInfo,
+				LastUpdated:  lastUpdated,
+			}
+			if r.Route != nil {
+				route.Timeout = r.Route.Timeout
+				if r.Route.Cluster != "" {
+					route.Destinations = append(route.Destinations, EnvoyRouteDestination{Cluster: r.Route.Cluster})
+				}
+				if r.Route.WeightedClusters != nil {
+					for _, wc := range r.Route.WeightedClusters.Clusters {
+						route.Destinations = append(route.Destinations, EnvoyRouteDestination{Cluster: wc.Name, Weight: wc.Weight})
+					}
+				}
+			}
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
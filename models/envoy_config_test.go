@@ -0,0 +1,131 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func TestParseEnvoyConfigDump(t *testing.T) {
+	assert := assert.New(t)
+
+	configDump := &kubernetes.ConfigDump{}
+	assert.NoError(json.Unmarshal([]byte(fakeEnvoyConfigDump), configDump))
+	assert.NoError(json.Unmarshal([]byte(fakeEnvoyClusters), configDump))
+
+	dump := EnvoyConfigDump{}
+	dump.Parse(configDump)
+
+	assert.Equal("1.7.0", dump.Bootstrap.IstioVersion)
+	assert.Equal("reviews.bookinfo", dump.Bootstrap.Cluster)
+
+	assert.Len(dump.Clusters, 2)
+	assert.Equal(EnvoyCluster{
+		Name:            "outbound|9080|v1|reviews.bookinfo.svc.cluster.local",
+		Direction:       "outbound",
+		Service:         "reviews.bookinfo.svc.cluster.local",
+		Port:            9080,
+		Subset:          "v1",
+		Type:            "EDS",
+		DestinationRule: "reviews.bookinfo",
+		Endpoints: []EnvoyEndpoint{
+			{Address: "10.1.1.5", Port: 9080, Weight: 1, Health: "HEALTHY"},
+			{Address: "10.1.1.6", Port: 9080, Weight: 1, Health: "FAILED_OUTLIER_CHECK"},
+		},
+	}, dump.Clusters[1])
+	assert.Equal("inbound", dump.Clusters[0].Direction)
+	assert.Empty(dump.Clusters[0].Subset)
+	assert.Empty(dump.Clusters[0].Endpoints)
+
+	// Listeners being warmed are not active yet
+	assert.Len(dump.Listeners, 2)
+	assert.Equal(9080, dump.Listeners[0].Port)
+	assert.Equal("9080", dump.Listeners[0].FilterChains[0].RouteConfig)
+	assert.Equal("outbound|3306||mysql.bookinfo.svc.cluster.local", dump.Listeners[1].FilterChains[0].Cluster)
+	assert.Equal("2020-06-01T10:00:00Z", dump.Listeners[1].LastUpdated)
+
+	assert.Len(dump.Routes, 2)
+	assert.Equal("reviews.bookinfo.svc.cluster.local:9080", dump.Routes[0].VirtualHost)
+	assert.Equal([]EnvoyRouteDestination{
+		{Cluster: "outbound|9080|v1|reviews.bookinfo.svc.cluster.local", Weight: 80},
+		{Cluster: "outbound|9080|v2|reviews.bookinfo.svc.cluster.local", Weight: 20},
+	}, dump.Routes[0].Destinations)
+	assert.Equal("allow_any", dump.Routes[1].VirtualHost)
+	assert.Equal([]EnvoyRouteDestination{{Cluster: "PassthroughCluster"}}, dump.Routes[1].Destinations)
+}
+
+const fakeEnvoyConfigDump = `{"configs": [
+	{
+		"@type": "type.googleapis.com/envoy.admin.v3.BootstrapConfigDump",
+		"bootstrap": {"node": {"id": "sidecar~10.1.1.4~productpage-v1.bookinfo~bookinfo.svc.cluster.local", "cluster": "reviews.bookinfo", "metadata": {"ISTIO_VERSION": "1.7.0"}}}
+	},
+	{
+		"@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump",
+		"static_clusters": [{"cluster": {"name": "inbound|9080|http|reviews.bookinfo.svc.cluster.local", "type": "STATIC"}}],
+		"dynamic_active_clusters": [{
+			"version_info": "2020-06-01T10:00:00Z/10",
+			"cluster": {
+				"name": "outbound|9080|v1|reviews.bookinfo.svc.cluster.local",
+				"type": "EDS",
+				"eds_cluster_config": {"service_name": "outbound|9080|v1|reviews.bookinfo.svc.cluster.local"},
+				"metadata": {"filter_metadata": {"istio": {"config": "/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destination-rule/reviews"}}}
+			}
+		}]
+	},
+	{
+		"@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump",
+		"dynamic_listeners": [
+			{
+				"name": "0.0.0.0_9080",
+				"active_state": {"listener": {
+					"name": "0.0.0.0_9080",
+					"address": {"socket_address": {"address": "0.0.0.0", "port_value": 9080}},
+					"filter_chains": [{"filters": [{"name": "envoy.http_connection_manager", "typed_config": {"rds": {"route_config_name": "9080"}}}]}]
+				}}
+			},
+			{
+				"name": "10.0.0.12_3306",
+				"active_state": {"last_updated": "2020-06-01T10:00:00Z", "listener": {
+					"name": "10.0.0.12_3306",
+					"address": {"socket_address": {"address": "10.0.0.12", "port_value": 3306}},
+					"filter_chains": [{"filters": [{"name": "envoy.tcp_proxy", "typed_config": {"cluster": "outbound|3306||mysql.bookinfo.svc.cluster.local"}}]}]
+				}}
+			},
+			{"name": "0.0.0.0_8080", "warming_state": {}}
+		]
+	},
+	{
+		"@type": "type.googleapis.com/envoy.admin.v3.RoutesConfigDump",
+		"dynamic_route_configs": [{
+			"route_config": {
+				"name": "9080",
+				"virtual_hosts": [
+					{
+						"name": "reviews.bookinfo.svc.cluster.local:9080",
+						"domains": ["reviews.bookinfo.svc.cluster.local", "reviews"],
+						"routes": [{"match": {"prefix": "/"}, "route": {"weighted_clusters": {"clusters": [
+							{"name": "outbound|9080|v1|reviews.bookinfo.svc.cluster.local", "weight": 80},
+							{"name": "outbound|9080|v2|reviews.bookinfo.svc.cluster.local", "weight": 20}
+						]}}}]
+					},
+					{
+						"name": "allow_any",
+						"domains": ["*"],
+						"routes": [{"name": "allow_any", "match": {"prefix": "/"}, "route": {"cluster": "PassthroughCluster", "timeout": "0s"}}]
+					}
+				]
+			}
+		}]
+	}
+]}`
+
+const fakeEnvoyClusters = `{"cluster_statuses": [{
+	"name": "outbound|9080|v1|reviews.bookinfo.svc.cluster.local",
+	"host_statuses": [
+		{"address": {"socket_address": {"address": "10.1.1.5", "port_value": 9080}}, "weight": 1, "health_status": {"eds_health_status": "HEALTHY"}},
+		{"address": {"socket_address": {"address": "10.1.1.6", "port_value": 9080}}, "weight": 1, "health_status": {"failed_outlier_check": true, "eds_health_status": "HEALTHY"}}
+	]
+}]}`
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// EnvoyLogLevelRequest is a change of the log level of an Envoy proxy
+type EnvoyLogLevelRequest struct {
+	// Logger changed, i.e. http, router, connection, upstream, rbac or jwt. All the loggers when empty.
+	// example: rbac
+	Logger string `json:"logger"`
+
+	// trace, debug, info, warning, error, critical or off
+	// required: true
+	// example: debug
+	Level string `json:"level"`
+
+	// Duration before the previous levels are restored, i.e. 10m. The change is kept when empty.
+	// example: 10m
+	ResetAfter string `json:"resetAfter"`
+}
+
+// EnvoyLogLevels envoyLogLevels
+//
+// This is used for returning the log level of each logger of an Envoy proxy
+//
+// swagger:model envoyLogLevels
+type EnvoyLogLevels struct {
+	// Level by logger
+	// required: true
+	Loggers map[string]string `json:"loggers"`
+
+	// Time the previous levels are restored, when a reset is pending
+	ResetTime *time.Time `json:"resetTime,omitempty"`
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+)
+
+// Event is a Kubernetes event related to a workload, a service or a pod.
+// Events with the same reason are aggregated, keeping the details of the last occurrence.
+type Event struct {
+	// Normal or Warning
+	// required: true
+	// example: Warning
+	Type string `json:"type"`
+
+	// Reason of the event
+	// required: true
+	// example: BackOff
+	Reason string `json:"reason"`
+
+	// Message of the last occurrence
+	// example: Back-off pulling image "reviews:v4"
+	Message string `json:"message"`
+
+	// Kind of the object of the last occurrence
+	// example: Pod
+	ObjectKind string `json:"objectKind"`
+
+	// Name of the object of the last occurrence
+	// example: reviews-v4-5c4b6d8f7-x2x8q
+	ObjectName string `json:"objectName"`
+
+	// Number of occurrences of the reason
+	// required: true
+	// example: 12
+	Count int32 `json:"count"`
+
+	// First time the reason was reported
+	FirstTimestamp string `json:"firstTimestamp"`
+
+	// Last time the reason was reported
+	LastTimestamp string `json:"lastTimestamp"`
+
+	first time.Time
+	last  time.Time
+}
+
+type Events []*Event
+
+// Parse aggregates the events by reason, warnings first and then the most recent first
+func (events *Events) Parse(list []core_v1.Event) {
+	byReason := map[string]*Event{}
+	for _, e := range list {
+		first, last := eventTimes(&e)
+		count := e.Count
+		if e.Series != nil {
+			count = e.Series.Count
+		}
+		if count == 0 {
+			count = 1
+		}
+		event, found := byReason[e.Reason]
+		if !found {
+			event = &Event{Reason: e.Reason, first: first}
+			byReason[e.Reason] = event
+			*events = append(*events, event)
+		}
+		event.Count += count
+		if first.Before(event.first) {
+			event.first = first
+		}
+		if !found || !last.Before(event.last) {
+			event.Type = e.Type
+			event.Message = e.Message
+			event.ObjectKind = e.InvolvedObject.Kind
+			event.ObjectName = e.InvolvedObject.Name
+			event.last = last
+		}
+	}
+	for _, event := range *events {
+		event.FirstTimestamp = formatTime(event.first)
+		event.LastTimestamp = formatTime(event.last)
+	}
+	sort.SliceStable(*events, func(i, j int) bool {
+		ei, ej := (*events)[i], (*events)[j]
+		if ei.IsWarning() != ej.IsWarning() {
+			return ei.IsWarning()
+		}
+		return ei.last.After(ej.last)
+	})
+}
+
+// IsWarning returns true for the events reporting a problem
+func (event *Event) IsWarning() bool {
+	return event.Type == core_v1.EventTypeWarning
+}
+
+// WarningReasons returns the reasons of the warning events
+func (events Events) WarningReasons() []string {
+	reasons := []string{}
+	for _, event := range events {
+		if event.IsWarning() {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+	return reasons
+}
+
+// eventTimes returns the first and the last time of an event, reported by the legacy fields or by the event series
+func eventTimes(e *core_v1.Event) (time.Time, time.Time) {
+	first, last := e.FirstTimestamp.Time, e.LastTimestamp.Time
+	if first.IsZero() {
+		first = e.EventTime.Time
+	}
+	if first.IsZero() {
+		first = e.CreationTimestamp.Time
+	}
+	if e.Series != nil && !e.Series.LastObservedTime.IsZero() {
+		last = e.Series.LastObservedTime.Time
+	}
+	if last.IsZero() {
+		last = first
+	}
+	return first, last
+}
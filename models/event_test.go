@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEventsParsing(t *testing.T) {
+	assert := assert.New(t)
+	t1 := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	event := func(eventType, reason, pod string, count int32, first, last time.Time) core_v1.Event {
+		return core_v1.Event{
+			Type:           eventType,
+			Reason:         reason,
+			Message:        reason + " " + pod,
+			InvolvedObject: core_v1.ObjectReference{Kind: "Pod", Name: pod},
+			Count:          count,
+			FirstTimestamp: meta_v1.NewTime(first),
+			LastTimestamp:  meta_v1.NewTime(last),
+		}
+	}
+
+	events := Events{}
+	events.Parse([]core_v1.Event{
+		event(core_v1.EventTypeNormal, "Pulled", "reviews-1", 1, t1, t1.Add(5*time.Minute)),
+		event(core_v1.EventTypeWarning, "BackOff", "reviews-1", 3, t1, t1.Add(2*time.Minute)),
+		event(core_v1.EventTypeWarning, "BackOff", "reviews-2", 2, t1.Add(-time.Minute), t1.Add(3*time.Minute)),
+		event(core_v1.EventTypeWarning, "Failed", "reviews-2", 0, t1, t1),
+	})
+
+	// Warnings first, then the most recent first
+	assert.Len(events, 3)
+	assert.Equal("BackOff", events[0].Reason)
+	assert.Equal(int32(5), events[0].Count)
+	assert.Equal("BackOff reviews-2", events[0].Message)
+	assert.Equal("reviews-2", events[0].ObjectName)
+	assert.Equal("2020-06-01T09:59:00Z", events[0].FirstTimestamp)
+	assert.Equal("2020-06-01T10:03:00Z", events[0].LastTimestamp)
+	assert.Equal("Failed", events[1].Reason)
+	assert.Equal(int32(1), events[1].Count)
+	assert.Equal("Pulled", events[2].Reason)
+	assert.False(events[2].IsWarning())
+	assert.Equal([]string{"BackOff", "Failed"}, events.WarningReasons())
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"strings"
+
 	"github.com/prometheus/common/model"
 )
 
@@ -71,6 +73,8 @@ type WorkloadStatus struct {
 	CurrentReplicas   int32  `json:"currentReplicas"`
 	AvailableReplicas int32  `json:"availableReplicas"`
 	SyncedProxies     int32  `json:"syncedProxies"`
+	// Reasons of the recent warning events of the workload, its ReplicaSets and its pods, i.e. BackOff, FailedCreate
+	WarningReasons []string `json:"warningReasons,omitempty"`
 }
 
 // ProxyStatus gives the sync status of the sidecar proxy.
@@ -101,6 +105,31 @@ func (in *RequestHealth) AggregateOutbound(sample *model.Sample) {
 	aggregate(sample, in.Outbound)
 }
 
+// InboundErrorRatio returns the ratio of the inbound requests failing: HTTP 4xx and 5xx, gRPC other than OK, and
+// requests without response. 0 without requests.
+func (in *RequestHealth) InboundErrorRatio() float64 {
+	total, errors := 0.0, 0.0
+	for protocol, codes := range in.Inbound {
+		for code, rate := range codes {
+			total += rate
+			switch {
+			case code == "-":
+				errors += rate
+			case protocol == "grpc":
+				if code != "0" {
+					errors += rate
+				}
+			case strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5"):
+				errors += rate
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return errors / total
+}
+
 func aggregate(sample *model.Sample, requests map[string]map[string]float64) {
 	code := string(sample.Metric["response_code"])
 	protocol := string(sample.Metric["request_protocol"])
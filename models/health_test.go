@@ -0,0 +1,18 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboundErrorRatio(t *testing.T) {
+	assert := assert.New(t)
+
+	health := NewEmptyRequestHealth()
+	assert.Equal(0.0, health.InboundErrorRatio())
+
+	health.Inbound["http"] = map[string]float64{"200": 6, "404": 1, "503": 1}
+	health.Inbound["grpc"] = map[string]float64{"0": 1, "14": 0.5, "-": 0.5}
+	assert.InDelta(0.3, health.InboundErrorRatio(), 0.0001)
+}
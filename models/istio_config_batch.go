@@ -0,0 +1,43 @@
+package models
+
+// IstioConfigBatchResult istioConfigBatchResult
+//
+// This is used for returning the result of applying several Istio objects together
+//
+// swagger:model istioConfigBatchResult
+type IstioConfigBatchResult struct {
+	// Checks introduced, resolved and unchanged by the whole batch
+	// required: true
+	Validations DryRunValidations `json:"validations"`
+
+	// False when the batch introduces error checks, and then nothing is applied
+	// required: true
+	Valid bool `json:"valid"`
+
+	// Objects of the batch, in the order they are applied
+	// required: true
+	Objects []IstioConfigBatchObject `json:"objects"`
+
+	// True when applying an object failed and the objects applied before were restored
+	RolledBack bool `json:"rolledBack"`
+
+	// Error applying the batch, or rolling it back
+	Error string `json:"error,omitempty"`
+}
+
+// IstioConfigBatchObject identifies an object of a batch and how it is applied
+type IstioConfigBatchObject struct {
+	// Type of the object, in plural
+	// example: virtualservices
+	ObjectType string `json:"objectType"`
+
+	// example: reviews
+	Name string `json:"name"`
+
+	// Operation applied: create when the object doesn't exist, update otherwise
+	// example: create
+	Operation string `json:"operation"`
+
+	// True when the object was applied, and not rolled back
+	Applied bool `json:"applied"`
+}
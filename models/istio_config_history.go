@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// IstioConfigRevision istioConfigRevision
+//
+// This is used for returning a change applied to an Istio object through Kiali
+//
+// swagger:model istioConfigRevision
+type IstioConfigRevision struct {
+	// Number of the revision, increasing for each change of the object
+	// example: 3
+	Revision   int    `json:"revision"`
+	Namespace  string `json:"namespace"`
+	ObjectType string `json:"objectType"`
+	Name       string `json:"name"`
+	// Operation applied: create, update or delete
+	// example: update
+	Operation string `json:"operation"`
+	// User that applied the change, as authenticated in Kiali
+	User      string    `json:"user"`
+	Timestamp time.Time `json:"timestamp"`
+	// The object before the change, empty on create
+	Before json.RawMessage `json:"before,omitempty"`
+	// The object after the change, empty on delete
+	After json.RawMessage `json:"after,omitempty"`
+}
+
+// IstioConfigRevisionDiff istioConfigRevisionDiff
+//
+// This is used for returning the differences of an Istio object between two revisions
+//
+// swagger:model istioConfigRevisionDiff
+type IstioConfigRevisionDiff struct {
+	// Revision compared from, 0 when comparing a revision with the object before it
+	From int `json:"from"`
+	To   int `json:"to"`
+	// Line based diff between the YAML of the object in both revisions
+	Diff string `json:"diff"`
+}
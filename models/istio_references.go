@@ -0,0 +1,39 @@
+package models
+
+// IstioReferences istioReferences
+//
+// This is used for returning the Istio objects referencing a service, workload, gateway or destination rule
+//
+// swagger:model istioReferences
+type IstioReferences struct {
+	// Type of the object referenced: services, workloads, gateways or destinationrules
+	// example: services
+	ObjectType string `json:"objectType"`
+
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// example: reviews
+	Name string `json:"name"`
+
+	// References found, one per field referencing the object
+	// required: true
+	References []IstioReference `json:"references"`
+}
+
+// IstioReference is a field of an Istio object referencing another object
+type IstioReference struct {
+	// Type of the Istio object referencing, in plural
+	// example: virtualservices
+	ObjectType string `json:"objectType"`
+
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// example: reviews
+	Name string `json:"name"`
+
+	// JSON path of the field with the reference
+	// example: spec.http[0].route[1].destination.host
+	Path string `json:"path"`
+}
@@ -0,0 +1,53 @@
+package models
+
+// Confidence levels of an UnusedIstioConfig finding
+const (
+	// The object points to, or selects, nothing in the registry
+	HighConfidence = "high"
+	// No traffic was observed for the object during the lookback
+	MediumConfidence = "medium"
+	// No traffic was observed, but the lookback is short or the object is newer than the lookback
+	LowConfidence = "low"
+)
+
+// UnusedIstioConfigReport unusedIstioConfigReport
+//
+// This is used for returning the Istio objects of a namespace that have no effect
+//
+// swagger:model unusedIstioConfigReport
+type UnusedIstioConfigReport struct {
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// Duration of the traffic analyzed
+	// example: 7d
+	Lookback string `json:"lookback"`
+
+	// Objects, or parts of them, with no effect
+	// required: true
+	Items []UnusedIstioConfig `json:"items"`
+}
+
+// UnusedIstioConfig is an Istio object, or a part of it, with no effect
+type UnusedIstioConfig struct {
+	// Type of the Istio object, in plural
+	// example: destinationrules
+	ObjectType string `json:"objectType"`
+
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// example: reviews
+	Name string `json:"name"`
+
+	// Path of the unused field, as in the validations
+	// example: spec/subsets[1]
+	Path string `json:"path"`
+
+	// example: subset never routed to
+	Reason string `json:"reason"`
+
+	// Confidence level of the finding: high, medium or low
+	// example: high
+	Confidence string `json:"confidence"`
+}
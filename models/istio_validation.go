@@ -30,6 +30,62 @@ type IstioValidationSummary struct {
 	Warnings int `json:"warnings"`
 }
 
+// MeshValidations represents the validations of all the namespaces of the mesh, with a summary per namespace.
+// swagger:model
+type MeshValidations struct {
+	// Validation summary per namespace
+	// required: true
+	Summaries map[string]IstioValidationSummary `json:"summaries"`
+
+	// Validations per namespace
+	// required: true
+	Validations NamespaceValidations `json:"validations"`
+}
+
+// Operations of an IstioConfigChange
+const (
+	CreateOperation = "create"
+	UpdateOperation = "update"
+	DeleteOperation = "delete"
+)
+
+// IstioConfigChange represents a proposed change of an Istio object, to be validated without applying it.
+// swagger:model
+type IstioConfigChange struct {
+	// Operation to perform: create, update or delete
+	// required: true
+	// example: update
+	Operation string `json:"operation"`
+
+	// Type of the object, in plural
+	// required: true
+	// example: virtualservices
+	ObjectType string `json:"objectType"`
+
+	// Name of the object. On create it is taken from the object metadata when empty
+	// example: reviews
+	Name string `json:"name"`
+
+	// Object to create, or JSON Merge Patch to apply on update
+	Object json.RawMessage `json:"object,omitempty"`
+}
+
+// DryRunValidations represents the differences of the validations of a namespace when an IstioConfigChange is applied.
+// swagger:model
+type DryRunValidations struct {
+	// Checks that appear with the change
+	// required: true
+	Introduced IstioValidations `json:"introduced"`
+
+	// Checks that disappear with the change
+	// required: true
+	Resolved IstioValidations `json:"resolved"`
+
+	// Checks found with and without the change
+	// required: true
+	Unchanged IstioValidations `json:"unchanged"`
+}
+
 // IstioValidations represents a set of IstioValidation grouped by IstioValidationKey.
 type IstioValidations map[IstioValidationKey]*IstioValidation
 
@@ -56,11 +112,19 @@ type IstioValidation struct {
 
 	// Related objects (only validation errors)
 	References []IstioValidationKey `json:"references"`
+
+	// Array of checks suppressed by annotation or configuration. They don't affect the validity of the object.
+	Suppressed []*IstioCheck `json:"suppressed,omitempty"`
 }
 
 // IstioCheck represents an individual check.
 // swagger:model
 type IstioCheck struct {
+	// Stable code that identifies the check
+	// required: true
+	// example: KIA1104
+	Code string `json:"code"`
+
 	// Description of the check
 	// required: true
 	// example: Weight sum should be 100
@@ -78,6 +142,10 @@ type IstioCheck struct {
 
 type SeverityLevel string
 
+// SuppressValidationsAnnotation is the annotation used on an Istio object to list the check codes, comma separated,
+// that are suppressed for that object. i.e. kiali.io/suppress-validations: KIA0101,KIA0202
+const SuppressValidationsAnnotation = "kiali.io/suppress-validations"
+
 const (
 	ErrorSeverity   SeverityLevel = "error"
 	WarningSeverity SeverityLevel = "warning"
@@ -105,158 +173,237 @@ var ObjectTypeSingular = map[string]string{
 
 var checkDescriptors = map[string]IstioCheck{
 	"authorizationpolicy.source.namespacenotfound": {
+		Code:     "KIA0101",
 		Message:  "KIA0101 Namespace not found for this rule",
 		Severity: WarningSeverity,
 	},
 	"authorizationpolicy.to.wrongmethod": {
+		Code:     "KIA0102",
 		Message:  "KIA0102 Only HTTP methods and fully-qualified gRPC names are allowed",
 		Severity: WarningSeverity,
 	},
 	"authorizationpolicy.nodest.matchingregistry": {
+		Code:     "KIA0104",
 		Message:  "KIA0104 This host has no matching entry in the service registry",
 		Severity: ErrorSeverity,
 	},
 	"authorizationpolicy.mtls.needstobeenabled": {
+		Code:     "KIA0105",
 		Message:  "KIA0105 This field requires mTLS to be enabled",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.multimatch": {
+		Code:     "KIA0201",
 		Message:  "KIA0201 More than one DestinationRules for the same host subset combination",
 		Severity: WarningSeverity,
 	},
 	"destinationrules.nodest.matchingregistry": {
+		Code:     "KIA0202",
 		Message:  "KIA0202 This host has no matching entry in the service registry (service, workload or service entries)",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.nodest.subsetlabels": {
+		Code:     "KIA0203",
 		Message:  "KIA0203 This subset's labels are not found in any matching host",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.trafficpolicy.notlssettings": {
+		Code:     "KIA0204",
 		Message:  "KIA0204 mTLS settings of a non-local Destination Rule are overridden",
 		Severity: WarningSeverity,
 	},
 	"destinationrules.mtls.meshpolicymissing": {
+		Code:     "KIA0205",
 		Message:  "KIA0205 PeerAuthentication enabling mTLS at mesh level is missing",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.mtls.nspolicymissing": {
+		Code:     "KIA0206",
 		Message:  "KIA0206 PeerAuthentication enabling namespace-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.mtls.policymtlsenabled": {
+		Code:     "KIA0207",
 		Message:  "KIA0207 PeerAuthentication with TLS strict mode found, it should be permissive",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.mtls.meshpolicymtlsenabled": {
+		Code:     "KIA0208",
 		Message:  "KIA0208 PeerAuthentication enabling mTLS found, permissive mode needed",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.nodest.subsetnolabels": {
+		Code:     "KIA0209",
 		Message:  "KIA0209 This subset has not labels",
 		Severity: WarningSeverity,
 	},
 	"gateways.multimatch": {
+		Code:     "KIA0301",
 		Message:  "KIA0301 More than one Gateway for the same host port combination",
 		Severity: WarningSeverity,
 	},
 	"gateways.selector": {
+		Code:     "KIA0302",
 		Message:  "KIA0302 No matching workload found for gateway selector in this namespace",
 		Severity: WarningSeverity,
 	},
 	"generic.multimatch.selectorless": {
+		Code:     "KIA0002",
 		Message:  "KIA0002 More than one selector-less object in the same namespace",
 		Severity: ErrorSeverity,
 	},
 	"generic.multimatch.selector": {
+		Code:     "KIA0003",
 		Message:  "KIA0003 More than one object applied to the same workload",
 		Severity: ErrorSeverity,
 	},
 	"generic.selector.workloadnotfound": {
+		Code:     "KIA0004",
 		Message:  "KIA0004 No matching workload found for the selector in this namespace",
 		Severity: WarningSeverity,
 	},
 	"peerauthentication.mtls.destinationrulemissing": {
+		Code:     "KIA0401",
 		Message:  "KIA0401 Mesh-wide Destination Rule enabling mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"peerauthentications.mtls.destinationrulemissing": {
+		Code:     "KIA0501",
 		Message:  "KIA0501 Destination Rule enabling namespace-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"peerauthentications.mtls.disabledestinationrulemissing": {
+		Code:     "KIA0505",
 		Message:  "KIA0505 Destination Rule disabling namespace-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"peerauthentications.mtls.disablemeshdestinationrulemissing": {
+		Code:     "KIA0506",
 		Message:  "KIA0506 Destination Rule disabling mesh-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"port.name.mismatch": {
+		Code:     "KIA0601",
 		Message:  "KIA0601 Port name must follow <protocol>[-suffix] form",
 		Severity: ErrorSeverity,
 	},
 	"service.deployment.port.mismatch": {
+		Code:     "KIA0701",
 		Message:  "KIA0701 Deployment exposing same port as Service not found",
 		Severity: WarningSeverity,
 	},
 	"servicerole.invalid.services": {
+		Code:     "KIA0901",
 		Message:  "KIA0901 Unable to find all the defined services",
 		Severity: ErrorSeverity,
 	},
 	"servicerole.invalid.namespace": {
+		Code:     "KIA0902",
 		Message:  "KIA0902 ServiceRole can only point to current namespace",
 		Severity: ErrorSeverity,
 	},
 	"servicerolebinding.invalid.role": {
+		Code:     "KIA0903",
 		Message:  "KIA0903 ServiceRole does not exists in this namespace",
 		Severity: ErrorSeverity,
 	},
 	"sidecar.egress.invalidhostformat": {
+		Code:     "KIA1003",
 		Message:  "KIA1003 Invalid host format. 'namespace/dnsName' format expected",
 		Severity: ErrorSeverity,
 	},
 	"sidecar.egress.servicenotfound": {
+		Code:     "KIA1004",
 		Message:  "KIA1004 This host has no matching entry in the service registry",
 		Severity: WarningSeverity,
 	},
+	"sidecar.egress.trafficexcluded": {
+		Code:     "KIA1007",
+		Message:  "KIA1007 Observed traffic to a destination not included in the egress hosts, it will be blocked",
+		Severity: ErrorSeverity,
+	},
+	"sidecar.egress.hostwithouttraffic": {
+		Code:     "KIA1008",
+		Message:  "KIA1008 No traffic observed to this host, the egress scope may be broader than needed",
+		Severity: WarningSeverity,
+	},
 	"sidecar.global.selector": {
+		Code:     "KIA1006",
 		Message:  "KIA1006 Global default sidecar should not have workloadSelector",
 		Severity: WarningSeverity,
 	},
 	"virtualservices.gateway.oldnomenclature": {
+		Code:     "KIA1108",
 		Message:  "KIA1108 Preferred nomenclature: <gateway namespace>/<gateway name>",
 		Severity: Unknown,
 	},
+	"virtualservices.delegate.notfound": {
+		Code:     "KIA1109",
+		Message:  "KIA1109 Delegate VirtualService not found",
+		Severity: ErrorSeverity,
+	},
+	"virtualservices.delegate.hostsorgateways": {
+		Code:     "KIA1110",
+		Message:  "KIA1110 A delegate VirtualService must not define hosts or gateways",
+		Severity: ErrorSeverity,
+	},
+	"virtualservices.delegate.cycle": {
+		Code:     "KIA1111",
+		Message:  "KIA1111 Delegation cycle: this VirtualService is reached again through its delegates",
+		Severity: ErrorSeverity,
+	},
+	"virtualservices.delegate.matchconflict": {
+		Code:     "KIA1112",
+		Message:  "KIA1112 Match conditions conflict with the ones of the parent VirtualService, the route is ignored",
+		Severity: ErrorSeverity,
+	},
+	"virtualservices.delegate.routeset": {
+		Code:     "KIA1113",
+		Message:  "KIA1113 A route with delegate must not define route or redirect",
+		Severity: ErrorSeverity,
+	},
+	"virtualservices.delegate.noroutes": {
+		Code:     "KIA1114",
+		Message:  "KIA1114 Delegate VirtualService doesn't define any HTTP route to inherit",
+		Severity: WarningSeverity,
+	},
 	"virtualservices.nohost.hostnotfound": {
+		Code:     "KIA1101",
 		Message:  "KIA1101 DestinationWeight on route doesn't have a valid service (host not found)",
 		Severity: ErrorSeverity,
 	},
 	"virtualservices.nogateway": {
+		Code:     "KIA1102",
 		Message:  "KIA1102 VirtualService is pointing to a non-existent gateway",
 		Severity: ErrorSeverity,
 	},
 	"virtualservices.nohost.invalidprotocol": {
+		Code:     "KIA1103",
 		Message:  "KIA1103 VirtualService doesn't define any valid route protocol",
 		Severity: ErrorSeverity,
 	},
 	"virtualservices.route.singleweight": {
+		Code:     "KIA1104",
 		Message:  "KIA1104 The weight is assumed to be 100 because there is only one route destination",
 		Severity: WarningSeverity,
 	},
 	"virtualservices.route.repeatedsubset": {
+		Code:     "KIA1105",
 		Message:  "KIA1105 This subset is already referenced in another route destination",
 		Severity: WarningSeverity,
 	},
 	"virtualservices.singlehost": {
+		Code:     "KIA1106",
 		Message:  "KIA1106 More than one Virtual Service for same host",
 		Severity: WarningSeverity,
 	},
 	"virtualservices.subsetpresent.subsetnotfound": {
+		Code:     "KIA1107",
 		Message:  "KIA1107 Subset not found",
 		Severity: WarningSeverity,
 	},
 	"validation.unable.cross-namespace": {
+		Code:     "KIA0001",
 		Message:  "KIA0001 Unable to verify the validity, cross-namespace validation is not supported for this field",
 		Severity: Unknown,
 	},
@@ -320,6 +467,41 @@ func (iv IstioValidations) FilterByTypes(objectTypes []string) IstioValidations
 	return fiv
 }
 
+// FilterByNamespace returns the validations of the objects of the given namespace
+func (iv IstioValidations) FilterByNamespace(namespace string) IstioValidations {
+	fiv := IstioValidations{}
+	for k, v := range iv {
+		if k.Namespace == namespace {
+			fiv[k] = v
+		}
+	}
+
+	return fiv
+}
+
+// FilterBySeverities returns the validations with checks of the given severities, keeping only those checks
+func (iv IstioValidations) FilterBySeverities(severities []SeverityLevel) IstioValidations {
+	fiv := IstioValidations{}
+	for k, v := range iv {
+		checks := make([]*IstioCheck, 0, len(v.Checks))
+		for _, c := range v.Checks {
+			for _, s := range severities {
+				if c.Severity == s {
+					checks = append(checks, c)
+					break
+				}
+			}
+		}
+		if len(checks) > 0 {
+			fv := *v
+			fv.Checks = checks
+			fiv[k] = &fv
+		}
+	}
+
+	return fiv
+}
+
 func (iv IstioValidations) MergeValidations(validations IstioValidations) IstioValidations {
 	for key, validation := range validations {
 		v, ok := iv[key]
@@ -352,6 +534,33 @@ func (iv IstioValidations) MergeValidations(validations IstioValidations) IstioV
 	return iv
 }
 
+// SuppressChecks moves the checks whose code is in codes to the Suppressed list.
+// Validity is re-evaluated with the remaining checks when at least one check is suppressed.
+func (v *IstioValidation) SuppressChecks(codes map[string]bool) {
+	if len(codes) == 0 {
+		return
+	}
+	checks := make([]*IstioCheck, 0, len(v.Checks))
+	for _, check := range v.Checks {
+		if codes[check.Code] {
+			v.Suppressed = append(v.Suppressed, check)
+		} else {
+			checks = append(checks, check)
+		}
+	}
+	if len(checks) == len(v.Checks) {
+		return
+	}
+	v.Checks = checks
+	v.Valid = true
+	for _, check := range checks {
+		if check.Severity == ErrorSeverity {
+			v.Valid = false
+			break
+		}
+	}
+}
+
 func (iv IstioValidations) MergeReferences(validations IstioValidations) IstioValidations {
 	for _, currentValidations := range iv {
 		if currentValidations.References == nil {
@@ -398,3 +607,56 @@ func (iv IstioValidations) MarshalJSON() ([]byte, error) {
 	}
 	return json.Marshal(out)
 }
+
+// DiffValidations compares the checks of two sets of validations of the same objects.
+// Introduced validations keep the validity of after, resolved ones the validity of before.
+func DiffValidations(before, after IstioValidations) DryRunValidations {
+	diff := DryRunValidations{
+		Introduced: IstioValidations{},
+		Resolved:   IstioValidations{},
+		Unchanged:  IstioValidations{},
+	}
+	for k, v := range after {
+		var previous []*IstioCheck
+		if b, found := before[k]; found {
+			previous = b.Checks
+		}
+		introduced, unchanged := splitChecks(v.Checks, previous)
+		addChecks(diff.Introduced, k, v, introduced)
+		addChecks(diff.Unchanged, k, v, unchanged)
+	}
+	for k, v := range before {
+		var current []*IstioCheck
+		if a, found := after[k]; found {
+			current = a.Checks
+		}
+		resolved, _ := splitChecks(v.Checks, current)
+		addChecks(diff.Resolved, k, v, resolved)
+	}
+	return diff
+}
+
+// splitChecks returns the checks not found in others, and the ones found
+func splitChecks(checks, others []*IstioCheck) ([]*IstioCheck, []*IstioCheck) {
+	missing, found := make([]*IstioCheck, 0), make([]*IstioCheck, 0)
+Checks:
+	for _, c := range checks {
+		for _, o := range others {
+			if *c == *o {
+				found = append(found, c)
+				continue Checks
+			}
+		}
+		missing = append(missing, c)
+	}
+	return missing, found
+}
+
+func addChecks(iv IstioValidations, key IstioValidationKey, validation *IstioValidation, checks []*IstioCheck) {
+	if len(checks) == 0 {
+		return
+	}
+	v := *validation
+	v.Checks = checks
+	iv[key] = &v
+}
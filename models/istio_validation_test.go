@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -69,3 +70,118 @@ func TestSummarizeValidations(t *testing.T) {
 	assert.Equal(2, summary.Errors)
 	assert.Equal(2, summary.Errors)
 }
+
+func TestCheckDescriptorsHaveCodes(t *testing.T) {
+	assert := assert.New(t)
+
+	for id, check := range checkDescriptors {
+		assert.NotEmpty(check.Code, id)
+		assert.Equal(check.Code, Build(id, "").Code)
+		assert.True(strings.HasPrefix(check.Message, check.Code+" "), id)
+	}
+}
+
+func TestSuppressChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	validation := &IstioValidation{
+		Name:       "foo",
+		ObjectType: "virtualservice",
+		Valid:      false,
+		Checks: []*IstioCheck{
+			{Code: "KIA1101", Severity: ErrorSeverity, Message: "Message 1"},
+			{Code: "KIA1104", Severity: WarningSeverity, Message: "Message 2"},
+		},
+	}
+
+	validation.SuppressChecks(map[string]bool{"KIA0101": true})
+	assert.False(validation.Valid)
+	assert.Len(validation.Checks, 2)
+	assert.Empty(validation.Suppressed)
+
+	validation.SuppressChecks(map[string]bool{"KIA1101": true})
+	assert.True(validation.Valid)
+	assert.Len(validation.Checks, 1)
+	assert.Equal("KIA1104", validation.Checks[0].Code)
+	assert.Len(validation.Suppressed, 1)
+	assert.Equal("KIA1101", validation.Suppressed[0].Code)
+}
+
+func TestFilterBySeverities(t *testing.T) {
+	assert := assert.New(t)
+
+	validations := IstioValidations{
+		IstioValidationKey{ObjectType: "virtualservice", Name: "foo", Namespace: "bookinfo"}: &IstioValidation{
+			Name:       "foo",
+			ObjectType: "virtualservice",
+			Valid:      false,
+			Checks: []*IstioCheck{
+				{Severity: ErrorSeverity, Message: "Message 1"},
+				{Severity: WarningSeverity, Message: "Message 2"},
+			},
+		},
+		IstioValidationKey{ObjectType: "virtualservice", Name: "bar", Namespace: "bookinfo"}: &IstioValidation{
+			Name:       "bar",
+			ObjectType: "virtualservice",
+			Valid:      true,
+			Checks: []*IstioCheck{
+				{Severity: WarningSeverity, Message: "Message 3"},
+			},
+		},
+	}
+
+	errors := validations.FilterBySeverities([]SeverityLevel{ErrorSeverity})
+	assert.Len(errors, 1)
+	assert.Len(errors[IstioValidationKey{ObjectType: "virtualservice", Name: "foo", Namespace: "bookinfo"}].Checks, 1)
+	// Original validations are not modified
+	assert.Len(validations[IstioValidationKey{ObjectType: "virtualservice", Name: "foo", Namespace: "bookinfo"}].Checks, 2)
+
+	all := validations.FilterBySeverities([]SeverityLevel{ErrorSeverity, WarningSeverity})
+	assert.Len(all, 2)
+}
+
+func TestDiffValidations(t *testing.T) {
+	assert := assert.New(t)
+
+	fooKey := IstioValidationKey{ObjectType: "virtualservice", Name: "foo", Namespace: "bookinfo"}
+	barKey := IstioValidationKey{ObjectType: "virtualservice", Name: "bar", Namespace: "bookinfo"}
+	before := IstioValidations{
+		fooKey: &IstioValidation{
+			Name:       "foo",
+			ObjectType: "virtualservice",
+			Valid:      false,
+			Checks: []*IstioCheck{
+				{Code: "KIA1101", Severity: ErrorSeverity, Message: "Message 1", Path: "spec/http[0]"},
+				{Code: "KIA1102", Severity: WarningSeverity, Message: "Message 2", Path: "spec/http[0]"},
+			},
+		},
+	}
+	after := IstioValidations{
+		fooKey: &IstioValidation{
+			Name:       "foo",
+			ObjectType: "virtualservice",
+			Valid:      true,
+			Checks: []*IstioCheck{
+				{Code: "KIA1102", Severity: WarningSeverity, Message: "Message 2", Path: "spec/http[0]"},
+			},
+		},
+		barKey: &IstioValidation{
+			Name:       "bar",
+			ObjectType: "virtualservice",
+			Valid:      false,
+			Checks: []*IstioCheck{
+				{Code: "KIA1101", Severity: ErrorSeverity, Message: "Message 1", Path: "spec/http[0]"},
+			},
+		},
+	}
+
+	diff := DiffValidations(before, after)
+	assert.Len(diff.Introduced, 1)
+	assert.False(diff.Introduced[barKey].Valid)
+	assert.Len(diff.Resolved, 1)
+	assert.False(diff.Resolved[fooKey].Valid)
+	assert.Equal("KIA1101", diff.Resolved[fooKey].Checks[0].Code)
+	assert.Len(diff.Unchanged, 1)
+	assert.True(diff.Unchanged[fooKey].Valid)
+	assert.Equal("KIA1102", diff.Unchanged[fooKey].Checks[0].Code)
+}
@@ -37,6 +37,20 @@ func (q *IstioMetricsQuery) FillDefaults() {
 	q.Direction = "outbound"
 }
 
+// ContainerFilters returns the container metrics requested by the filters. They are only fetched when requested.
+func (q *IstioMetricsQuery) ContainerFilters() []string {
+	filters := []string{}
+	for _, filter := range q.Filters {
+		for _, name := range prometheus.ContainerMetrics {
+			if filter == name {
+				filters = append(filters, filter)
+				break
+			}
+		}
+	}
+	return filters
+}
+
 // CustomMetricsQuery holds query parameters for a custom metrics query
 type CustomMetricsQuery struct {
 	prometheus.RangeQuery
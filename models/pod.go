@@ -27,6 +27,7 @@ type Pod struct {
 	VersionLabel        bool              `json:"versionLabel"`
 	Annotations         map[string]string `json:"annotations"`
 	ProxyStatus         *ProxyStatus      `json:"proxyStatus"`
+	Events              Events            `json:"events,omitempty"`
 }
 
 // Reference holds some information on the pod creator
@@ -50,6 +50,7 @@ type ServiceDetails struct {
 	Validations       IstioValidations  `json:"validations"`
 	NamespaceMTLS     MTLSStatus        `json:"namespaceMTLS"`
 	AdditionalDetails []AdditionalItem  `json:"additionalDetails"`
+	Events            Events            `json:"events"`
 }
 
 type Services []*Service
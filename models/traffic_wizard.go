@@ -0,0 +1,186 @@
+package models
+
+// Traffic wizards, used as label value of the objects generated
+const (
+	WeightedRoutingWizard  = "weighted_routing"
+	RequestRoutingWizard   = "request_routing"
+	FaultInjectionWizard   = "fault_injection"
+	RequestTimeoutsWizard  = "request_timeouts"
+	CircuitBreakerWizard   = "circuit_breaker"
+	TrafficMirroringWizard = "traffic_mirroring"
+)
+
+// WeightedRouting weightedRouting
+//
+// This is used for splitting the traffic of a service across its versions
+//
+// swagger:model weightedRouting
+type WeightedRouting struct {
+	// Weights of the versions, adding up to 100
+	// required: true
+	Weights []VersionWeight `json:"weights"`
+}
+
+// VersionWeight is the percentage of traffic routed to a version
+type VersionWeight struct {
+	// example: v1
+	Version string `json:"version"`
+
+	// example: 80
+	Weight int `json:"weight"`
+}
+
+// RequestRouting requestRouting
+//
+// This is used for routing the requests of a service to a version by header or cookie
+//
+// swagger:model requestRouting
+type RequestRouting struct {
+	// Rules evaluated in order, the first one matching routes the request
+	// required: true
+	Rules []RequestRoutingRule `json:"rules"`
+
+	// Version of the requests not matching any rule. All the versions when empty
+	// example: v1
+	DefaultVersion string `json:"defaultVersion,omitempty"`
+}
+
+// RequestRoutingRule routes to a version the requests with a header or a cookie
+type RequestRoutingRule struct {
+	// Header matched, exclusive with cookie
+	// example: end-user
+	Header string `json:"header,omitempty"`
+
+	// Cookie matched, exclusive with header
+	// example: user
+	Cookie string `json:"cookie,omitempty"`
+
+	// Value of the header or cookie
+	// example: jason
+	Value string `json:"value"`
+
+	// True when the value is a regular expression
+	Regex bool `json:"regex,omitempty"`
+
+	// example: v2
+	Version string `json:"version"`
+}
+
+// FaultInjection faultInjection
+//
+// This is used for injecting delays and aborts in the requests of a service
+//
+// swagger:model faultInjection
+type FaultInjection struct {
+	Delay *FaultDelay `json:"delay,omitempty"`
+	Abort *FaultAbort `json:"abort,omitempty"`
+}
+
+// FaultDelay delays a percentage of the requests
+type FaultDelay struct {
+	// example: 10
+	Percentage float64 `json:"percentage"`
+
+	// example: 5s
+	FixedDelay string `json:"fixedDelay"`
+}
+
+// FaultAbort aborts a percentage of the requests with an HTTP status
+type FaultAbort struct {
+	// example: 10
+	Percentage float64 `json:"percentage"`
+
+	// example: 503
+	HttpStatus int `json:"httpStatus"`
+}
+
+// RequestTimeouts requestTimeouts
+//
+// This is used for setting the timeout and the retries of the requests of a service
+//
+// swagger:model requestTimeouts
+type RequestTimeouts struct {
+	// example: 10s
+	Timeout string `json:"timeout,omitempty"`
+
+	Retries *Retries `json:"retries,omitempty"`
+}
+
+// Retries of a failed request
+type Retries struct {
+	// example: 3
+	Attempts int `json:"attempts"`
+
+	// example: 2s
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+
+	// Conditions retried, as the Envoy x-envoy-retry-on header
+	// example: gateway-error,connect-failure
+	RetryOn string `json:"retryOn,omitempty"`
+}
+
+// CircuitBreaker circuitBreaker
+//
+// This is used for limiting the connections to a service and ejecting its failing hosts
+//
+// swagger:model circuitBreaker
+type CircuitBreaker struct {
+	// example: 100
+	MaxConnections int `json:"maxConnections,omitempty"`
+
+	// example: 10
+	HTTP1MaxPendingRequests int `json:"http1MaxPendingRequests,omitempty"`
+
+	// example: 1
+	MaxRequestsPerConnection int `json:"maxRequestsPerConnection,omitempty"`
+
+	// Consecutive 5xx errors ejecting a host
+	// example: 5
+	Consecutive5xxErrors int `json:"consecutive5xxErrors,omitempty"`
+
+	// example: 10s
+	Interval string `json:"interval,omitempty"`
+
+	// example: 30s
+	BaseEjectionTime string `json:"baseEjectionTime,omitempty"`
+
+	// example: 100
+	MaxEjectionPercent int `json:"maxEjectionPercent,omitempty"`
+}
+
+// TrafficMirroring trafficMirroring
+//
+// This is used for mirroring the requests of a version of a service to another version
+//
+// swagger:model trafficMirroring
+type TrafficMirroring struct {
+	// Version receiving the requests
+	// required: true
+	// example: v1
+	Version string `json:"version"`
+
+	// Version receiving the copies of the requests, whose responses are discarded
+	// required: true
+	// example: v2
+	MirrorVersion string `json:"mirrorVersion"`
+
+	// Percentage of the requests mirrored, 100 when not set
+	// example: 50
+	Percentage float64 `json:"percentage,omitempty"`
+}
+
+// TrafficWizardResult trafficWizardResult
+//
+// This is used for returning the objects generated by a traffic wizard
+//
+// swagger:model trafficWizardResult
+type TrafficWizardResult struct {
+	// example: weighted_routing
+	Wizard string `json:"wizard"`
+
+	// required: true
+	DestinationRule DestinationRule `json:"destinationRule"`
+
+	// required: true
+	VirtualService VirtualService `json:"virtualService"`
+}
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
 )
 
 type WorkloadList struct {
@@ -112,6 +113,15 @@ type Workload struct {
 
 	// Additional details to display, such as configured annotations
 	AdditionalDetails []AdditionalItem `json:"additionalDetails"`
+
+	// Progress of the rollout, for the workloads controlled by Argo Rollouts
+	ArgoRollout *ArgoRollout `json:"argoRollout,omitempty"`
+
+	// Recent events of the workload, its ReplicaSets and its pods
+	Events Events `json:"events"`
+
+	// CPU and memory of the containers of the pods, absent when they are not reported to Prometheus
+	Resources *WorkloadResources `json:"resources,omitempty"`
 }
 
 type Workloads []*Workload
@@ -207,6 +217,28 @@ func (workload *Workload) ParseStatefulSet(s *apps_v1.StatefulSet) {
 	workload.AvailableReplicas = s.Status.ReadyReplicas
 }
 
+func (workload *Workload) ParseDaemonSet(ds *apps_v1.DaemonSet) {
+	workload.Type = "DaemonSet"
+	workload.parseObjectMeta(&ds.ObjectMeta, &ds.Spec.Template.ObjectMeta)
+	// A DaemonSet has no replicas, it runs a pod in each eligible node
+	workload.DesiredReplicas = ds.Status.DesiredNumberScheduled
+	workload.CurrentReplicas = ds.Status.CurrentNumberScheduled
+	workload.AvailableReplicas = ds.Status.NumberAvailable
+}
+
+func (workload *Workload) ParseArgoRollout(r *kubernetes.ArgoRollout, replicaSets []apps_v1.ReplicaSet) {
+	workload.Type = "Rollout"
+	workload.parseObjectMeta(&r.ObjectMeta, &r.Spec.Template.ObjectMeta)
+	workload.DesiredReplicas = 1
+	if r.Spec.Replicas != nil {
+		workload.DesiredReplicas = *r.Spec.Replicas
+	}
+	workload.CurrentReplicas = r.Status.Replicas
+	workload.AvailableReplicas = r.Status.AvailableReplicas
+	workload.ArgoRollout = &ArgoRollout{}
+	workload.ArgoRollout.Parse(r, replicaSets)
+}
+
 func (workload *Workload) ParsePod(pod *core_v1.Pod) {
 	workload.Type = "Pod"
 	workload.parseObjectMeta(&pod.ObjectMeta, &pod.ObjectMeta)
@@ -333,3 +365,11 @@ func (wl WorkloadList) GetLabels() []labels.Set {
 	}
 	return wLabels
 }
+
+// WorkloadScaleRequest is a change of the number of replicas of a workload
+type WorkloadScaleRequest struct {
+	// Number of desired replicas
+	// required: true
+	// example: 3
+	Replicas *int32 `json:"replicas"`
+}
@@ -0,0 +1,370 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	osapps_v1 "github.com/openshift/api/apps/v1"
+	apps_v1 "k8s.io/api/apps/v1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Status of the rollout of a workload
+const (
+	RolloutProgressing = "Progressing"
+	RolloutComplete    = "Complete"
+	RolloutPaused      = "Paused"
+	RolloutFailed      = "Failed"
+)
+
+const (
+	deploymentRevisionAnnotation   = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation          = "kubernetes.io/change-cause"
+	deploymentConfigNameAnnotation = "openshift.io/deployment-config.name"
+	deploymentConfigVersion        = "openshift.io/deployment-config.latest-version"
+	deploymentConfigPhase          = "openshift.io/deployment.phase"
+)
+
+// WorkloadRollout workloadRollout
+//
+// This is used for returning the progress and the history of the rollout of a workload
+//
+// swagger:model workloadRollout
+type WorkloadRollout struct {
+	// Type of the workload
+	// required: true
+	// example: Deployment
+	Type string `json:"type"`
+
+	// Progressing, Complete, Paused or Failed
+	// required: true
+	// example: Progressing
+	Status string `json:"status"`
+
+	// Description of the progress, as reported by kubectl rollout status
+	// example: 1 of 2 updated replicas are available
+	Message string `json:"message"`
+
+	// Define if the rollout is paused
+	// required: true
+	Paused bool `json:"paused"`
+
+	// Number of desired replicas defined by the user in the controller Spec
+	// required: true
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// Number of replicas running the latest revision
+	// required: true
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// Number of ready replicas
+	// required: true
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// Number of available replicas
+	// required: true
+	AvailableReplicas int32 `json:"availableReplicas"`
+
+	// Latest revision of the workload
+	// example: 3
+	Revision string `json:"revision"`
+
+	// Revisions of the workload, the latest first
+	History []WorkloadRevision `json:"history"`
+}
+
+// WorkloadRevision is a revision of the pod template of a workload
+type WorkloadRevision struct {
+	// Revision number
+	// required: true
+	// example: 3
+	Revision string `json:"revision"`
+
+	// Name of the ReplicaSet, ReplicationController or ControllerRevision holding the revision
+	// required: true
+	// example: reviews-v1-7f6b8c9d4
+	Name string `json:"name"`
+
+	// Creation timestamp (in RFC3339 format)
+	// example: 2018-07-31T12:24:17Z
+	CreatedAt string `json:"createdAt"`
+
+	// Cause of the change, from the kubernetes.io/change-cause annotation
+	ChangeCause string `json:"changeCause,omitempty"`
+
+	// Images of the containers of the revision
+	Images []string `json:"images"`
+
+	// Number of replicas of the revision. StatefulSets and DaemonSets do not report replicas by revision.
+	Replicas int32 `json:"replicas"`
+
+	// Define if this is the latest revision
+	Current bool `json:"current"`
+}
+
+// ParseDeployment sets the rollout of a Deployment from its status and the ReplicaSets it owns
+func (rollout *WorkloadRollout) ParseDeployment(d *apps_v1.Deployment, replicaSets []apps_v1.ReplicaSet) {
+	rollout.Type = "Deployment"
+	rollout.Paused = d.Spec.Paused
+	rollout.DesiredReplicas = 1
+	if d.Spec.Replicas != nil {
+		rollout.DesiredReplicas = *d.Spec.Replicas
+	}
+	rollout.UpdatedReplicas = d.Status.UpdatedReplicas
+	rollout.ReadyReplicas = d.Status.ReadyReplicas
+	rollout.AvailableReplicas = d.Status.AvailableReplicas
+	rollout.Revision = d.Annotations[deploymentRevisionAnnotation]
+
+	// Same steps than kubectl rollout status
+	progressDeadlineExceeded := false
+	for _, condition := range d.Status.Conditions {
+		if condition.Type == apps_v1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			progressDeadlineExceeded = true
+			rollout.Message = condition.Message
+		}
+	}
+	switch {
+	case d.Spec.Paused:
+		rollout.Status = RolloutPaused
+		rollout.Message = "Rollout is paused"
+	case d.Generation > d.Status.ObservedGeneration:
+		rollout.Status = RolloutProgressing
+		rollout.Message = "Waiting for the spec update to be observed"
+	case progressDeadlineExceeded:
+		rollout.Status = RolloutFailed
+	case d.Status.UpdatedReplicas < rollout.DesiredReplicas:
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("%d out of %d new replicas have been updated", d.Status.UpdatedReplicas, rollout.DesiredReplicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("%d old replicas are pending termination", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("%d of %d updated replicas are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	default:
+		rollout.Status = RolloutComplete
+		rollout.Message = "Successfully rolled out"
+	}
+
+	rollout.History = []WorkloadRevision{}
+	for _, rs := range replicaSets {
+		if !isOwnedBy(rs.OwnerReferences, "Deployment", d.Name) {
+			continue
+		}
+		revision := WorkloadRevision{
+			Revision:    rs.Annotations[deploymentRevisionAnnotation],
+			Name:        rs.Name,
+			CreatedAt:   formatTime(rs.CreationTimestamp.Time),
+			ChangeCause: rs.Annotations[changeCauseAnnotation],
+			Images:      templateImages(&rs.Spec.Template),
+			Replicas:    rs.Status.Replicas,
+		}
+		revision.Current = revision.Revision != "" && revision.Revision == rollout.Revision
+		rollout.History = append(rollout.History, revision)
+	}
+	sortRevisions(rollout.History)
+}
+
+// ParseStatefulSet sets the rollout of a StatefulSet from its status and the ControllerRevisions it owns
+func (rollout *WorkloadRollout) ParseStatefulSet(s *apps_v1.StatefulSet, revisions []apps_v1.ControllerRevision) {
+	rollout.Type = "StatefulSet"
+	rollout.DesiredReplicas = 1
+	if s.Spec.Replicas != nil {
+		rollout.DesiredReplicas = *s.Spec.Replicas
+	}
+	rollout.UpdatedReplicas = s.Status.UpdatedReplicas
+	rollout.ReadyReplicas = s.Status.ReadyReplicas
+	// StatefulSets of this API version don't report the available replicas
+	rollout.AvailableReplicas = s.Status.ReadyReplicas
+
+	var partition int32
+	if s.Spec.UpdateStrategy.RollingUpdate != nil && s.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		partition = *s.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+
+	// Same steps than kubectl rollout status
+	switch {
+	case s.Generation > s.Status.ObservedGeneration:
+		rollout.Status = RolloutProgressing
+		rollout.Message = "Waiting for the spec update to be observed"
+	case s.Status.ReadyReplicas < rollout.DesiredReplicas:
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("%d of %d replicas are ready", s.Status.ReadyReplicas, rollout.DesiredReplicas)
+	case partition > 0 && s.Status.UpdatedReplicas < rollout.DesiredReplicas-partition:
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("%d of %d replicas have been updated", s.Status.UpdatedReplicas, rollout.DesiredReplicas-partition)
+	case partition > 0:
+		rollout.Status = RolloutComplete
+		rollout.Message = fmt.Sprintf("Partitioned rollout complete: %d new replicas have been updated", s.Status.UpdatedReplicas)
+	case s.Status.UpdateRevision != s.Status.CurrentRevision:
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("Waiting for the replicas to be updated to revision %s", s.Status.UpdateRevision)
+	default:
+		rollout.Status = RolloutComplete
+		rollout.Message = "Successfully rolled out"
+	}
+
+	rollout.History = controllerRevisionHistory("StatefulSet", s.Name, revisions)
+	for i := range rollout.History {
+		rollout.History[i].Current = rollout.History[i].Name == s.Status.UpdateRevision
+		if rollout.History[i].Current {
+			rollout.Revision = rollout.History[i].Revision
+		}
+	}
+}
+
+// ParseDaemonSet sets the rollout of a DaemonSet from its status and the ControllerRevisions it owns
+func (rollout *WorkloadRollout) ParseDaemonSet(ds *apps_v1.DaemonSet, revisions []apps_v1.ControllerRevision) {
+	rollout.Type = "DaemonSet"
+	rollout.DesiredReplicas = ds.Status.DesiredNumberScheduled
+	rollout.UpdatedReplicas = ds.Status.UpdatedNumberScheduled
+	rollout.ReadyReplicas = ds.Status.NumberReady
+	rollout.AvailableReplicas = ds.Status.NumberAvailable
+
+	// Same steps than kubectl rollout status
+	switch {
+	case ds.Generation > ds.Status.ObservedGeneration:
+		rollout.Status = RolloutProgressing
+		rollout.Message = "Waiting for the spec update to be observed"
+	case ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled:
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("%d out of %d new pods have been updated", ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled)
+	case ds.Status.NumberAvailable < ds.Status.DesiredNumberScheduled:
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("%d of %d updated pods are available", ds.Status.NumberAvailable, ds.Status.DesiredNumberScheduled)
+	default:
+		rollout.Status = RolloutComplete
+		rollout.Message = "Successfully rolled out"
+	}
+
+	// The status of a DaemonSet doesn't name its revision: the latest one is the one being rolled out
+	rollout.History = controllerRevisionHistory("DaemonSet", ds.Name, revisions)
+	if len(rollout.History) > 0 {
+		rollout.History[0].Current = true
+		rollout.Revision = rollout.History[0].Revision
+	}
+}
+
+// controllerRevisionHistory returns the revisions of the ControllerRevisions owned by a controller, the latest first
+func controllerRevisionHistory(kind, name string, revisions []apps_v1.ControllerRevision) []WorkloadRevision {
+	history := []WorkloadRevision{}
+	for _, cr := range revisions {
+		if !isOwnedBy(cr.OwnerReferences, kind, name) {
+			continue
+		}
+		revision := WorkloadRevision{
+			Revision:  strconv.FormatInt(cr.Revision, 10),
+			Name:      cr.Name,
+			CreatedAt: formatTime(cr.CreationTimestamp.Time),
+			Images:    []string{},
+		}
+		// The revision data is a patch of the controller spec holding its pod template
+		data := struct {
+			Spec struct {
+				Template core_v1.PodTemplateSpec `json:"template"`
+			} `json:"spec"`
+		}{}
+		if err := json.Unmarshal(cr.Data.Raw, &data); err == nil {
+			revision.Images = templateImages(&data.Spec.Template)
+		}
+		history = append(history, revision)
+	}
+	sortRevisions(history)
+	return history
+}
+
+// ParseDeploymentConfig sets the rollout of a DeploymentConfig from its status and the ReplicationControllers
+// of its deployments
+func (rollout *WorkloadRollout) ParseDeploymentConfig(dc *osapps_v1.DeploymentConfig, rcs []core_v1.ReplicationController) {
+	rollout.Type = "DeploymentConfig"
+	rollout.Paused = dc.Spec.Paused
+	rollout.DesiredReplicas = dc.Spec.Replicas
+	rollout.UpdatedReplicas = dc.Status.UpdatedReplicas
+	rollout.ReadyReplicas = dc.Status.ReadyReplicas
+	rollout.AvailableReplicas = dc.Status.AvailableReplicas
+	rollout.Revision = strconv.FormatInt(dc.Status.LatestVersion, 10)
+
+	rollout.History = []WorkloadRevision{}
+	latestPhase := ""
+	for _, rc := range rcs {
+		if rc.Annotations[deploymentConfigNameAnnotation] != dc.Name {
+			continue
+		}
+		revision := WorkloadRevision{
+			Revision:    rc.Annotations[deploymentConfigVersion],
+			Name:        rc.Name,
+			CreatedAt:   formatTime(rc.CreationTimestamp.Time),
+			ChangeCause: rc.Annotations[changeCauseAnnotation],
+			Images:      []string{},
+			Replicas:    rc.Status.Replicas,
+			Current:     rc.Annotations[deploymentConfigVersion] == rollout.Revision,
+		}
+		if rc.Spec.Template != nil {
+			revision.Images = templateImages(rc.Spec.Template)
+		}
+		if revision.Current {
+			latestPhase = rc.Annotations[deploymentConfigPhase]
+		}
+		rollout.History = append(rollout.History, revision)
+	}
+	sortRevisions(rollout.History)
+
+	progressDeadlineExceeded := false
+	for _, condition := range dc.Status.Conditions {
+		if condition.Type == osapps_v1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			progressDeadlineExceeded = true
+			rollout.Message = condition.Message
+		}
+	}
+	switch {
+	case dc.Spec.Paused:
+		rollout.Status = RolloutPaused
+		rollout.Message = "Rollout is paused"
+	case dc.Generation > dc.Status.ObservedGeneration:
+		rollout.Status = RolloutProgressing
+		rollout.Message = "Waiting for the spec update to be observed"
+	case progressDeadlineExceeded:
+		rollout.Status = RolloutFailed
+	case latestPhase == "Failed":
+		rollout.Status = RolloutFailed
+		rollout.Message = fmt.Sprintf("Deployment #%s failed", rollout.Revision)
+	case latestPhase != "" && latestPhase != "Complete":
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("Deployment #%s is %s", rollout.Revision, latestPhase)
+	case dc.Status.AvailableReplicas < dc.Status.UpdatedReplicas:
+		rollout.Status = RolloutProgressing
+		rollout.Message = fmt.Sprintf("%d of %d updated replicas are available", dc.Status.AvailableReplicas, dc.Status.UpdatedReplicas)
+	default:
+		rollout.Status = RolloutComplete
+		rollout.Message = "Successfully rolled out"
+	}
+}
+
+func isOwnedBy(refs []meta_v1.OwnerReference, kind, name string) bool {
+	for _, ref := range refs {
+		if ref.Kind == kind && ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func templateImages(template *core_v1.PodTemplateSpec) []string {
+	images := make([]string, 0, len(template.Spec.Containers))
+	for _, c := range template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// sortRevisions sorts the revisions by number, the latest first
+func sortRevisions(revisions []WorkloadRevision) {
+	sort.SliceStable(revisions, func(i, j int) bool {
+		ri, _ := strconv.ParseInt(revisions[i].Revision, 10, 64)
+		rj, _ := strconv.ParseInt(revisions[j].Revision, 10, 64)
+		return ri > rj
+	})
+}
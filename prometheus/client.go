@@ -23,12 +23,17 @@ type ClientInterface interface {
 	FetchHistogramValues(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error)
 	FetchRange(metricName, labels, grouping, aggregator string, q *RangeQuery) Metric
 	FetchRateRange(metricName string, labels []string, grouping string, q *RangeQuery) Metric
+	FetchContainerRange(metricName, namespace string, pods []string, q *RangeQuery) Metric
 	GetAllRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetAppRequestRates(namespace, app, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
 	GetConfiguration() (prom_v1.ConfigResult, error)
+	GetContainerResources(namespace string, pods []string, ratesInterval string, queryTime time.Time) (map[string]model.Vector, error)
+	GetDestinationTraffic(namespaces []string, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetFlags() (prom_v1.FlagsResult, error)
 	GetNamespaceServicesRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
+	GetOutboundTraffic(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetServiceRequestRates(namespace, service, ratesInterval string, queryTime time.Time) (model.Vector, error)
+	GetWorkloadInboundTraffic(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetWorkloadRequestRates(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
 	GetMetricsForLabels(labels []string) ([]string, error)
 }
@@ -144,6 +149,31 @@ func (in *Client) GetNamespaceServicesRequestRates(namespace string, ratesInterv
 	return result, nil
 }
 
+// GetOutboundTraffic queries Prometheus to fetch the HTTP and TCP rates, over a time interval, from the workloads
+// of the namespace to each destination service. The workload is optional, to limit the traffic to a single workload.
+// Samples are grouped by source workload and destination service.
+// Returns (rates, error)
+func (in *Client) GetOutboundTraffic(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetOutboundTraffic [namespace: %s] [workload: %s] [ratesInterval: %s] [queryTime: %s]", namespace, workload, ratesInterval, queryTime.String())
+	return getOutboundTraffic(in.api, namespace, workload, queryTime, ratesInterval)
+}
+
+// GetDestinationTraffic queries Prometheus to fetch the HTTP and TCP rates, over a time interval, into each destination
+// service and version of the namespaces. Samples from both reporters are included, so they are only meaningful to detect traffic.
+// Returns (rates, error)
+func (in *Client) GetDestinationTraffic(namespaces []string, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetDestinationTraffic [namespaces: %v] [ratesInterval: %s] [queryTime: %s]", namespaces, ratesInterval, queryTime.String())
+	return getDestinationTraffic(in.api, namespaces, queryTime, ratesInterval)
+}
+
+// GetWorkloadInboundTraffic queries Prometheus to fetch the HTTP and TCP rates, over a time interval, into a workload.
+// Samples are grouped by source principal, source workload, destination service and request operation.
+// Returns (rates, error)
+func (in *Client) GetWorkloadInboundTraffic(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetWorkloadInboundTraffic [namespace: %s] [workload: %s] [ratesInterval: %s] [queryTime: %s]", namespace, workload, ratesInterval, queryTime.String())
+	return getWorkloadInboundTraffic(in.api, namespace, workload, queryTime, ratesInterval)
+}
+
 // GetServiceRequestRates queries Prometheus to fetch request counters rates over a time interval
 // for a given service (hence only inbound). Note that it does not discriminate on "reporter", so rates can
 // be inflated due to duplication, and therefore should be used mainly for calculating ratios
@@ -210,6 +240,14 @@ func (in *Client) GetWorkloadRequestRates(namespace, workload, ratesInterval str
 	return inResult, outResult, nil
 }
 
+// GetContainerResources queries Prometheus to fetch the CPU and memory usage, requests and limits, and the CPU throttling
+// of the containers of the given pods, summed by container. Results are keyed by container metric (see ContainerMetrics).
+// Returns (resources, error)
+func (in *Client) GetContainerResources(namespace string, pods []string, ratesInterval string, queryTime time.Time) (map[string]model.Vector, error) {
+	log.Tracef("GetContainerResources [namespace: %s] [pods: %v] [ratesInterval: %s] [queryTime: %s]", namespace, pods, ratesInterval, queryTime.String())
+	return getContainerResources(in.api, namespace, pods, queryTime, ratesInterval)
+}
+
 // FetchRange fetches a simple metric (gauge or counter) in given range
 func (in *Client) FetchRange(metricName, labels, grouping, aggregator string, q *RangeQuery) Metric {
 	query := fmt.Sprintf("%s(%s%s)", aggregator, metricName, labels)
@@ -225,6 +263,11 @@ func (in *Client) FetchRateRange(metricName string, labels []string, grouping st
 	return fetchRateRange(in.api, metricName, labels, grouping, q)
 }
 
+// FetchContainerRange fetches one of the ContainerMetrics of the containers of the given pods in given range, by container
+func (in *Client) FetchContainerRange(metricName, namespace string, pods []string, q *RangeQuery) Metric {
+	return fetchContainerRange(in.api, metricName, namespace, pods, q)
+}
+
 // FetchHistogramRange fetches bucketed metric as histogram in given range
 func (in *Client) FetchHistogramRange(metricName, labels, grouping string, q *RangeQuery) Histogram {
 	return fetchHistogramRange(in.api, metricName, labels, grouping, q)
@@ -3,6 +3,8 @@ package prometheus
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
@@ -31,6 +33,15 @@ func fetchRateRange(api prom_v1.API, metricName string, labels []string, groupin
 	return fetchRange(api, query, q.Range)
 }
 
+func fetchContainerRange(api prom_v1.API, metricName, namespace string, pods []string, q *RangeQuery) Metric {
+	query, ok := buildContainerQueries(namespace, pods, q.RateFunc, q.RateInterval)[metricName]
+	if !ok {
+		return Metric{Err: fmt.Errorf("unknown container metric: %s", metricName)}
+	}
+	query = roundSignificant(query, 0.001)
+	return fetchRange(api, query, q.Range)
+}
+
 func fetchHistogramRange(api prom_v1.API, metricName, labels, grouping string, q *RangeQuery) Histogram {
 	// Note: the p8s queries are not run in parallel here, but they are at the caller's place.
 	//	This is because we may not want to create too many threads in the lowest layer
@@ -173,6 +184,127 @@ func getRequestRatesForLabel(api prom_v1.API, time time.Time, labels, ratesInter
 	return result.(model.Vector), nil
 }
 
+// getOutboundTraffic retrieves the destination services called from the namespace, or from a workload of the namespace
+// when workload is not empty. It uses the same source-reported HTTP and TCP metrics than the graph for internal traffic.
+func getOutboundTraffic(api prom_v1.API, namespace, workload string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	lbl := fmt.Sprintf(`reporter="source",source_workload_namespace="%s"`, namespace)
+	if workload != "" {
+		lbl = fmt.Sprintf(`%s,source_workload="%s"`, lbl, workload)
+	}
+	groupBy := "source_workload_namespace,source_workload,destination_service_namespace,destination_service,destination_service_name"
+
+	all := model.Vector{}
+	for _, metric := range []string{"istio_requests_total", "istio_tcp_sent_bytes_total"} {
+		query := fmt.Sprintf("sum(rate(%s{%s}[%s])) by (%s) > 0", metric, lbl, ratesInterval, groupBy)
+		promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetOutboundTraffic")
+		result, err := api.Query(context.Background(), query, queryTime)
+		if err != nil {
+			return model.Vector{}, err
+		}
+		promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+		all = append(all, result.(model.Vector)...)
+	}
+	return all, nil
+}
+
+// getDestinationTraffic retrieves the HTTP and TCP rates into each destination service and version of the namespaces,
+// with any reporter, so traffic out of the mesh (reported by the source only) is included.
+func getDestinationTraffic(api prom_v1.API, namespaces []string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	lbl := fmt.Sprintf(`destination_service_namespace=~"%s"`, strings.Join(namespaces, "|"))
+	groupBy := "destination_service_namespace,destination_service,destination_version"
+
+	all := model.Vector{}
+	for _, metric := range []string{"istio_requests_total", "istio_tcp_sent_bytes_total"} {
+		query := fmt.Sprintf("sum(rate(%s{%s}[%s])) by (%s) > 0", metric, lbl, ratesInterval, groupBy)
+		promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetDestinationTraffic")
+		result, err := api.Query(context.Background(), query, queryTime)
+		if err != nil {
+			return model.Vector{}, err
+		}
+		promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+		all = append(all, result.(model.Vector)...)
+	}
+	return all, nil
+}
+
+// getWorkloadInboundTraffic retrieves the destination-reported HTTP and TCP rates into a workload, grouped by source identity.
+// HTTP rates are also grouped by the request_operation classification label, as the graph aggregate nodes.
+func getWorkloadInboundTraffic(api prom_v1.API, namespace, workload string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	lbl := fmt.Sprintf(`reporter="destination",destination_workload_namespace="%s",destination_workload="%s"`, namespace, workload)
+	groupBy := "source_principal,source_workload_namespace,source_workload,destination_service_namespace,destination_service_name,request_protocol"
+	queries := []string{
+		fmt.Sprintf("sum(rate(istio_requests_total{%s}[%s])) by (%s,request_operation) > 0", lbl, ratesInterval, groupBy),
+		fmt.Sprintf("sum(rate(istio_tcp_sent_bytes_total{%s}[%s])) by (%s) > 0", lbl, ratesInterval, groupBy),
+	}
+
+	all := model.Vector{}
+	for _, query := range queries {
+		promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetWorkloadInboundTraffic")
+		result, err := api.Query(context.Background(), query, queryTime)
+		if err != nil {
+			return model.Vector{}, err
+		}
+		promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+		all = append(all, result.(model.Vector)...)
+	}
+	return all, nil
+}
+
+// getContainerResources retrieves the current CPU and memory metrics of the containers of the given pods, by container
+func getContainerResources(api prom_v1.API, namespace string, pods []string, queryTime time.Time, ratesInterval string) (map[string]model.Vector, error) {
+	queries := buildContainerQueries(namespace, pods, "rate", ratesInterval)
+	resources := make(map[string]model.Vector, len(queries))
+	errChan := make(chan error, len(queries))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for name, query := range queries {
+		wg.Add(1)
+		go func(name, query string) {
+			defer wg.Done()
+			promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetContainerResources")
+			result, err := api.Query(context.Background(), query, queryTime)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+			mutex.Lock()
+			resources[name] = result.(model.Vector)
+			mutex.Unlock()
+		}(name, query)
+	}
+	wg.Wait()
+	if len(errChan) != 0 {
+		return nil, <-errChan
+	}
+	return resources, nil
+}
+
+// buildContainerQueries returns the queries of the ContainerMetrics of the given pods, summed by container. Usage and throttling
+// come from the cAdvisor metrics of the kubelet, requests and limits from kube-state-metrics. The series of the pod cgroup
+// (no container) and of the pause container (POD) are excluded.
+func buildContainerQueries(namespace string, pods []string, rateFunc, rateInterval string) map[string]string {
+	lbl := fmt.Sprintf(`namespace="%s",pod=~"%s",container!="",container!="POD"`, namespace, strings.Join(pods, "|"))
+	rate := func(metric string) string {
+		return fmt.Sprintf("sum(%s(%s{%s}[%s])) by (container)", rateFunc, metric, lbl, rateInterval)
+	}
+	gauge := func(metric, resource string) string {
+		if resource != "" {
+			return fmt.Sprintf(`sum(%s{%s,resource="%s"}) by (container)`, metric, lbl, resource)
+		}
+		return fmt.Sprintf("sum(%s{%s}) by (container)", metric, lbl)
+	}
+	return map[string]string{
+		ContainerCPUUsage:       rate("container_cpu_usage_seconds_total"),
+		ContainerCPURequests:    gauge("kube_pod_container_resource_requests", "cpu"),
+		ContainerCPULimits:      gauge("kube_pod_container_resource_limits", "cpu"),
+		ContainerCPUThrottled:   rate("container_cpu_cfs_throttled_periods_total") + " / " + rate("container_cpu_cfs_periods_total"),
+		ContainerMemoryUsage:    gauge("container_memory_working_set_bytes", ""),
+		ContainerMemoryRequests: gauge("kube_pod_container_resource_requests", "memory"),
+		ContainerMemoryLimits:   gauge("kube_pod_container_resource_limits", "memory"),
+	}
+}
+
 // roundSignificant will output promQL that performs rounding only if the resulting value is significant, that is, higher than the requested precision
 func roundSignificant(innerQuery string, precision float64) string {
 	return fmt.Sprintf("round(%s, %f) > %f or %s", innerQuery, precision, precision, innerQuery)
@@ -88,6 +88,70 @@ func TestGetAllRequestRatesIstioSystem(t *testing.T) {
 	assert.Equal(t, vectorQ2[0], rates[1])
 }
 
+func TestGetOutboundTraffic(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+
+	vectorQ1 := model.Vector{
+		&model.Sample{
+			Timestamp: model.Now(),
+			Value:     model.SampleValue(1),
+			Metric:    model.Metric{"foo": "bar"},
+		},
+	}
+	api.OnQueryTime(`sum(rate(istio_requests_total{reporter="source",source_workload_namespace="ns",source_workload="wk"}[1h])) by (source_workload_namespace,source_workload,destination_service_namespace,destination_service,destination_service_name) > 0`, &queryTime, vectorQ1)
+
+	vectorQ2 := model.Vector{
+		&model.Sample{
+			Timestamp: model.Now(),
+			Value:     model.SampleValue(2),
+			Metric:    model.Metric{"foo": "bar"}},
+	}
+	api.OnQueryTime(`sum(rate(istio_tcp_sent_bytes_total{reporter="source",source_workload_namespace="ns",source_workload="wk"}[1h])) by (source_workload_namespace,source_workload,destination_service_namespace,destination_service,destination_service_name) > 0`, &queryTime, vectorQ2)
+
+	rates, _ := client.GetOutboundTraffic("ns", "wk", "1h", queryTime)
+	assert.Equal(t, 2, rates.Len())
+	assert.Equal(t, vectorQ1[0], rates[0])
+	assert.Equal(t, vectorQ2[0], rates[1])
+}
+
+func TestGetWorkloadInboundTraffic(t *testing.T) {
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+
+	vectorQ1 := model.Vector{
+		&model.Sample{
+			Timestamp: model.Now(),
+			Value:     model.SampleValue(1),
+			Metric:    model.Metric{"foo": "bar"},
+		},
+	}
+	api.OnQueryTime(`sum(rate(istio_requests_total{reporter="destination",destination_workload_namespace="ns",destination_workload="wk"}[1h])) by (source_principal,source_workload_namespace,source_workload,destination_service_namespace,destination_service_name,request_protocol,request_operation) > 0`, &queryTime, vectorQ1)
+
+	vectorQ2 := model.Vector{
+		&model.Sample{
+			Timestamp: model.Now(),
+			Value:     model.SampleValue(2),
+			Metric:    model.Metric{"foo": "bar"}},
+	}
+	api.OnQueryTime(`sum(rate(istio_tcp_sent_bytes_total{reporter="destination",destination_workload_namespace="ns",destination_workload="wk"}[1h])) by (source_principal,source_workload_namespace,source_workload,destination_service_namespace,destination_service_name,request_protocol) > 0`, &queryTime, vectorQ2)
+
+	rates, _ := client.GetWorkloadInboundTraffic("ns", "wk", "1h", queryTime)
+	assert.Equal(t, 2, rates.Len())
+	assert.Equal(t, vectorQ1[0], rates[0])
+	assert.Equal(t, vectorQ2[0], rates[1])
+}
+
 func TestGetNamespaceServicesRequestRates(t *testing.T) {
 	client, api, err := setupMocked()
 	if err != nil {
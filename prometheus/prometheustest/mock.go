@@ -246,6 +246,11 @@ func (o *PromClientMock) MockWorkloadRequestRates(namespace, wkld string, in, ou
 	o.On("GetWorkloadRequestRates", namespace, wkld, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(in, out, nil)
 }
 
+// MockContainerResources mocks GetContainerResources for given namespace and any pods, returning the resources by container metric
+func (o *PromClientMock) MockContainerResources(namespace string, resources map[string]model.Vector) {
+	o.On("GetContainerResources", namespace, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(resources, nil)
+}
+
 func (o *PromClientMock) GetAllRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
 	args := o.Called(namespace, ratesInterval, queryTime)
 	return args.Get(0).(model.Vector), args.Error(1)
@@ -256,6 +261,11 @@ func (o *PromClientMock) GetConfiguration() (prom_v1.ConfigResult, error) {
 	return args.Get(0).(prom_v1.ConfigResult), args.Error(1)
 }
 
+func (o *PromClientMock) GetContainerResources(namespace string, pods []string, ratesInterval string, queryTime time.Time) (map[string]model.Vector, error) {
+	args := o.Called(namespace, pods, ratesInterval, queryTime)
+	return args.Get(0).(map[string]model.Vector), args.Error(1)
+}
+
 func (o *PromClientMock) GetFlags() (prom_v1.FlagsResult, error) {
 	args := o.Called()
 	return args.Get(0).(prom_v1.FlagsResult), args.Error(1)
@@ -266,6 +276,21 @@ func (o *PromClientMock) GetNamespaceServicesRequestRates(namespace, ratesInterv
 	return args.Get(0).(model.Vector), args.Error(1)
 }
 
+func (o *PromClientMock) GetOutboundTraffic(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(namespace, workload, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) GetDestinationTraffic(namespaces []string, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(namespaces, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) GetWorkloadInboundTraffic(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(namespace, workload, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
 func (o *PromClientMock) GetAppRequestRates(namespace, app, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error) {
 	args := o.Called(namespace, app, ratesInterval, queryTime)
 	return args.Get(0).(model.Vector), args.Get(1).(model.Vector), args.Error(2)
@@ -291,6 +316,11 @@ func (o *PromClientMock) FetchRateRange(metricName string, labels []string, grou
 	return args.Get(0).(prometheus.Metric)
 }
 
+func (o *PromClientMock) FetchContainerRange(metricName, namespace string, pods []string, q *prometheus.RangeQuery) prometheus.Metric {
+	args := o.Called(metricName, namespace, pods, q)
+	return args.Get(0).(prometheus.Metric)
+}
+
 func (o *PromClientMock) FetchHistogramRange(metricName, labels, grouping string, q *prometheus.RangeQuery) prometheus.Histogram {
 	args := o.Called(metricName, labels, grouping, q)
 	return args.Get(0).(prometheus.Histogram)
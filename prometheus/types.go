@@ -27,6 +27,29 @@ func (q *RangeQuery) FillDefaults() {
 	q.Avg = true
 }
 
+// Metrics of the CPU and memory of the containers. CPU is in cores, memory in bytes and throttling is the ratio of the CPU
+// periods where the container was throttled.
+const (
+	ContainerCPUUsage       = "container_cpu_usage"
+	ContainerCPURequests    = "container_cpu_requests"
+	ContainerCPULimits      = "container_cpu_limits"
+	ContainerCPUThrottled   = "container_cpu_throttled"
+	ContainerMemoryUsage    = "container_memory_usage"
+	ContainerMemoryRequests = "container_memory_requests"
+	ContainerMemoryLimits   = "container_memory_limits"
+)
+
+// ContainerMetrics are the names of the metrics of the CPU and memory of the containers
+var ContainerMetrics = []string{
+	ContainerCPUUsage,
+	ContainerCPURequests,
+	ContainerCPULimits,
+	ContainerCPUThrottled,
+	ContainerMemoryUsage,
+	ContainerMemoryRequests,
+	ContainerMemoryLimits,
+}
+
 // Metrics contains all simple metrics and histograms data
 type Metrics struct {
 	Metrics    map[string]*Metric   `json:"metrics"`
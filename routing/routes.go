@@ -227,6 +227,7 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/yaml
 		//
 		//     Schemes: http, https
 		//
@@ -241,12 +242,41 @@ func NewRoutes() (r *Routes) {
 			handlers.IstioConfigList,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/istio config istioConfigBatchApply
+		// ---
+		// Endpoint to apply several Istio objects together, as a multi-document YAML or a JSON list.
+		// Objects are validated together and applied in dependency order: ServiceEntries and DestinationRules
+		// before VirtualServices, Gateways before VirtualServices. If an object fails, the ones applied are rolled back.
+		//
+		//     Consumes:
+		//     - application/yaml
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      422: istioConfigBatchResponse
+		//      500: istioConfigBatchResponse
+		//      200: istioConfigBatchResponse
+		//
+		{
+			"IstioConfigBatchApply",
+			"POST",
+			"/api/namespaces/{namespace}/istio",
+			handlers.IstioConfigBatchApply,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/istio/{object_type}/{object} config istioConfigDetails
 		// ---
 		// Endpoint to get the Istio Config of an Istio object
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/yaml
 		//
 		//     Schemes: http, https
 		//
@@ -287,9 +317,11 @@ func NewRoutes() (r *Routes) {
 		// swagger:route PATCH /namespaces/{namespace}/istio/{object_type}/{object} config istioConfigUpdate
 		// ---
 		// Endpoint to update the Istio Config of an Istio object used for templates and adapters using Json Merge Patch strategy.
+		// A YAML body is the full object, merged with the current object as kubectl apply does.
 		//
 		//     Consumes:
 		//	   - application/json
+		//	   - application/yaml
 		//
 		//     Produces:
 		//     - application/json
@@ -309,9 +341,101 @@ func NewRoutes() (r *Routes) {
 			handlers.IstioConfigUpdate,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/istio/{object_type}/{object}/references config istioConfigReferences
+		// ---
+		// Endpoint to get the Istio objects referencing a Gateway or DestinationRule, with the JSON path of each reference
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioReferencesResponse
+		//
+		{
+			"IstioConfigReferences",
+			"GET",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/references",
+			handlers.IstioReferences,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/istio/{object_type}/{object}/revisions config istioConfigRevisions
+		// ---
+		// Endpoint to get the revisions of an Istio object written through Kiali, the oldest first
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: istioConfigRevisionsResponse
+		//
+		{
+			"IstioConfigRevisions",
+			"GET",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/revisions",
+			handlers.IstioConfigRevisions,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/istio/{object_type}/{object}/revisions/diff config istioConfigRevisionsDiff
+		// ---
+		// Endpoint to get the differences of an Istio object between two revisions
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigRevisionDiffResponse
+		//
+		{
+			"IstioConfigRevisionsDiff",
+			"GET",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/revisions/diff",
+			handlers.IstioConfigRevisionsDiff,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/istio/{object_type}/{object}/revisions/{revision}/revert config istioConfigRevert
+		// ---
+		// Endpoint to restore an Istio object as it was after a revision. The object is created or deleted when needed.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigDetailsResponse
+		//
+		{
+			"IstioConfigRevert",
+			"POST",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/revisions/{revision}/revert",
+			handlers.IstioConfigRevert,
+			true,
+		},
 		// swagger:route POST /namespaces/{namespace}/istio/{object_type} config istioConfigCreate
 		// ---
-		// Endpoint to create an Istio object by using an Istio Config item
+		// Endpoint to create an Istio object by using an Istio Config item. A YAML body may hold several objects,
+		// the objects created are then returned as an array, even for a single object.
+		//
+		//     Consumes:
+		//     - application/json
+		//     - application/yaml
 		//
 		//     Produces:
 		//     - application/json
@@ -332,6 +456,162 @@ func NewRoutes() (r *Routes) {
 			handlers.IstioConfigCreate,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/istio/unused config istioConfigUnused
+		// ---
+		// Endpoint to find the Istio objects of a namespace with no effect: objects pointing to or selecting nothing,
+		// and objects with no traffic during the lookback duration. Each finding has a confidence level.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: unusedIstioConfigResponse
+		//
+		{
+			"IstioConfigUnused",
+			"GET",
+			"/api/namespaces/{namespace}/istio/unused",
+			handlers.IstioConfigUnused,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/sidecar/generate config sidecarGenerate
+		// ---
+		// Endpoint to generate a Sidecar whose egress hosts are the services called by the workloads of the namespace during the lookback duration
+		//
+		//     Produces:
+		//     - application/yaml
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200
+		//
+		{
+			"NamespaceSidecarGenerate",
+			"GET",
+			"/api/namespaces/{namespace}/sidecar/generate",
+			handlers.SidecarGenerate,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/sidecar/generate config sidecarGenerateCreate
+		// ---
+		// Endpoint to create the Sidecar generated from the services called by the workloads of the namespace during the lookback duration
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigDetailsResponse
+		//
+		{
+			"NamespaceSidecarGenerateCreate",
+			"POST",
+			"/api/namespaces/{namespace}/sidecar/generate",
+			handlers.SidecarGenerateCreate,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/sidecar/generate config workloadSidecarGenerate
+		// ---
+		// Endpoint to generate a Sidecar whose egress hosts are the services called by the workload during the lookback duration
+		//
+		//     Produces:
+		//     - application/yaml
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200
+		//
+		{
+			"WorkloadSidecarGenerate",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/sidecar/generate",
+			handlers.SidecarGenerate,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/workloads/{workload}/sidecar/generate config workloadSidecarGenerateCreate
+		// ---
+		// Endpoint to create the Sidecar generated from the services called by the workload during the lookback duration
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigDetailsResponse
+		//
+		{
+			"WorkloadSidecarGenerateCreate",
+			"POST",
+			"/api/namespaces/{namespace}/workloads/{workload}/sidecar/generate",
+			handlers.SidecarGenerateCreate,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/authorizationpolicy/generate config authorizationPolicyGenerate
+		// ---
+		// Endpoint to generate an ALLOW AuthorizationPolicy limited to the traffic observed into the workload during the lookback duration
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: authorizationPolicyProposalResponse
+		//
+		{
+			"AuthorizationPolicyGenerate",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/authorizationpolicy/generate",
+			handlers.AuthorizationPolicyGenerate,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/workloads/{workload}/authorizationpolicy/generate config authorizationPolicyGenerateCreate
+		// ---
+		// Endpoint to create the ALLOW AuthorizationPolicy limited to the traffic observed into the workload during the lookback duration
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      409: conflictError
+		//      500: internalError
+		//      200: istioConfigDetailsResponse
+		//
+		{
+			"AuthorizationPolicyGenerateCreate",
+			"POST",
+			"/api/namespaces/{namespace}/workloads/{workload}/authorizationpolicy/generate",
+			handlers.AuthorizationPolicyGenerateCreate,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/services services serviceList
 		// ---
 		// Endpoint to get the details of a given service
@@ -373,6 +653,162 @@ func NewRoutes() (r *Routes) {
 			handlers.ServiceDetails,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/wizards/{wizard} services serviceTrafficWizard
+		// ---
+		// Endpoint to create or update the VirtualService and DestinationRule of a service for a traffic wizard:
+		// weighted_routing, request_routing, fault_injection, request_timeouts, circuit_breaker or traffic_mirroring
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      409: conflictError
+		//      500: internalError
+		//      200: trafficWizardResponse
+		//
+		{
+			"ServiceTrafficWizard",
+			"POST",
+			"/api/namespaces/{namespace}/services/{service}/wizards/{wizard}",
+			handlers.ServiceTrafficWizard,
+			true,
+		},
+		// swagger:route DELETE /namespaces/{namespace}/services/{service}/wizards services serviceTrafficWizardDelete
+		// ---
+		// Endpoint to delete the VirtualServices and DestinationRules generated by the traffic wizards of a service
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200
+		//
+		{
+			"ServiceTrafficWizardDelete",
+			"DELETE",
+			"/api/namespaces/{namespace}/services/{service}/wizards",
+			handlers.ServiceTrafficWizardDelete,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/chaos services serviceChaosExperiment
+		// ---
+		// Endpoint to get the fault injection experiment running on a service, with the health of its requests
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: chaosExperimentResponse
+		//
+		{
+			"ServiceChaosExperiment",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/chaos",
+			handlers.ServiceChaosExperiment,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/chaos services serviceChaosExperimentStart
+		// ---
+		// Endpoint to inject faults in the requests of a service for a duration, after which the previous routes are restored.
+		// The routes are restored by the Kiali service account, which must be allowed to patch and delete the VirtualServices
+		// of the namespace.
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      409: conflictError
+		//      500: internalError
+		//      200: chaosExperimentResponse
+		//
+		{
+			"ServiceChaosExperimentStart",
+			"POST",
+			"/api/namespaces/{namespace}/services/{service}/chaos",
+			handlers.ServiceChaosExperimentStart,
+			true,
+		},
+		// swagger:route DELETE /namespaces/{namespace}/services/{service}/chaos services serviceChaosExperimentStop
+		// ---
+		// Endpoint to end the fault injection experiment running on a service before its expiry
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200
+		//
+		{
+			"ServiceChaosExperimentStop",
+			"DELETE",
+			"/api/namespaces/{namespace}/services/{service}/chaos",
+			handlers.ServiceChaosExperimentStop,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/references services serviceReferences
+		// ---
+		// Endpoint to get the Istio objects referencing a service, with the JSON path of each reference
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioReferencesResponse
+		//
+		{
+			"ServiceReferences",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/references",
+			handlers.IstioReferences,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/events services serviceEvents
+		// ---
+		// Endpoint to get the recent events of a service and its endpoints, warnings first and aggregated by reason
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: eventsResponse
+		//
+		{
+			"ServiceEvents",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/events",
+			handlers.ServiceEvents,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/apps/{app}/spans traces appSpans
 		// ---
 		// Endpoint to get Jaeger spans for a given app
@@ -392,47 +828,219 @@ func NewRoutes() (r *Routes) {
 			handlers.AppSpans,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/spans traces workloadSpans
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/spans traces workloadSpans
+		// ---
+		// Endpoint to get Jaeger spans for a given workload
+		//
+		//		Produces:
+		//		- application/json
+		//
+		//		Schemes: http, https
+		//
+		// responses:
+		// 		500: internalError
+		//		200: spansResponse
+		{
+			"WorkloadSpans",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/spans",
+			handlers.WorkloadSpans,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/spans traces serviceSpans
+		// ---
+		// Endpoint to get Jaeger spans for a given service
+		//
+		//		Produces:
+		//		- application/json
+		//
+		//		Schemes: http, https
+		//
+		// responses:
+		// 		500: internalError
+		//		200: spansResponse
+		{
+			"ServiceSpans",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/spans",
+			handlers.ServiceSpans,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/traces traces appTraces
+		// ---
+		// Endpoint to get the traces of a given app
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: traceDetailsResponse
+		//
+		{
+			"AppTraces",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/traces",
+			handlers.AppTraces,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/traces traces serviceTraces
+		// ---
+		// Endpoint to get the traces of a given service
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: traceDetailsResponse
+		//
+		{
+			"ServiceTraces",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/traces",
+			handlers.ServiceTraces,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/traces traces workloadTraces
+		// ---
+		// Endpoint to get the traces of a given workload
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: traceDetailsResponse
+		//
+		{
+			"WorkloadTraces",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/traces",
+			handlers.WorkloadTraces,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/errortraces traces errorTraces
+		// ---
+		// Endpoint to get the number of traces in error for a given service
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: errorTracesResponse
+		//
+		{
+			"ErrorTraces",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/errortraces",
+			handlers.ErrorTraces,
+			true,
+		},
+		// swagger:route GET /traces/{traceID} traces traceDetails
+		// ---
+		// Endpoint to get a specific trace from ID
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: traceDetailsResponse
+		//
+		{
+			"TracesDetails",
+			"GET",
+			"/api/traces/{traceID}",
+			handlers.TraceDetails,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads workloads workloadList
+		// ---
+		// Endpoint to get the list of workloads for a namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: workloadListResponse
+		//
+		{
+			"WorkloadList",
+			"GET",
+			"/api/namespaces/{namespace}/workloads",
+			handlers.WorkloadList,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload} workloads workloadDetails
 		// ---
-		// Endpoint to get Jaeger spans for a given workload
+		// Endpoint to get the workload details
 		//
-		//		Produces:
-		//		- application/json
+		//     Produces:
+		//     - application/json
 		//
-		//		Schemes: http, https
+		//     Schemes: http, https
 		//
 		// responses:
-		// 		500: internalError
-		//		200: spansResponse
+		//      500: internalError
+		//      404: notFoundError
+		//      200: workloadDetails
+		//
 		{
-			"WorkloadSpans",
+			"WorkloadDetails",
 			"GET",
-			"/api/namespaces/{namespace}/workloads/{workload}/spans",
-			handlers.WorkloadSpans,
+			"/api/namespaces/{namespace}/workloads/{workload}",
+			handlers.WorkloadDetails,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/services/{service}/spans traces serviceSpans
+		// swagger:route PATCH /namespaces/{namespace}/workloads/{workload} workloads workloadUpdate
 		// ---
-		// Endpoint to get Jaeger spans for a given service
+		// Endpoint to update the Workload configuration using Json Merge Patch strategy.
 		//
-		//		Produces:
-		//		- application/json
+		//     Consumes:
+		//	   - application/json
 		//
-		//		Schemes: http, https
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
 		//
 		// responses:
-		// 		500: internalError
-		//		200: spansResponse
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: workloadDetails
+		//
 		{
-			"ServiceSpans",
-			"GET",
-			"/api/namespaces/{namespace}/services/{service}/spans",
-			handlers.ServiceSpans,
+			"WorkloadUpdate",
+			"PATCH",
+			"/api/namespaces/{namespace}/workloads/{workload}",
+			handlers.WorkloadUpdate,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/apps/{app}/traces traces appTraces
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/logs workloads workloadLogs
 		// ---
-		// Endpoint to get the traces of a given app
+		// Endpoint to get the logs of all the containers of the pods of a workload, the istio-proxy included,
+		// merged in time order and tagged with their pod and container
 		//
 		//     Produces:
 		//     - application/json
@@ -440,20 +1048,22 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      400: badRequestError
 		//      404: notFoundError
 		//      500: internalError
-		//      200: traceDetailsResponse
+		//      200: aggregatedLogResponse
 		//
 		{
-			"AppTraces",
+			"WorkloadLogs",
 			"GET",
-			"/api/namespaces/{namespace}/apps/{app}/traces",
-			handlers.AppTraces,
+			"/api/namespaces/{namespace}/workloads/{workload}/logs",
+			handlers.WorkloadLogs,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/services/{service}/traces traces serviceTraces
+		// swagger:route POST /namespaces/{namespace}/workloads/{workload}/restart workloads workloadRestart
 		// ---
-		// Endpoint to get the traces of a given service
+		// Endpoint to trigger a rolling restart of the pods of a Deployment, DeploymentConfig, StatefulSet or DaemonSet,
+		// as kubectl rollout restart does
 		//
 		//     Produces:
 		//     - application/json
@@ -461,20 +1071,25 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      400: badRequestError
 		//      404: notFoundError
 		//      500: internalError
-		//      200: traceDetailsResponse
+		//      200: workloadDetails
 		//
 		{
-			"ServiceTraces",
-			"GET",
-			"/api/namespaces/{namespace}/services/{service}/traces",
-			handlers.ServiceTraces,
+			"WorkloadRestart",
+			"POST",
+			"/api/namespaces/{namespace}/workloads/{workload}/restart",
+			handlers.WorkloadRestart,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/traces traces workloadTraces
+		// swagger:route PUT /namespaces/{namespace}/workloads/{workload}/scale workloads workloadScale
 		// ---
-		// Endpoint to get the traces of a given workload
+		// Endpoint to set the number of replicas of a Deployment, DeploymentConfig, StatefulSet, ReplicaSet,
+		// ReplicationController or Argo Rollout
+		//
+		//     Consumes:
+		//     - application/json
 		//
 		//     Produces:
 		//     - application/json
@@ -482,20 +1097,21 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      400: badRequestError
 		//      404: notFoundError
 		//      500: internalError
-		//      200: traceDetailsResponse
+		//      200: workloadDetails
 		//
 		{
-			"WorkloadTraces",
-			"GET",
-			"/api/namespaces/{namespace}/workloads/{workload}/traces",
-			handlers.WorkloadTraces,
+			"WorkloadScale",
+			"PUT",
+			"/api/namespaces/{namespace}/workloads/{workload}/scale",
+			handlers.WorkloadScale,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/apps/{app}/errortraces traces errorTraces
+		// swagger:route POST /namespaces/{namespace}/workloads/{workload}/pause workloads workloadPause
 		// ---
-		// Endpoint to get the number of traces in error for a given service
+		// Endpoint to pause the rollout of the changes of a Deployment, DeploymentConfig or Argo Rollout
 		//
 		//     Produces:
 		//     - application/json
@@ -503,20 +1119,21 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      400: badRequestError
 		//      404: notFoundError
 		//      500: internalError
-		//      200: errorTracesResponse
+		//      200: workloadDetails
 		//
 		{
-			"ErrorTraces",
-			"GET",
-			"/api/namespaces/{namespace}/apps/{app}/errortraces",
-			handlers.ErrorTraces,
+			"WorkloadPause",
+			"POST",
+			"/api/namespaces/{namespace}/workloads/{workload}/pause",
+			handlers.WorkloadPause,
 			true,
 		},
-		// swagger:route GET /traces/{traceID} traces traceDetails
+		// swagger:route POST /namespaces/{namespace}/workloads/{workload}/resume workloads workloadResume
 		// ---
-		// Endpoint to get a specific trace from ID
+		// Endpoint to resume the paused rollout of a Deployment, DeploymentConfig or Argo Rollout
 		//
 		//     Produces:
 		//     - application/json
@@ -524,20 +1141,22 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      400: badRequestError
 		//      404: notFoundError
 		//      500: internalError
-		//      200: traceDetailsResponse
+		//      200: workloadDetails
 		//
 		{
-			"TracesDetails",
-			"GET",
-			"/api/traces/{traceID}",
-			handlers.TraceDetails,
+			"WorkloadResume",
+			"POST",
+			"/api/namespaces/{namespace}/workloads/{workload}/resume",
+			handlers.WorkloadResume,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/workloads workloads workloadList
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/rollout workloads workloadRollout
 		// ---
-		// Endpoint to get the list of workloads for a namespace
+		// Endpoint to get the progress of the rollout of a Deployment, DeploymentConfig, StatefulSet or DaemonSet,
+		// and the history of its revisions
 		//
 		//     Produces:
 		//     - application/json
@@ -545,19 +1164,21 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
 		//      500: internalError
-		//      200: workloadListResponse
+		//      200: workloadRolloutResponse
 		//
 		{
-			"WorkloadList",
+			"WorkloadRollout",
 			"GET",
-			"/api/namespaces/{namespace}/workloads",
-			handlers.WorkloadList,
+			"/api/namespaces/{namespace}/workloads/{workload}/rollout",
+			handlers.WorkloadRollout,
 			true,
 		},
-		// swagger:route GET /namespaces/{namespace}/workloads/{workload} workloads workloadDetails
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/events workloads workloadEvents
 		// ---
-		// Endpoint to get the workload details
+		// Endpoint to get the recent events of a workload, its ReplicaSets and its pods, warnings first and aggregated by reason
 		//
 		//     Produces:
 		//     - application/json
@@ -565,23 +1186,20 @@ func NewRoutes() (r *Routes) {
 		//     Schemes: http, https
 		//
 		// responses:
-		//      500: internalError
 		//      404: notFoundError
-		//      200: workloadDetails
+		//      500: internalError
+		//      200: eventsResponse
 		//
 		{
-			"WorkloadDetails",
+			"WorkloadEvents",
 			"GET",
-			"/api/namespaces/{namespace}/workloads/{workload}",
-			handlers.WorkloadDetails,
+			"/api/namespaces/{namespace}/workloads/{workload}/events",
+			handlers.WorkloadEvents,
 			true,
 		},
-		// swagger:route PATCH /namespaces/{namespace}/workloads/{workload} workloads workloadUpdate
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/references workloads workloadReferences
 		// ---
-		// Endpoint to update the Workload configuration using Json Merge Patch strategy.
-		//
-		//     Consumes:
-		//	   - application/json
+		// Endpoint to get the Istio objects selecting a workload, with the JSON path of each reference
 		//
 		//     Produces:
 		//     - application/json
@@ -592,13 +1210,13 @@ func NewRoutes() (r *Routes) {
 		//      400: badRequestError
 		//      404: notFoundError
 		//      500: internalError
-		//      200: workloadDetails
+		//      200: istioReferencesResponse
 		//
 		{
-			"WorkloadUpdate",
-			"PATCH",
-			"/api/namespaces/{namespace}/workloads/{workload}",
-			handlers.WorkloadUpdate,
+			"WorkloadReferences",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/references",
+			handlers.IstioReferences,
 			true,
 		},
 		// swagger:route GET /namespaces/{namespace}/apps apps appList
@@ -642,6 +1260,29 @@ func NewRoutes() (r *Routes) {
 			handlers.AppDetails,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/logs apps appLogs
+		// ---
+		// Endpoint to get the logs of all the containers of the pods of an app, the istio-proxy included,
+		// merged in time order and tagged with their pod and container
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: aggregatedLogResponse
+		//
+		{
+			"AppLogs",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/logs",
+			handlers.AppLogs,
+			true,
+		},
 		// swagger:route GET /namespaces namespaces namespaceList
 		// ---
 		// Endpoint to get the list of the available namespaces
@@ -752,7 +1393,7 @@ func NewRoutes() (r *Routes) {
 		},
 		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/metrics workloads workloadMetrics
 		// ---
-		// Endpoint to fetch metrics to be displayed, related to a single workload
+		// Endpoint to fetch metrics to be displayed, related to a single workload, including the CPU and memory of its containers
 		//
 		//     Produces:
 		//     - application/json
@@ -761,6 +1402,7 @@ func NewRoutes() (r *Routes) {
 		//
 		// responses:
 		//      400: badRequestError
+		//      404: notFoundError
 		//      503: serviceUnavailableError
 		//      200: metricsResponse
 		//
@@ -981,6 +1623,29 @@ func NewRoutes() (r *Routes) {
 			handlers.NamespaceValidationSummary,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/validations/dryrun validations namespaceValidationsDryRun
+		// ---
+		// Validate the namespace as if a proposed create, update or delete of an Istio object was applied, without applying it
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: dryRunValidationsResponse
+		//      400: badRequestError
+		//      404: notFoundError
+		//      409: conflictError
+		//      500: internalError
+		//
+		{
+			"NamespaceValidationsDryRun",
+			"POST",
+			"/api/namespaces/{namespace}/validations/dryrun",
+			handlers.NamespaceValidationsDryRun,
+			true,
+		},
 		// swagger:route GET /mesh/tls tls meshTls
 		// ---
 		// Get TLS status for the whole mesh
@@ -1002,6 +1667,27 @@ func NewRoutes() (r *Routes) {
 			handlers.MeshTls,
 			true,
 		},
+		// swagger:route GET /mesh/validations validations meshValidations
+		// ---
+		// Get validations, and its summary, for all the namespaces of the mesh
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: meshValidationsResponse
+		//      400: badRequestError
+		//      500: internalError
+		//
+		{
+			"MeshValidations",
+			"GET",
+			"/api/mesh/validations",
+			handlers.MeshValidations,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/tls tls namespaceTls
 		// ---
 		// Get TLS status for the given namespace
@@ -1258,6 +1944,27 @@ func NewRoutes() (r *Routes) {
 			handlers.PodDetails,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/events pods podEvents
+		// ---
+		// Endpoint to get the recent events of a pod, warnings first and aggregated by reason
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: eventsResponse
+		//
+		{
+			"PodEvents",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/events",
+			handlers.PodEvents,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/pods/{pod}/logs pods podLogs
 		// ---
 		// Endpoint to get pod logs
@@ -1279,6 +1986,108 @@ func NewRoutes() (r *Routes) {
 			handlers.PodLogs,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/logs/stream pods podLogsStream
+		// ---
+		// Endpoint to follow the logs of a pod container, sent as server-sent events: a "log" event with each new entry,
+		// then an "end" event when the container terminates or the max duration expires. Over HTTP/2 the stream can't
+		// outlast the 30s write timeout of the server, so it ends after 25s: clients continue with a new stream from
+		// the time of the last entry.
+		//
+		//     Produces:
+		//     - text/event-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: podLogsStreamResponse
+		//
+		{
+			"PodLogsStream",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/logs/stream",
+			handlers.PodLogsStream,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/config_dump pods podConfigDump
+		// ---
+		// Endpoint to get the configuration received by the Envoy proxy of a pod: bootstrap, clusters with their endpoints,
+		// listeners and routes. The Envoy admin interface is reached through the pods exec subresource, so the user must be
+		// allowed to create pods/exec in the namespace.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: envoyConfigDumpResponse
+		//
+		{
+			"PodConfigDump",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/config_dump",
+			handlers.PodConfigDump,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/logging pods podProxyLogging
+		// ---
+		// Endpoint to get the log level of each logger of the Envoy proxy of a pod. The Envoy admin interface is reached
+		// through the pods exec subresource, so the user must be allowed to create pods/exec in the namespace.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: envoyLogLevelsResponse
+		//
+		{
+			"PodProxyLogging",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/logging",
+			handlers.PodProxyLogging,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/pods/{pod}/logging pods podProxyLoggingUpdate
+		// ---
+		// Endpoint to change the log level of the Envoy proxy of a pod, for all its loggers or for one of them,
+		// optionally restoring the previous levels after a duration. The user must be allowed to create pods/exec in the
+		// namespace, and to patch the pod to restore the levels. The levels are restored by the Kiali service account,
+		// which then needs the pods/exec create and pods patch permissions in the accessible namespaces.
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      403: forbiddenError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: envoyLogLevelsResponse
+		//
+		{
+			"PodProxyLoggingUpdate",
+			"POST",
+			"/api/namespaces/{namespace}/pods/{pod}/logging",
+			handlers.PodProxyLoggingUpdate,
+			true,
+		},
 		// swagger:route GET /iter8
 		// ---
 		// Endpoint to check if iter8 adapter is present in the cluster and if user can write adapter config
@@ -87,6 +87,12 @@ func (s *Server) Start() {
 	if conf.Server.MetricsEnabled {
 		StartMetricsServer()
 	}
+
+	// End the chaos experiments started before a restart when they expire
+	go business.StartChaosExperiments()
+
+	// Reset the proxy log levels changed before a restart when their reset time comes
+	go business.StartProxyLogLevelResets()
 }
 
 // Stop the HTTP server
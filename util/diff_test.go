@@ -0,0 +1,20 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	assert := assert.New(t)
+
+	from := "kind: Sidecar\nspec:\n  egress:\n  - hosts:\n    - ./*\n"
+	to := "kind: Sidecar\nspec:\n  egress:\n  - hosts:\n    - istio-system/*\n    - ./reviews\n"
+	assert.Equal(" kind: Sidecar\n spec:\n   egress:\n   - hosts:\n-    - ./*\n+    - istio-system/*\n+    - ./reviews\n", DiffLines(from, to))
+
+	assert.Equal("+a\n+b\n", DiffLines("", "a\nb"))
+	assert.Equal("-a\n", DiffLines("a\n", ""))
+	assert.Equal(" a\n", DiffLines("a", "a"))
+	assert.Equal("", DiffLines("", ""))
+}
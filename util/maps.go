@@ -1,5 +1,7 @@
 package util
 
+import "reflect"
+
 func RemoveNilValues(root interface{}) {
 	if mRoot, isMap := root.(map[string]interface{}); isMap {
 		for k, v := range mRoot {
@@ -12,3 +14,85 @@ func RemoveNilValues(root interface{}) {
 		}
 	}
 }
+
+// MergePatch applies a JSON merge patch (RFC 7386) to a generic JSON document and returns the result.
+// The target is not modified, maps are copied where the patch changes them.
+func MergePatch(target, patch interface{}) interface{} {
+	mPatch, isMap := patch.(map[string]interface{})
+	if !isMap {
+		return patch
+	}
+	result := make(map[string]interface{})
+	if mTarget, isTargetMap := target.(map[string]interface{}); isTargetMap {
+		for k, v := range mTarget {
+			result[k] = v
+		}
+	}
+	for k, v := range mPatch {
+		if v == nil {
+			delete(result, k)
+		} else {
+			result[k] = MergePatch(result[k], v)
+		}
+	}
+	return result
+}
+
+// CreateMergePatch returns the JSON merge patch (RFC 7386) that transforms the generic JSON document from into to.
+// Keys removed from maps are set to nil in the patch, while other values are replaced as a whole.
+func CreateMergePatch(from, to interface{}) interface{} {
+	mFrom, isFromMap := from.(map[string]interface{})
+	mTo, isToMap := to.(map[string]interface{})
+	if !isFromMap || !isToMap {
+		return to
+	}
+	patch := make(map[string]interface{})
+	for k := range mFrom {
+		if _, found := mTo[k]; !found {
+			patch[k] = nil
+		}
+	}
+	for k, v := range mTo {
+		if fromValue, found := mFrom[k]; !found {
+			patch[k] = v
+		} else if !reflect.DeepEqual(fromValue, v) {
+			patch[k] = CreateMergePatch(fromValue, v)
+		}
+	}
+	return patch
+}
+
+// CreateThreeWayMergePatch returns the JSON merge patch (RFC 7386) that updates the current document to the modified
+// one, as kubectl apply does. Keys are only removed when they were in the original document the modified one comes
+// from, so keys set by others in the current document are kept.
+func CreateThreeWayMergePatch(original, modified, current interface{}) interface{} {
+	mModified, isModifiedMap := modified.(map[string]interface{})
+	mCurrent, isCurrentMap := current.(map[string]interface{})
+	if !isModifiedMap || !isCurrentMap {
+		return modified
+	}
+	mOriginal, _ := original.(map[string]interface{})
+
+	patch := make(map[string]interface{})
+	for k, v := range mModified {
+		currentValue, found := mCurrent[k]
+		if !found {
+			patch[k] = v
+		} else if !reflect.DeepEqual(currentValue, v) {
+			p := CreateThreeWayMergePatch(mOriginal[k], v, currentValue)
+			// Maps differing only in keys set by others don't need a patch
+			if mp, isMap := p.(map[string]interface{}); !isMap || len(mp) > 0 {
+				patch[k] = p
+			}
+		}
+	}
+	for k := range mOriginal {
+		if _, found := mModified[k]; found {
+			continue
+		}
+		if _, found := mCurrent[k]; found {
+			patch[k] = nil
+		}
+	}
+	return patch
+}
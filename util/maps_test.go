@@ -39,3 +39,97 @@ func TestRemoveNilValues(t *testing.T) {
 	assert.True(t, k3k1)
 	assert.True(t, k3k3k1)
 }
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"a": "b",
+		"c": map[string]interface{}{
+			"d": "e",
+			"f": "g",
+		},
+		"l": []interface{}{"x", "y"},
+	}
+	patch := map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{
+			"f": nil,
+		},
+		"l": []interface{}{"w"},
+		"n": map[string]interface{}{"m": nil, "o": 1},
+	}
+
+	result := MergePatch(target, patch).(map[string]interface{})
+
+	assert.Equal(t, "z", result["a"])
+	assert.Equal(t, map[string]interface{}{"d": "e"}, result["c"])
+	assert.Equal(t, []interface{}{"w"}, result["l"])
+	assert.Equal(t, map[string]interface{}{"o": 1}, result["n"])
+
+	// Target is untouched
+	assert.Equal(t, "b", target["a"])
+	assert.Equal(t, "g", target["c"].(map[string]interface{})["f"])
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	from := map[string]interface{}{
+		"a": "b",
+		"c": map[string]interface{}{
+			"d": "e",
+			"f": "g",
+		},
+		"l": []interface{}{"x", "y"},
+		"u": "v",
+	}
+	to := map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{
+			"d": "e",
+		},
+		"l": []interface{}{"w"},
+		"u": "v",
+		"n": map[string]interface{}{"o": 1},
+	}
+
+	patch := CreateMergePatch(from, to).(map[string]interface{})
+
+	assert.Equal(t, map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{"f": nil},
+		"l": []interface{}{"w"},
+		"n": map[string]interface{}{"o": 1},
+	}, patch)
+	assert.Equal(t, to, MergePatch(from, patch))
+}
+
+func TestCreateThreeWayMergePatch(t *testing.T) {
+	original := map[string]interface{}{
+		"a": "b",
+		"c": map[string]interface{}{"d": "e"},
+	}
+	current := map[string]interface{}{
+		"a": "b",
+		"c": map[string]interface{}{"d": "e", "x": "set by others"},
+		"y": "set by others",
+	}
+	modified := map[string]interface{}{
+		"c": map[string]interface{}{"d": "f"},
+		"n": "o",
+	}
+
+	patch := CreateThreeWayMergePatch(original, modified, current).(map[string]interface{})
+
+	assert.Equal(t, map[string]interface{}{
+		"a": nil,
+		"c": map[string]interface{}{"d": "f"},
+		"n": "o",
+	}, patch)
+	assert.Equal(t, map[string]interface{}{
+		"c": map[string]interface{}{"d": "f", "x": "set by others"},
+		"n": "o",
+		"y": "set by others",
+	}, MergePatch(current, patch))
+
+	// Without original, keys not modified are removed
+	patch = CreateThreeWayMergePatch(current, modified, current).(map[string]interface{})
+	assert.Equal(t, modified, MergePatch(current, patch))
+}
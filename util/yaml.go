@@ -0,0 +1,90 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// MarshalYAML returns the YAML of the payload using the JSON names of its fields, as in Kubernetes manifests.
+// Null values are omitted.
+func MarshalYAML(payload interface{}) ([]byte, error) {
+	var generic interface{}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+	RemoveNilValues(generic)
+	return yaml.Marshal(generic)
+}
+
+// ParseManifests returns the objects of a multi-document YAML body, as generic JSON documents.
+// As JSON is valid YAML, a JSON object or list is parsed too. Lists, and List objects, are expanded into their items.
+func ParseManifests(body []byte) ([]map[string]interface{}, error) {
+	objects := []map[string]interface{}{}
+	decoder := yaml.NewDecoder(bytes.NewReader(body))
+	for {
+		var doc interface{}
+		err := decoder.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if objects, err = appendManifests(objects, convertYAML(doc)); err != nil {
+			return nil, err
+		}
+	}
+	return objects, nil
+}
+
+func appendManifests(objects []map[string]interface{}, doc interface{}) ([]map[string]interface{}, error) {
+	switch d := doc.(type) {
+	case nil:
+		// Empty documents are ignored
+	case []interface{}:
+		for _, item := range d {
+			var err error
+			if objects, err = appendManifests(objects, item); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		if kind, _ := d["kind"].(string); strings.HasSuffix(kind, "List") {
+			if items, isList := d["items"].([]interface{}); isList {
+				return appendManifests(objects, items)
+			}
+		}
+		objects = append(objects, d)
+	default:
+		return nil, fmt.Errorf("manifest is not an object: %v", doc)
+	}
+	return objects, nil
+}
+
+// convertYAML converts the maps decoded from YAML into maps with string keys, as decoded from JSON
+func convertYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = convertYAML(item)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, item := range v {
+			l[i] = convertYAML(item)
+		}
+		return l
+	}
+	return value
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifests(t *testing.T) {
+	assert := assert.New(t)
+
+	objects, err := ParseManifests([]byte(`
+kind: DestinationRule
+metadata:
+  name: reviews
+spec:
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+---
+---
+kind: VirtualService
+metadata:
+  name: reviews
+`))
+	assert.NoError(err)
+	assert.Len(objects, 2)
+	assert.Equal("DestinationRule", objects[0]["kind"])
+	assert.Equal(map[string]interface{}{"version": "v1"}, objects[0]["spec"].(map[string]interface{})["subsets"].([]interface{})[0].(map[string]interface{})["labels"])
+	assert.Equal("VirtualService", objects[1]["kind"])
+
+	objects, err = ParseManifests([]byte(`[{"kind": "Gateway", "spec": {"servers": [{"port": {"number": 80}}]}}, {"kind": "List", "items": [{"kind": "Sidecar"}]}]`))
+	assert.NoError(err)
+	assert.Len(objects, 2)
+	assert.Equal("Gateway", objects[0]["kind"])
+	assert.Equal("Sidecar", objects[1]["kind"])
+
+	_, err = ParseManifests([]byte(`- a`))
+	assert.Error(err)
+}